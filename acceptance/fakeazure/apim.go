@@ -0,0 +1,277 @@
+package fakeazure
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
+)
+
+type namedValue struct {
+	properties armapimanagement.NamedValueContractProperties
+}
+
+type apimSubscription struct {
+	properties armapimanagement.SubscriptionContractProperties
+}
+
+func (s *Server) serveResourceManager(w http.ResponseWriter, r *http.Request) {
+	if !isAuthorized(r) {
+		writeError(w, http.StatusUnauthorized, "AuthenticationFailed", "Authentication failed. The 'Authorization' header is missing.")
+		return
+	}
+
+	// /subscriptions/{s}/resourceGroups/{rg}/providers/Microsoft.ApiManagement/service/{svc}/{type}/{id}[/{operation}]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(parts) < 10 || parts[2] != "resourceGroups" || parts[4] != "providers" ||
+		!strings.EqualFold(parts[5], "Microsoft.ApiManagement") || parts[6] != "service" {
+		writeError(w, http.StatusNotFound, "NotFound", "unsupported resource manager path")
+		return
+	}
+
+	resourceId := strings.Join(parts[:10], "/")
+	objectType := parts[8]
+	operation := ""
+	if len(parts) > 10 {
+		operation = parts[10]
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch objectType {
+	case "namedValues":
+		s.serveNamedValue(w, r, "/"+resourceId, parts[9], operation)
+	case "subscriptions":
+		s.serveApimSubscription(w, r, "/"+resourceId, parts[9], operation)
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("resource type %s is not supported", objectType))
+	}
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Named values
+
+func (s *Server) serveNamedValue(w http.ResponseWriter, r *http.Request, resourceId, name, operation string) {
+	nv := s.namedValues[resourceId]
+
+	switch {
+	case r.Method == http.MethodGet && len(operation) == 0:
+		if nv == nil {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("Named value %s not found", name))
+			return
+		}
+		writeJson(w, http.StatusOK, namedValueOf(resourceId, name, nv))
+
+	case r.Method == http.MethodPost && operation == "listValue":
+		if nv == nil {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("Named value %s not found", name))
+			return
+		}
+		writeJson(w, http.StatusOK, armapimanagement.NamedValueSecretContract{Value: nv.properties.Value})
+
+	case r.Method == http.MethodPut && len(operation) == 0:
+		params := armapimanagement.NamedValueCreateContract{}
+		if err := readJson(r, &params); err != nil || params.Properties == nil {
+			writeError(w, http.StatusBadRequest, "ValidationError", "properties are required")
+			return
+		}
+
+		status := http.StatusOK
+		if nv == nil {
+			status = http.StatusCreated
+		}
+
+		nv = &namedValue{properties: armapimanagement.NamedValueContractProperties{
+			DisplayName: params.Properties.DisplayName,
+			Secret:      params.Properties.Secret,
+			Tags:        params.Properties.Tags,
+			Value:       params.Properties.Value,
+		}}
+		s.namedValues[resourceId] = nv
+
+		writeJson(w, status, namedValueOf(resourceId, name, nv))
+
+	case r.Method == http.MethodPatch && len(operation) == 0:
+		if nv == nil {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("Named value %s not found", name))
+			return
+		}
+
+		params := armapimanagement.NamedValueUpdateParameters{}
+		if err := readJson(r, &params); err != nil {
+			writeError(w, http.StatusBadRequest, "ValidationError", err.Error())
+			return
+		}
+		if p := params.Properties; p != nil {
+			nv.properties.DisplayName = valueOr(p.DisplayName, nv.properties.DisplayName)
+			nv.properties.Secret = valueOr(p.Secret, nv.properties.Secret)
+			nv.properties.Value = valueOr(p.Value, nv.properties.Value)
+			if p.Tags != nil {
+				nv.properties.Tags = p.Tags
+			}
+		}
+
+		writeJson(w, http.StatusOK, namedValueOf(resourceId, name, nv))
+
+	case r.Method == http.MethodDelete && len(operation) == 0:
+		if nv == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		delete(s.namedValues, resourceId)
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "operation is not supported by the fake")
+	}
+}
+
+// namedValueOf the named value as API management returns it: the value of a secret named value is returned only
+// by the listValue operation.
+func namedValueOf(resourceId, name string, nv *namedValue) armapimanagement.NamedValueContract {
+	props := nv.properties
+	if props.Secret != nil && *props.Secret {
+		props.Value = nil
+	}
+
+	return armapimanagement.NamedValueContract{
+		ID:         to.Ptr(resourceId),
+		Name:       to.Ptr(name),
+		Type:       to.Ptr("Microsoft.ApiManagement/service/namedValues"),
+		Properties: &props,
+	}
+}
+
+// NamedValue the value of the named value; false where the service doesn't have the named value.
+func (s *Server) NamedValue(subscriptionId, resourceGroup, serviceName, name string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if nv := s.namedValues[apimResourceId(subscriptionId, resourceGroup, serviceName, "namedValues", name)]; nv != nil && nv.properties.Value != nil {
+		return *nv.properties.Value, true
+	}
+	return "", false
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Subscriptions
+
+func (s *Server) serveApimSubscription(w http.ResponseWriter, r *http.Request, resourceId, sid, operation string) {
+	sub := s.subscriptions[resourceId]
+
+	switch {
+	case r.Method == http.MethodGet && len(operation) == 0:
+		if sub == nil {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("Subscription %s not found", sid))
+			return
+		}
+		writeJson(w, http.StatusOK, subscriptionOf(resourceId, sid, sub))
+
+	case r.Method == http.MethodPost && operation == "listSecrets":
+		if sub == nil {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("Subscription %s not found", sid))
+			return
+		}
+		writeJson(w, http.StatusOK, armapimanagement.SubscriptionKeysContract{
+			PrimaryKey:   sub.properties.PrimaryKey,
+			SecondaryKey: sub.properties.SecondaryKey,
+		})
+
+	case r.Method == http.MethodPut && len(operation) == 0:
+		params := armapimanagement.SubscriptionCreateParameters{}
+		if err := readJson(r, &params); err != nil || params.Properties == nil {
+			writeError(w, http.StatusBadRequest, "ValidationError", "properties are required")
+			return
+		}
+
+		status := http.StatusOK
+		if sub == nil {
+			status = http.StatusCreated
+		}
+
+		p := params.Properties
+		sub = &apimSubscription{properties: armapimanagement.SubscriptionContractProperties{
+			DisplayName:  p.DisplayName,
+			Scope:        p.Scope,
+			AllowTracing: p.AllowTracing,
+			OwnerID:      p.OwnerID,
+			PrimaryKey:   valueOr(p.PrimaryKey, to.Ptr(newVersion())),
+			SecondaryKey: valueOr(p.SecondaryKey, to.Ptr(newVersion())),
+			State:        valueOr(p.State, to.Ptr(armapimanagement.SubscriptionStateActive)),
+			CreatedDate:  to.Ptr(time.Now().UTC().Truncate(time.Second)),
+		}}
+		s.subscriptions[resourceId] = sub
+
+		writeJson(w, status, subscriptionOf(resourceId, sid, sub))
+
+	case r.Method == http.MethodPatch && len(operation) == 0:
+		if sub == nil {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", fmt.Sprintf("Subscription %s not found", sid))
+			return
+		}
+
+		params := armapimanagement.SubscriptionUpdateParameters{}
+		if err := readJson(r, &params); err != nil {
+			writeError(w, http.StatusBadRequest, "ValidationError", err.Error())
+			return
+		}
+		if p := params.Properties; p != nil {
+			sub.properties.DisplayName = valueOr(p.DisplayName, sub.properties.DisplayName)
+			sub.properties.Scope = valueOr(p.Scope, sub.properties.Scope)
+			sub.properties.AllowTracing = valueOr(p.AllowTracing, sub.properties.AllowTracing)
+			sub.properties.OwnerID = valueOr(p.OwnerID, sub.properties.OwnerID)
+			sub.properties.PrimaryKey = valueOr(p.PrimaryKey, sub.properties.PrimaryKey)
+			sub.properties.SecondaryKey = valueOr(p.SecondaryKey, sub.properties.SecondaryKey)
+			sub.properties.State = valueOr(p.State, sub.properties.State)
+		}
+
+		writeJson(w, http.StatusOK, subscriptionOf(resourceId, sid, sub))
+
+	case r.Method == http.MethodDelete && len(operation) == 0:
+		if sub == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		delete(s.subscriptions, resourceId)
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "operation is not supported by the fake")
+	}
+}
+
+// subscriptionOf the subscription as API management returns it: the keys are returned only by the
+// listSecrets operation.
+func subscriptionOf(resourceId, sid string, sub *apimSubscription) armapimanagement.SubscriptionContract {
+	props := sub.properties
+	props.PrimaryKey = nil
+	props.SecondaryKey = nil
+
+	return armapimanagement.SubscriptionContract{
+		ID:         to.Ptr(resourceId),
+		Name:       to.Ptr(sid),
+		Type:       to.Ptr("Microsoft.ApiManagement/service/subscriptions"),
+		Properties: &props,
+	}
+}
+
+// SubscriptionKeys the primary and the secondary key of the API management subscription; false where the
+// service doesn't have the subscription.
+func (s *Server) SubscriptionKeys(subscriptionId, resourceGroup, serviceName, sid string) (string, string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if sub := s.subscriptions[apimResourceId(subscriptionId, resourceGroup, serviceName, "subscriptions", sid)]; sub != nil {
+		return *sub.properties.PrimaryKey, *sub.properties.SecondaryKey, true
+	}
+	return "", "", false
+}
+
+func apimResourceId(subscriptionId, resourceGroup, serviceName, objectType, name string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/%s/%s",
+		subscriptionId, resourceGroup, serviceName, objectType, name)
+}
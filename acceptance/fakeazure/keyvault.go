@@ -0,0 +1,543 @@
+package fakeazure
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+type secretVersion struct {
+	value       string
+	contentType *string
+	attributes  azsecrets.SecretAttributes
+	tags        map[string]*string
+}
+
+type keyVersion struct {
+	key        azkeys.JSONWebKey
+	privateKey *rsa.PrivateKey
+	attributes azkeys.KeyAttributes
+	tags       map[string]*string
+}
+
+type certificateVersion struct {
+	cer        []byte
+	policy     *azcertificates.CertificatePolicy
+	attributes azcertificates.CertificateAttributes
+	tags       map[string]*string
+}
+
+// objectId the identifier of the Key Vault object. The identifier uses the public cloud domain irrespective of
+// the URL of the server, as the provider derives the vault name from the host name of the object identifier.
+func objectId(vaultName, objectType, name, version string) string {
+	return fmt.Sprintf("https://%s.vault.azure.net/%s/%s/%s", vaultName, objectType, name, version)
+}
+
+func objectKey(vaultName, name string) string {
+	return vaultName + "/" + name
+}
+
+func (s *Server) serveKeyVault(w http.ResponseWriter, r *http.Request) {
+	if !isAuthorized(r) {
+		// Key Vault clients authenticate only after receiving the challenge.
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf("Bearer authorization=\"https://login.microsoftonline.com/%s\", resource=\"https://vault.azure.net\"", s.TenantId))
+		writeError(w, http.StatusUnauthorized, "Unauthorized", "AKV10000: Request is missing a Bearer or PoP token.")
+		return
+	}
+
+	// /vaults/{vault}/{type}/{name}[/{version}[/{operation}]]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/vaults/"), "/")
+	if len(parts) < 3 {
+		writeError(w, http.StatusNotFound, "NotFound", "unsupported Key Vault path")
+		return
+	}
+
+	vaultName, objectType, name := parts[0], parts[1], parts[2]
+	rest := parts[3:]
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch objectType {
+	case "secrets":
+		s.serveSecret(w, r, vaultName, name, rest)
+	case "keys":
+		s.serveKey(w, r, vaultName, name, rest)
+	case "certificates":
+		s.serveCertificate(w, r, vaultName, name, rest)
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("object type %s is not supported", objectType))
+	}
+}
+
+func versionOf(rest []string) string {
+	if len(rest) > 0 {
+		return rest[0]
+	}
+	return ""
+}
+
+func stampCreated(enabled **bool, created **time.Time, updated **time.Time) {
+	now := time.Now().Truncate(time.Second)
+	if *enabled == nil {
+		*enabled = to.Ptr(true)
+	}
+	*created = &now
+	*updated = &now
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Secrets
+
+func (s *Server) serveSecret(w http.ResponseWriter, r *http.Request, vaultName, name string, rest []string) {
+	key := objectKey(vaultName, name)
+
+	switch {
+	case r.Method == http.MethodPut && len(rest) == 0:
+		params := azsecrets.SetSecretParameters{}
+		if err := readJson(r, &params); err != nil || params.Value == nil {
+			writeError(w, http.StatusBadRequest, "BadParameter", "secret value is required")
+			return
+		}
+
+		ver := &secretVersion{value: *params.Value, contentType: params.ContentType, tags: params.Tags}
+		if params.SecretAttributes != nil {
+			ver.attributes = *params.SecretAttributes
+		}
+		stampCreated(&ver.attributes.Enabled, &ver.attributes.Created, &ver.attributes.Updated)
+
+		if s.secrets[key] == nil {
+			s.secrets[key] = &versionedObject[secretVersion]{versions: map[string]*secretVersion{}}
+		}
+		version := newVersion()
+		s.secrets[key].add(version, ver)
+
+		writeJson(w, http.StatusOK, secretOf(vaultName, name, version, ver, true))
+
+	case r.Method == http.MethodGet && len(rest) <= 1:
+		ver, version := s.secrets[key].get(versionOf(rest))
+		if ver == nil {
+			writeError(w, http.StatusNotFound, "SecretNotFound", fmt.Sprintf("A secret with (name/id) %s was not found in this key vault", name))
+			return
+		}
+		writeJson(w, http.StatusOK, secretOf(vaultName, name, version, ver, true))
+
+	case r.Method == http.MethodPatch && len(rest) == 1:
+		ver, version := s.secrets[key].get(rest[0])
+		if ver == nil {
+			writeError(w, http.StatusNotFound, "SecretNotFound", fmt.Sprintf("A secret with (name/id) %s was not found in this key vault", name))
+			return
+		}
+
+		params := azsecrets.UpdateSecretPropertiesParameters{}
+		if err := readJson(r, &params); err != nil {
+			writeError(w, http.StatusBadRequest, "BadParameter", err.Error())
+			return
+		}
+		if params.ContentType != nil {
+			ver.contentType = params.ContentType
+		}
+		if params.Tags != nil {
+			ver.tags = params.Tags
+		}
+		if attr := params.SecretAttributes; attr != nil {
+			ver.attributes.Enabled = valueOr(attr.Enabled, ver.attributes.Enabled)
+			ver.attributes.NotBefore = attr.NotBefore
+			ver.attributes.Expires = attr.Expires
+		}
+		ver.attributes.Updated = to.Ptr(time.Now().Truncate(time.Second))
+
+		writeJson(w, http.StatusOK, secretOf(vaultName, name, version, ver, false))
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "operation is not supported by the fake")
+	}
+}
+
+func secretOf(vaultName, name, version string, ver *secretVersion, withValue bool) azsecrets.Secret {
+	rv := azsecrets.Secret{
+		ID:          to.Ptr(azsecrets.ID(objectId(vaultName, "secrets", name, version))),
+		ContentType: ver.contentType,
+		Attributes:  to.Ptr(ver.attributes),
+		Tags:        ver.tags,
+	}
+	if withValue {
+		rv.Value = to.Ptr(ver.value)
+	}
+	return rv
+}
+
+func valueOr[T any](v *T, def *T) *T {
+	if v != nil {
+		return v
+	}
+	return def
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Keys
+
+func (s *Server) serveKey(w http.ResponseWriter, r *http.Request, vaultName, name string, rest []string) {
+	key := objectKey(vaultName, name)
+
+	switch {
+	case r.Method == http.MethodPut && len(rest) == 0:
+		params := azkeys.ImportKeyParameters{}
+		if err := readJson(r, &params); err != nil || params.Key == nil {
+			writeError(w, http.StatusBadRequest, "BadParameter", "key is required")
+			return
+		}
+
+		ver := &keyVersion{key: *params.Key, tags: params.Tags}
+		if params.KeyAttributes != nil {
+			ver.attributes = *params.KeyAttributes
+		}
+		ver.privateKey = rsaPrivateKeyOf(params.Key)
+
+		writeJson(w, http.StatusOK, s.addKeyVersion(vaultName, name, ver))
+
+	case r.Method == http.MethodGet && len(rest) <= 1:
+		ver, version := s.keys[key].get(versionOf(rest))
+		if ver == nil {
+			writeError(w, http.StatusNotFound, "KeyNotFound", fmt.Sprintf("A key with (name/id) %s was not found in this key vault", name))
+			return
+		}
+		writeJson(w, http.StatusOK, keyBundleOf(vaultName, name, version, ver))
+
+	case r.Method == http.MethodPatch && len(rest) <= 1:
+		ver, version := s.keys[key].get(versionOf(rest))
+		if ver == nil {
+			writeError(w, http.StatusNotFound, "KeyNotFound", fmt.Sprintf("A key with (name/id) %s was not found in this key vault", name))
+			return
+		}
+
+		params := azkeys.UpdateKeyParameters{}
+		if err := readJson(r, &params); err != nil {
+			writeError(w, http.StatusBadRequest, "BadParameter", err.Error())
+			return
+		}
+		if params.Tags != nil {
+			ver.tags = params.Tags
+		}
+		if params.KeyOps != nil {
+			ver.key.KeyOps = params.KeyOps
+		}
+		if attr := params.KeyAttributes; attr != nil {
+			ver.attributes.Enabled = valueOr(attr.Enabled, ver.attributes.Enabled)
+			ver.attributes.NotBefore = attr.NotBefore
+			ver.attributes.Expires = attr.Expires
+		}
+		ver.attributes.Updated = to.Ptr(time.Now().Truncate(time.Second))
+
+		writeJson(w, http.StatusOK, keyBundleOf(vaultName, name, version, ver))
+
+	case r.Method == http.MethodPost && len(rest) == 1 && rest[0] == "create":
+		params := azkeys.CreateKeyParameters{}
+		if err := readJson(r, &params); err != nil || params.Kty == nil {
+			writeError(w, http.StatusBadRequest, "BadParameter", "key type is required")
+			return
+		} else if *params.Kty != azkeys.KeyTypeRSA && *params.Kty != azkeys.KeyTypeRSAHSM {
+			writeError(w, http.StatusBadRequest, "BadParameter", "only RSA keys can be created by the fake")
+			return
+		}
+
+		keySize := 2048
+		if params.KeySize != nil {
+			keySize = int(*params.KeySize)
+		}
+		privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "BadParameter", err.Error())
+			return
+		}
+
+		ver := &keyVersion{
+			key: azkeys.JSONWebKey{
+				Kty:    params.Kty,
+				KeyOps: params.KeyOps,
+				N:      privateKey.N.Bytes(),
+				E:      big.NewInt(int64(privateKey.E)).Bytes(),
+			},
+			privateKey: privateKey,
+			tags:       params.Tags,
+		}
+		if params.KeyAttributes != nil {
+			ver.attributes = *params.KeyAttributes
+		}
+
+		writeJson(w, http.StatusOK, s.addKeyVersion(vaultName, name, ver))
+
+	case r.Method == http.MethodPost && len(rest) == 2 && rest[1] == "decrypt":
+		s.serveDecrypt(w, r, vaultName, name, rest[0])
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "operation is not supported by the fake")
+	}
+}
+
+func (s *Server) addKeyVersion(vaultName, name string, ver *keyVersion) azkeys.KeyBundle {
+	stampCreated(&ver.attributes.Enabled, &ver.attributes.Created, &ver.attributes.Updated)
+
+	key := objectKey(vaultName, name)
+	if s.keys[key] == nil {
+		s.keys[key] = &versionedObject[keyVersion]{versions: map[string]*keyVersion{}}
+	}
+	version := newVersion()
+	s.keys[key].add(version, ver)
+
+	return keyBundleOf(vaultName, name, version, ver)
+}
+
+func (s *Server) serveDecrypt(w http.ResponseWriter, r *http.Request, vaultName, name, version string) {
+	ver, version := s.keys[objectKey(vaultName, name)].get(version)
+	if ver == nil {
+		writeError(w, http.StatusNotFound, "KeyNotFound", fmt.Sprintf("A key with (name/id) %s was not found in this key vault", name))
+		return
+	} else if ver.privateKey == nil {
+		writeError(w, http.StatusBadRequest, "BadParameter", "the key cannot decrypt")
+		return
+	} else if ver.attributes.Enabled != nil && !*ver.attributes.Enabled {
+		writeError(w, http.StatusForbidden, "Forbidden", "Operation decrypt is not allowed on a disabled key.")
+		return
+	}
+
+	params := azkeys.KeyOperationParameters{}
+	if err := readJson(r, &params); err != nil || params.Algorithm == nil {
+		writeError(w, http.StatusBadRequest, "BadParameter", "algorithm and value are required")
+		return
+	}
+
+	var plaintext []byte
+	var decryptErr error
+
+	switch *params.Algorithm {
+	case azkeys.EncryptionAlgorithmRSAOAEP256:
+		plaintext, decryptErr = rsa.DecryptOAEP(sha256.New(), nil, ver.privateKey, params.Value, nil)
+	case azkeys.EncryptionAlgorithmRSAOAEP:
+		plaintext, decryptErr = rsa.DecryptOAEP(sha1.New(), nil, ver.privateKey, params.Value, nil)
+	case azkeys.EncryptionAlgorithmRSA15:
+		plaintext, decryptErr = rsa.DecryptPKCS1v15(nil, ver.privateKey, params.Value)
+	default:
+		writeError(w, http.StatusBadRequest, "BadParameter", fmt.Sprintf("algorithm %s is not supported by the fake", *params.Algorithm))
+		return
+	}
+
+	if decryptErr != nil {
+		writeError(w, http.StatusBadRequest, "BadParameter", fmt.Sprintf("decryption failed: %s", decryptErr.Error()))
+		return
+	}
+
+	writeJson(w, http.StatusOK, azkeys.KeyOperationResult{
+		KID:    to.Ptr(azkeys.ID(objectId(vaultName, "keys", name, version))),
+		Result: plaintext,
+	})
+}
+
+// keyBundleOf the key bundle as Key Vault returns it: the private components of the key are never returned.
+func keyBundleOf(vaultName, name, version string, ver *keyVersion) azkeys.KeyBundle {
+	return azkeys.KeyBundle{
+		Key: &azkeys.JSONWebKey{
+			KID:    to.Ptr(azkeys.ID(objectId(vaultName, "keys", name, version))),
+			Kty:    ver.key.Kty,
+			KeyOps: ver.key.KeyOps,
+			N:      ver.key.N,
+			E:      ver.key.E,
+			Crv:    ver.key.Crv,
+			X:      ver.key.X,
+			Y:      ver.key.Y,
+		},
+		Attributes: to.Ptr(ver.attributes),
+		Tags:       ver.tags,
+	}
+}
+
+func rsaPrivateKeyOf(key *azkeys.JSONWebKey) *rsa.PrivateKey {
+	if len(key.N) == 0 || len(key.D) == 0 || len(key.P) == 0 || len(key.Q) == 0 {
+		return nil
+	}
+
+	rv := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{
+			N: new(big.Int).SetBytes(key.N),
+			E: int(new(big.Int).SetBytes(key.E).Int64()),
+		},
+		D:      new(big.Int).SetBytes(key.D),
+		Primes: []*big.Int{new(big.Int).SetBytes(key.P), new(big.Int).SetBytes(key.Q)},
+	}
+	if rv.Validate() != nil {
+		return nil
+	}
+	rv.Precompute()
+	return rv
+}
+
+// AddWrappingKey places the RSA key into the vault, as the wrapping key of the ciphertexts. The returned value
+// is the version of the key.
+func (s *Server) AddWrappingKey(vaultName, name string, privateKey *rsa.PrivateKey) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	bundle := s.addKeyVersion(vaultName, name, &keyVersion{
+		key: azkeys.JSONWebKey{
+			Kty: to.Ptr(azkeys.KeyTypeRSA),
+			KeyOps: []*azkeys.KeyOperation{
+				to.Ptr(azkeys.KeyOperationEncrypt),
+				to.Ptr(azkeys.KeyOperationDecrypt),
+				to.Ptr(azkeys.KeyOperationWrapKey),
+				to.Ptr(azkeys.KeyOperationUnwrapKey),
+			},
+			N: privateKey.N.Bytes(),
+			E: big.NewInt(int64(privateKey.E)).Bytes(),
+		},
+		privateKey: privateKey,
+	})
+
+	return bundle.Key.KID.Version()
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Certificates
+
+func (s *Server) serveCertificate(w http.ResponseWriter, r *http.Request, vaultName, name string, rest []string) {
+	key := objectKey(vaultName, name)
+
+	switch {
+	case r.Method == http.MethodPost && len(rest) == 1 && rest[0] == "import":
+		params := azcertificates.ImportCertificateParameters{}
+		if err := readJson(r, &params); err != nil || params.Base64EncodedCertificate == nil {
+			writeError(w, http.StatusBadRequest, "BadParameter", "certificate is required")
+			return
+		}
+
+		cer, cerErr := certificateDerOf(*params.Base64EncodedCertificate, params.Password)
+		if cerErr != nil {
+			writeError(w, http.StatusBadRequest, "BadParameter", cerErr.Error())
+			return
+		}
+
+		ver := &certificateVersion{cer: cer, policy: params.CertificatePolicy, tags: params.Tags}
+		if params.CertificateAttributes != nil {
+			ver.attributes = *params.CertificateAttributes
+		}
+		stampCreated(&ver.attributes.Enabled, &ver.attributes.Created, &ver.attributes.Updated)
+
+		if s.certificates[key] == nil {
+			s.certificates[key] = &versionedObject[certificateVersion]{versions: map[string]*certificateVersion{}}
+		}
+		version := newVersion()
+		s.certificates[key].add(version, ver)
+
+		writeJson(w, http.StatusOK, certificateOf(vaultName, name, version, ver))
+
+	case r.Method == http.MethodGet && len(rest) <= 1:
+		ver, version := s.certificates[key].get(versionOf(rest))
+		if ver == nil {
+			writeError(w, http.StatusNotFound, "CertificateNotFound", fmt.Sprintf("A certificate with (name/id) %s was not found in this key vault", name))
+			return
+		}
+		writeJson(w, http.StatusOK, certificateOf(vaultName, name, version, ver))
+
+	case r.Method == http.MethodPatch && len(rest) <= 1:
+		ver, version := s.certificates[key].get(versionOf(rest))
+		if ver == nil {
+			writeError(w, http.StatusNotFound, "CertificateNotFound", fmt.Sprintf("A certificate with (name/id) %s was not found in this key vault", name))
+			return
+		}
+
+		params := azcertificates.UpdateCertificateParameters{}
+		if err := readJson(r, &params); err != nil {
+			writeError(w, http.StatusBadRequest, "BadParameter", err.Error())
+			return
+		}
+		if params.Tags != nil {
+			ver.tags = params.Tags
+		}
+		if attr := params.CertificateAttributes; attr != nil {
+			ver.attributes.Enabled = valueOr(attr.Enabled, ver.attributes.Enabled)
+			ver.attributes.NotBefore = attr.NotBefore
+			ver.attributes.Expires = attr.Expires
+		}
+		ver.attributes.Updated = to.Ptr(time.Now().Truncate(time.Second))
+
+		writeJson(w, http.StatusOK, certificateOf(vaultName, name, version, ver))
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "operation is not supported by the fake")
+	}
+}
+
+// certificateDerOf the DER-encoded leaf certificate of the imported PEM or PKCS12 data
+func certificateDerOf(base64Data string, password *string) ([]byte, error) {
+	data, decodeErr := base64.StdEncoding.DecodeString(base64Data)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("certificate data is not base64-encoded: %s", decodeErr.Error())
+	}
+
+	if block, rest := pem.Decode(data); block != nil {
+		for block != nil {
+			if block.Type == "CERTIFICATE" {
+				return block.Bytes, nil
+			}
+			block, rest = pem.Decode(rest)
+		}
+		return nil, fmt.Errorf("PEM data contains no certificate")
+	}
+
+	pwd := ""
+	if password != nil {
+		pwd = *password
+	}
+	_, cert, _, p12Err := pkcs12.DecodeChain(data, pwd)
+	if p12Err != nil {
+		return nil, fmt.Errorf("cannot read PKCS12 data: %s", p12Err.Error())
+	}
+	return cert.Raw, nil
+}
+
+func certificateOf(vaultName, name, version string, ver *certificateVersion) azcertificates.Certificate {
+	thumbprint := sha1.Sum(ver.cer)
+
+	rv := azcertificates.Certificate{
+		ID:             to.Ptr(azcertificates.ID(objectId(vaultName, "certificates", name, version))),
+		KID:            to.Ptr(azcertificates.ID(objectId(vaultName, "keys", name, version))),
+		SID:            to.Ptr(azcertificates.ID(objectId(vaultName, "secrets", name, version))),
+		CER:            ver.cer,
+		X509Thumbprint: thumbprint[:],
+		Policy:         ver.policy,
+		Attributes:     to.Ptr(ver.attributes),
+		Tags:           ver.tags,
+	}
+
+	if cert, err := x509.ParseCertificate(ver.cer); err == nil {
+		rv.Attributes.NotBefore = to.Ptr(cert.NotBefore)
+		rv.Attributes.Expires = to.Ptr(cert.NotAfter)
+	}
+
+	return rv
+}
+
+// SecretValue the value of the latest version of the secret; false where the vault doesn't have the secret.
+func (s *Server) SecretValue(vaultName, name string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if ver, _ := s.secrets[objectKey(vaultName, name)].get(""); ver != nil {
+		return ver.value, true
+	}
+	return "", false
+}
@@ -0,0 +1,188 @@
+// Package fakeazure an in-process fake of the Azure Key Vault, API Management, and Table Storage APIs the provider
+// calls. The fake keeps the objects in memory; it lets the acceptance tests run the provider against "Azure" without
+// the network and without an Azure subscription.
+//
+// The fake implements only the operations the provider uses:
+//   - Key Vault secrets: set, get, update properties;
+//   - Key Vault keys: create, import, get, update, decrypt;
+//   - Key Vault certificates: import, get, update;
+//   - API management named values: get, list value, create or update, update, delete;
+//   - API management subscriptions: get, list secrets, create or update, update, delete;
+//   - storage account tables: query entities with the equality filters, add entity, merge entity.
+package fakeazure
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// DefaultTenantId the tenant of the fake credential
+const DefaultTenantId = "00000000-0000-0000-0000-00000000fa4e"
+
+// Server the fake Azure server. The server uses TLS (the Azure SDK refuses to send the bearer tokens over plain
+// HTTP); the transport returned by Transport trusts the certificate of the server.
+type Server struct {
+	TenantId string
+
+	srv   *httptest.Server
+	mutex sync.Mutex
+
+	secrets       map[string]*versionedObject[secretVersion]
+	keys          map[string]*versionedObject[keyVersion]
+	certificates  map[string]*versionedObject[certificateVersion]
+	namedValues   map[string]*namedValue
+	subscriptions map[string]*apimSubscription
+	tables        map[string]map[string]tableEntity
+}
+
+// NewServer starts the fake Azure server. The server must be closed when no longer needed.
+func NewServer() *Server {
+	rv := &Server{
+		TenantId:      DefaultTenantId,
+		secrets:       map[string]*versionedObject[secretVersion]{},
+		keys:          map[string]*versionedObject[keyVersion]{},
+		certificates:  map[string]*versionedObject[certificateVersion]{},
+		namedValues:   map[string]*namedValue{},
+		subscriptions: map[string]*apimSubscription{},
+		tables:        map[string]map[string]tableEntity{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vaults/", rv.serveKeyVault)
+	mux.HandleFunc("/subscriptions/", rv.serveResourceManager)
+	mux.HandleFunc("/tables/", rv.serveTables)
+
+	rv.srv = httptest.NewTLSServer(mux)
+	return rv
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// URL the base URL of the server
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// KeyVaultURLTemplate the URL template of the vaults served by this server; the vault name is substituted for the
+// %s verb.
+func (s *Server) KeyVaultURLTemplate() string {
+	return s.srv.URL + "/vaults/%s"
+}
+
+// ResourceManagerURL the URL of the Azure Resource Manager API served by this server
+func (s *Server) ResourceManagerURL() string {
+	return s.srv.URL
+}
+
+// Transport the HTTP transport trusting the certificate of this server
+func (s *Server) Transport() policy.Transporter {
+	return s.srv.Client()
+}
+
+// Credential the credential returning the access tokens this server accepts. The token is issued by the
+// tenant of the server.
+func (s *Server) Credential() azcore.TokenCredential {
+	return &fakeCredential{tenantId: s.TenantId}
+}
+
+type fakeCredential struct {
+	tenantId string
+}
+
+func (f *fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	claims, _ := json.Marshal(map[string]string{"tid": f.tenantId})
+
+	token := strings.Join([]string{
+		base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)),
+		base64.RawURLEncoding.EncodeToString(claims),
+		base64.RawURLEncoding.EncodeToString([]byte("fake-signature")),
+	}, ".")
+
+	return azcore.AccessToken{
+		Token:     token,
+		ExpiresOn: time.Now().Add(time.Hour),
+	}, nil
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Versioned objects
+
+type versionedObject[T any] struct {
+	latest   string
+	versions map[string]*T
+}
+
+func (v *versionedObject[T]) get(version string) (*T, string) {
+	if v == nil {
+		return nil, ""
+	}
+	if len(version) == 0 {
+		version = v.latest
+	}
+	return v.versions[version], version
+}
+
+func (v *versionedObject[T]) add(version string, obj *T) {
+	v.latest = version
+	v.versions[version] = obj
+}
+
+func newVersion() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Responses
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprintf("\"%s\"", newVersion()))
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	body, _ := json.Marshal(map[string]any{
+		"error": map[string]string{
+			"code":    code,
+			"message": message,
+		},
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func readJson(r *http.Request, v any) error {
+	defer r.Body.Close()
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+func isAuthorized(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
@@ -0,0 +1,212 @@
+package fakeazure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
+	"github.com/stretchr/testify/assert"
+)
+
+func givenServer(t *testing.T) *Server {
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func clientOptionsOf(srv *Server) policy.ClientOptions {
+	return policy.ClientOptions{
+		Transport: srv.Transport(),
+		Retry:     policy.RetryOptions{MaxRetries: -1},
+	}
+}
+
+func armClientOptionsOf(srv *Server) *arm.ClientOptions {
+	opts := clientOptionsOf(srv)
+	opts.Cloud = cloud.Configuration{
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Endpoint: srv.ResourceManagerURL(),
+				Audience: "https://management.core.windows.net/",
+			},
+		},
+	}
+	return &arm.ClientOptions{ClientOptions: opts}
+}
+
+func Test_Server_Secrets(t *testing.T) {
+	srv := givenServer(t)
+	client, err := azsecrets.NewClient(fmt.Sprintf(srv.KeyVaultURLTemplate(), "vault"), srv.Credential(), &azsecrets.ClientOptions{
+		ClientOptions:                        clientOptionsOf(srv),
+		DisableChallengeResourceVerification: true,
+	})
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+
+	_, err = client.GetSecret(ctx, "secret", "", nil)
+	assert.True(t, core.IsResourceNotFoundError(err))
+
+	setResp, err := client.SetSecret(ctx, "secret", azsecrets.SetSecretParameters{
+		Value: to.Ptr("secret value"),
+		Tags:  map[string]*string{"a": to.Ptr("b")},
+	}, nil)
+	assert.Nil(t, err)
+
+	coord := core.AzKeyVaultObjectVersionedCoordinate{}
+	assert.Nil(t, coord.FromId(string(*setResp.ID)))
+	assert.Equal(t, "vault", coord.VaultName)
+	assert.Equal(t, "secret", coord.Name)
+
+	_, err = client.UpdateSecretProperties(ctx, "secret", setResp.ID.Version(), azsecrets.UpdateSecretPropertiesParameters{
+		SecretAttributes: &azsecrets.SecretAttributes{Enabled: to.Ptr(false)},
+	}, nil)
+	assert.Nil(t, err)
+
+	getResp, err := client.GetSecret(ctx, "secret", "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret value", *getResp.Value)
+	assert.False(t, *getResp.Attributes.Enabled)
+	assert.Equal(t, "b", *getResp.Tags["a"])
+
+	v, ok := srv.SecretValue("vault", "secret")
+	assert.True(t, ok)
+	assert.Equal(t, "secret value", v)
+}
+
+func Test_Server_DecryptsWithWrappingKey(t *testing.T) {
+	srv := givenServer(t)
+
+	privateKey, err := core.PrivateKeyFromData(testkeymaterial.EphemeralRsaKeyText)
+	assert.Nil(t, err)
+	rsaKey := privateKey.(*rsa.PrivateKey)
+
+	version := srv.AddWrappingKey("vault", "wrapping-key", rsaKey)
+
+	client, err := azkeys.NewClient(fmt.Sprintf(srv.KeyVaultURLTemplate(), "vault"), srv.Credential(), &azkeys.ClientOptions{
+		ClientOptions:                        clientOptionsOf(srv),
+		DisableChallengeResourceVerification: true,
+	})
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+
+	keyResp, err := client.GetKey(ctx, "wrapping-key", "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, version, keyResp.Key.KID.Version())
+	assert.Nil(t, keyResp.Key.D)
+
+	ciphertext, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, &rsaKey.PublicKey, []byte("plain text"), nil)
+	assert.Nil(t, err)
+
+	decResp, err := client.Decrypt(ctx, "wrapping-key", version, azkeys.KeyOperationParameters{
+		Algorithm: to.Ptr(azkeys.EncryptionAlgorithmRSAOAEP256),
+		Value:     ciphertext,
+	}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain text", string(decResp.Result))
+}
+
+func Test_Server_ImportsPemCertificate(t *testing.T) {
+	srv := givenServer(t)
+	client, err := azcertificates.NewClient(fmt.Sprintf(srv.KeyVaultURLTemplate(), "vault"), srv.Credential(), &azcertificates.ClientOptions{
+		ClientOptions:                        clientOptionsOf(srv),
+		DisableChallengeResourceVerification: true,
+	})
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+
+	importResp, err := client.ImportCertificate(ctx, "cert", azcertificates.ImportCertificateParameters{
+		Base64EncodedCertificate: to.Ptr(base64.StdEncoding.EncodeToString(testkeymaterial.EphemeralCertificatePEM)),
+		CertificatePolicy: &azcertificates.CertificatePolicy{
+			SecretProperties: &azcertificates.SecretProperties{ContentType: to.Ptr("application/x-pem-file")},
+		},
+	}, nil)
+	assert.Nil(t, err)
+	assert.True(t, len(importResp.CER) > 0)
+
+	getResp, err := client.GetCertificate(ctx, "cert", importResp.ID.Version(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, importResp.CER, getResp.CER)
+}
+
+func Test_Server_NamedValues(t *testing.T) {
+	srv := givenServer(t)
+	client, err := armapimanagement.NewNamedValueClient("sub", srv.Credential(), armClientOptionsOf(srv))
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+
+	_, err = client.Get(ctx, "rg", "svc", "nv", nil)
+	assert.True(t, core.IsResourceNotFoundError(err))
+
+	poller, err := client.BeginCreateOrUpdate(ctx, "rg", "svc", "nv", armapimanagement.NamedValueCreateContract{
+		Properties: &armapimanagement.NamedValueCreateContractProperties{
+			DisplayName: to.Ptr("nv"),
+			Secret:      to.Ptr(true),
+			Value:       to.Ptr("named value"),
+		},
+	}, nil)
+	assert.Nil(t, err)
+	created, err := poller.PollUntilDone(ctx, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, created.Properties.Value)
+
+	valueResp, err := client.ListValue(ctx, "rg", "svc", "nv", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "named value", *valueResp.Value)
+
+	_, err = client.Delete(ctx, "rg", "svc", "nv", "*", nil)
+	assert.Nil(t, err)
+
+	_, ok := srv.NamedValue("sub", "rg", "svc", "nv")
+	assert.False(t, ok)
+}
+
+func Test_Server_Subscriptions(t *testing.T) {
+	srv := givenServer(t)
+	client, err := armapimanagement.NewSubscriptionClient("sub", srv.Credential(), armClientOptionsOf(srv))
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+
+	createResp, err := client.CreateOrUpdate(ctx, "rg", "svc", "sid", armapimanagement.SubscriptionCreateParameters{
+		Properties: &armapimanagement.SubscriptionCreateParameterProperties{
+			DisplayName: to.Ptr("Subscription"),
+			Scope:       to.Ptr("/apis"),
+			PrimaryKey:  to.Ptr("primary"),
+		},
+	}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/subscriptions/sid", *createResp.ID)
+
+	keysResp, err := client.ListSecrets(ctx, "rg", "svc", "sid", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "primary", *keysResp.PrimaryKey)
+	assert.NotEmpty(t, *keysResp.SecondaryKey)
+
+	_, err = client.Update(ctx, "rg", "svc", "sid", "*", armapimanagement.SubscriptionUpdateParameters{
+		Properties: &armapimanagement.SubscriptionUpdateParameterProperties{SecondaryKey: to.Ptr("secondary")},
+	}, nil)
+	assert.Nil(t, err)
+
+	primary, secondary, ok := srv.SubscriptionKeys("sub", "rg", "svc", "sid")
+	assert.True(t, ok)
+	assert.Equal(t, "primary", primary)
+	assert.Equal(t, "secondary", secondary)
+}
@@ -0,0 +1,147 @@
+package fakeazure
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tableEntity the properties of the table entity, including PartitionKey and RowKey, as sent by the client
+type tableEntity map[string]any
+
+func entityKey(partitionKey, rowKey string) string {
+	return partitionKey + "\x00" + rowKey
+}
+
+// TableURL the URL of the storage account table served by this server, as the table clients accept it
+func (s *Server) TableURL(tableName string) string {
+	return s.srv.URL + "/tables/" + tableName
+}
+
+// tableFilterTerm the term of the filter the fake supports, e.g. PartitionKey eq 'p'
+var tableFilterTerm = regexp.MustCompile(`^(\w+) eq '((?:[^']|'')*)'$`)
+
+// parseTableFilter parses the conjunction of the equality terms into the map of the property values
+func parseTableFilter(filter string) (map[string]string, error) {
+	rv := map[string]string{}
+	if len(filter) == 0 {
+		return rv, nil
+	}
+
+	for _, term := range strings.Split(filter, " and ") {
+		match := tableFilterTerm.FindStringSubmatch(strings.TrimSpace(term))
+		if match == nil {
+			return nil, fmt.Errorf("filter term %s is not supported", term)
+		}
+		rv[match[1]] = strings.ReplaceAll(match[2], "''", "'")
+	}
+
+	return rv, nil
+}
+
+// entityAddress the table name and the keys of the entity addressed by the path segment, e.g.
+// tracker(PartitionKey='p',RowKey='r')
+var entityAddress = regexp.MustCompile(`^(\w+)\(PartitionKey='((?:[^']|'')*)',RowKey='((?:[^']|'')*)'\)$`)
+
+func (s *Server) serveTables(w http.ResponseWriter, r *http.Request) {
+	if !isAuthorized(r) {
+		writeError(w, http.StatusUnauthorized, "AuthenticationFailed", "Request is missing a Bearer token.")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/tables/")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "()"):
+		s.queryEntities(w, r, strings.TrimSuffix(path, "()"))
+
+	case r.Method == http.MethodPost && !strings.Contains(path, "("):
+		entity := tableEntity{}
+		if err := readJson(r, &entity); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidInput", err.Error())
+			return
+		}
+
+		pk, _ := entity["PartitionKey"].(string)
+		rk, _ := entity["RowKey"].(string)
+		if s.tables[path] == nil {
+			s.tables[path] = map[string]tableEntity{}
+		}
+		if _, exists := s.tables[path][entityKey(pk, rk)]; exists {
+			writeError(w, http.StatusConflict, "EntityAlreadyExists", "The specified entity already exists.")
+			return
+		}
+
+		s.tables[path][entityKey(pk, rk)] = entity
+		writeJson(w, http.StatusCreated, entity)
+
+	case r.Method == http.MethodPatch:
+		match := entityAddress.FindStringSubmatch(path)
+		if match == nil {
+			writeError(w, http.StatusBadRequest, "InvalidUri", "entity address is not supported")
+			return
+		}
+
+		tableName := match[1]
+		key := entityKey(strings.ReplaceAll(match[2], "''", "'"), strings.ReplaceAll(match[3], "''", "'"))
+		existing, exists := s.tables[tableName][key]
+		if !exists {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", "The specified resource does not exist.")
+			return
+		}
+
+		update := tableEntity{}
+		if err := readJson(r, &update); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidInput", err.Error())
+			return
+		}
+		for k, v := range update {
+			existing[k] = v
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusNotFound, "ResourceNotFound", "unsupported table operation")
+	}
+}
+
+func (s *Server) queryEntities(w http.ResponseWriter, r *http.Request, tableName string) {
+	conditions, err := parseTableFilter(r.URL.Query().Get("$filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidInput", err.Error())
+		return
+	}
+
+	keys := make([]string, 0, len(s.tables[tableName]))
+	for k := range s.tables[tableName] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var matched []tableEntity
+	for _, k := range keys {
+		entity := s.tables[tableName][k]
+
+		matches := true
+		for prop, value := range conditions {
+			if v, ok := entity[prop].(string); !ok || v != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			matched = append(matched, entity)
+		}
+	}
+
+	if matched == nil {
+		matched = []tableEntity{}
+	}
+	writeJson(w, http.StatusOK, map[string]any{"value": matched})
+}
@@ -0,0 +1,161 @@
+package acceptance
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"testing"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/acceptance/fakeazure"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/provider"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/cmdgroups/apim"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/cmdgroups/keyvault"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/model"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	offlineVaultName       = "acceptance-vault"
+	offlineWrappingKeyName = "wrapping-key"
+)
+
+// offlineAzure the fake Azure the offline acceptance tests run against. The wrapping key of the fake vault is
+// the ephemeral test key, so that the ciphertexts are produced without Azure.
+type offlineAzure struct {
+	srv                *fakeazure.Server
+	wrappingKeyVersion string
+}
+
+func givenOfflineAzure(t *testing.T) *offlineAzure {
+	privateKey, err := core.PrivateKeyFromData(testkeymaterial.EphemeralRsaKeyText)
+	assert.NoError(t, err)
+
+	srv := fakeazure.NewServer()
+	t.Cleanup(srv.Close)
+
+	return &offlineAzure{
+		srv:                srv,
+		wrappingKeyVersion: srv.AddWrappingKey(offlineVaultName, offlineWrappingKeyName, privateKey.(*rsa.PrivateKey)),
+	}
+}
+
+func (o *offlineAzure) providerFactories() map[string]func() (tfprotov6.ProviderServer, error) {
+	overrides := provider.AzureOverrides{
+		Credential: o.srv.Credential(),
+		Transport:  o.srv.Transport(),
+		Endpoints: provider.AzEndpoints{
+			KeyVaultURLTemplate: o.srv.KeyVaultURLTemplate(),
+			ResourceManager:     o.srv.ResourceManagerURL(),
+		},
+	}
+
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"az-confidential": providerserver.NewProtocol6WithError(provider.NewWithAzureOverrides("test", overrides)()),
+	}
+}
+
+func (o *offlineAzure) providerConfig() string {
+	return fmt.Sprintf(`
+provider "az-confidential" {
+  subscription_id = "00000000-0000-0000-0000-000000000000"
+  constraints     = ["acceptance-testing"]
+
+  default_destination_vault_name = "%s"
+
+  default_wrapping_key = {
+    vault_name = "%s"
+    name       = "%s"
+    version    = "%s"
+  }
+}
+`, offlineVaultName, offlineVaultName, offlineWrappingKeyName, o.wrappingKeyVersion)
+}
+
+func offlineContentWrappingParams() model.ContentWrappingParams {
+	return model.ContentWrappingParams{
+		SecondaryProtectionParameters: core.SecondaryProtectionParameters{
+			ProviderConstraints: []core.ProviderConstraint{"acceptance-testing"},
+		},
+		LoadRsaPublicKey:      core.LoadPublicKeyFromDataOnce(testkeymaterial.EphemeralRsaPublicKey),
+		WrappingKeyCoordinate: model.NewWrappingKey(),
+	}
+}
+
+func TestAccOfflineConfidentialSecret(t *testing.T) {
+	azure := givenOfflineAzure(t)
+
+	kwp := offlineContentWrappingParams()
+	secretModel := keyvault.TerraformCodeModel{
+		BaseTerraformCodeModel: model.BaseTerraformCodeModel{
+			TFBlockName:           "secret",
+			WrappingKeyCoordinate: kwp.WrappingKeyCoordinate,
+		},
+		DestinationCoordinate: keyvault.NewObjectCoordinateModel("", "offline-secret"),
+	}
+
+	tfCode, _, tfErr := keyvault.OutputSecretTerraformCode(secretModel, &kwp, "this is a very secret string")
+	assert.NoError(t, tfErr)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: azure.providerFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: azure.providerConfig() + tfCode.String(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("az-confidential_keyvault_secret.secret", "secret_version"),
+					resource.TestCheckResourceAttr("az-confidential_keyvault_secret.secret", "enabled", "true"),
+					func(_ *terraform.State) error {
+						if v, ok := azure.srv.SecretValue(offlineVaultName, "offline-secret"); !ok || v != "this is a very secret string" {
+							return fmt.Errorf("secret was not placed into the fake vault")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccOfflineApimNamedValue(t *testing.T) {
+	azure := givenOfflineAzure(t)
+
+	kwp := offlineContentWrappingParams()
+	nvModel := apim.NamedValueTerraformCodeModel{
+		BaseTerraformCodeModel: model.BaseTerraformCodeModel{
+			TFBlockName:           "nv",
+			WrappingKeyCoordinate: kwp.WrappingKeyCoordinate,
+			EncryptedContent:      model.NewStringTerraformFieldHeredocExpression(),
+		},
+		DestinationNamedValue: apim.NamedValueCoordinateModel{
+			BaseCoordinateModel: apim.BaseCoordinateModel{
+				AzSubscriptionId:  NewStrVal("00000000-0000-0000-0000-000000000000"),
+				ResourceGroupName: NewStrVal("rg"),
+				ServiceName:       NewStrVal("apim"),
+			},
+			NamedValue: NewStrVal("offlineNamedValue"),
+		},
+	}
+
+	tfCode, _, tfErr := apim.OutputNamedValueTerraformCode(nvModel, &kwp, "this is a very sensitive named value")
+	assert.NoError(t, tfErr)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: azure.providerFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: azure.providerConfig() + tfCode.String(),
+				Check: func(_ *terraform.State) error {
+					if v, ok := azure.srv.NamedValue("00000000-0000-0000-0000-000000000000", "rg", "apim", "offlineNamedValue"); !ok || v != "this is a very sensitive named value" {
+						return fmt.Errorf("named value was not placed into the fake API management")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type AuditOperation string
+
+const (
+	AuditOperationDecrypt AuditOperation = "decrypt"
+	AuditOperationCreate  AuditOperation = "create"
+	AuditOperationUpdate  AuditOperation = "update"
+	AuditOperationDelete  AuditOperation = "delete"
+)
+
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "success"
+	AuditOutcomeFailure AuditOutcome = "failure"
+)
+
+// AuditEvent a structured record of an operation the provider performed with the ciphertext. The event
+// describes which ciphertext was unwrapped by which wrapping key and where the resulting object was placed.
+// The event must never carry the plaintext or any derivative thereof.
+type AuditEvent struct {
+	Timestamp      time.Time      `json:"timestamp"`
+	Operation      AuditOperation `json:"operation"`
+	CiphertextUuid string         `json:"uuid,omitempty"`
+	ObjectType     string         `json:"type,omitempty"`
+	Destination    string         `json:"destination,omitempty"`
+	WrappingKey    string         `json:"wrapping_key,omitempty"`
+	Outcome        AuditOutcome   `json:"outcome"`
+	Message        string         `json:"message,omitempty"`
+}
+
+// WrappingKeyAuditLabel formats the wrapping key coordinate in the form recorded in the audit events.
+func WrappingKeyAuditLabel(coord WrappingKeyCoordinate) string {
+	return fmt.Sprintf("%s/%s/%s (%s)", coord.VaultName, coord.KeyName, coord.KeyVersion, coord.Algorithm)
+}
+
+// AuditSubject the ciphertext and the destination of the operation in the course of which the ciphertext
+// is decrypted. The uuid and the type are read from the unverified headers of the ciphertext.
+type AuditSubject struct {
+	CiphertextUuid string
+	ObjectType     string
+	Destination    string
+}
+
+type auditSubjectContextKey struct{}
+
+// WithAuditSubject returns the context which attributes the decrypt audit events to the subject.
+func WithAuditSubject(ctx context.Context, subject AuditSubject) context.Context {
+	return context.WithValue(ctx, auditSubjectContextKey{}, subject)
+}
+
+// AuditSubjectOf returns the subject of the decrypt audit events recorded within this context, or an empty
+// subject where none was set.
+func AuditSubjectOf(ctx context.Context) AuditSubject {
+	if subject, ok := ctx.Value(auditSubjectContextKey{}).(AuditSubject); ok {
+		return subject
+	}
+	return AuditSubject{}
+}
@@ -41,3 +41,23 @@ func AnyIsInWithComparator[T, V any](sourceValues []T, values []V, cmp BiCompara
 
 	return false
 }
+
+// StringPtrsValues the values of the string pointers; nil pointers are skipped.
+func StringPtrsValues(values []*string) []string {
+	var rv []string
+	for _, v := range values {
+		if v != nil {
+			rv = append(rv, *v)
+		}
+	}
+
+	return rv
+}
+
+// StringPtrValue the value of the string pointer; empty string where the pointer is nil.
+func StringPtrValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -22,6 +22,7 @@ type ConfidentialDataJsonHeader struct {
 	Uuid                 string                `json:"u"`
 	Type                 string                `json:"t"`
 	CreateLimit          int64                 `json:"clt,omitempty,omitzero"`
+	NotBefore            int64                 `json:"nbf,omitempty,omitzero"`
 	Expiry               int64                 `json:"exp,omitempty,omitzero"`
 	NumUses              int                   `json:"nu,omitempty,omitzero"`
 	ProviderConstraints  []ProviderConstraint  `json:"prc,omitempty"`
@@ -33,6 +34,7 @@ func (c ConfidentialDataJsonHeader) Equal(other ConfidentialDataJsonHeader) bool
 	return c.Uuid == other.Uuid &&
 		c.Type == other.Type &&
 		c.CreateLimit == other.CreateLimit &&
+		c.NotBefore == other.NotBefore &&
 		c.Expiry == other.Expiry &&
 		c.NumUses == other.NumUses &&
 		SameBag[ProviderConstraint](func(a, b ProviderConstraint) bool { return a == b }, c.ProviderConstraints, other.ProviderConstraints) &&
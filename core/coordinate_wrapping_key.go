@@ -26,6 +26,16 @@ func (w *WrappingKeyCoordinateModel) AsCoordinate() WrappingKeyCoordinate {
 	}
 }
 
+// IsKnown whether all coordinates of the wrapping key are known. During the planning, the coordinates
+// may depend on the resources that are not yet created.
+func (w *WrappingKeyCoordinateModel) IsKnown() bool {
+	if w == nil {
+		return true
+	}
+
+	return !w.VaultName.IsUnknown() && !w.KeyName.IsUnknown() && !w.KeyVersion.IsUnknown() && !w.Algorithm.IsUnknown()
+}
+
 type WrappingKeyCoordinate struct {
 	VaultName  string
 	KeyName    string
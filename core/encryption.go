@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"crypto"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -147,6 +148,15 @@ func (em *EncryptedMessage) GetSecretExpr() string {
 	}
 }
 
+// GetHeader returns the value of the unencrypted header of the message. The headers are not authenticated;
+// the values are informational only.
+func (em *EncryptedMessage) GetHeader(name string) string {
+	if em.headers == nil {
+		return ""
+	}
+	return em.headers[name]
+}
+
 func (em *EncryptedMessage) HasContentEncryptionKey() bool {
 	return len(em.contentEncryptionKey) > 0
 }
@@ -366,6 +376,31 @@ func LoadPublicKeyFromData(pubText []byte) (*rsa.PublicKey, error) {
 	}
 }
 
+// PublicKeyToPEM encodes the public key as PKIX "PUBLIC KEY" PEM block which LoadPublicKeyFromData accepts.
+func PublicKeyToPEM(key *rsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// PublicKeyThumbprint computes the RFC 7638 SHA-256 thumbprint of the public key, encoded as base64url.
+func PublicKeyThumbprint(key *rsa.PublicKey) (string, error) {
+	jwkKey, importErr := jwk.Import(key)
+	if importErr != nil {
+		return "", importErr
+	}
+
+	tp, tpErr := jwkKey.Thumbprint(crypto.SHA256)
+	if tpErr != nil {
+		return "", tpErr
+	}
+
+	return base64.RawURLEncoding.EncodeToString(tp), nil
+}
+
 func ConvertJWKSToAzJWK(set jwk.Set, key *azkeys.JSONWebKey) error {
 	if jwkKey, exists := set.Key(0); exists {
 		return ConvertJWKToAzJWK(jwkKey, key)
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultExpiryWarningDays number of days before the ciphertext expires (or can no longer be used to create
+// objects) during which the warnings are issued, unless the provider configures otherwise.
+const DefaultExpiryWarningDays = 30
+
+// ExpiryWarningWindowOf converts the number of days into the warning window.
+func ExpiryWarningWindowOf(days int64) time.Duration {
+	return time.Hour * 24 * time.Duration(days)
+}
+
+// ExpiryWarningsOf lists the time limits of the ciphertext that are not yet reached, but fall within the
+// warning window. Limits that have already lapsed are not reported: these are errors rather than warnings.
+// A non-positive window disables the warnings.
+func ExpiryWarningsOf(header ConfidentialDataJsonHeader, now time.Time, window time.Duration) []string {
+	var rv []string
+
+	if msg, ok := ExpiryWarningOf(header, now, window); ok {
+		rv = append(rv, msg)
+	}
+	if msg, ok := CreateLimitWarningOf(header, now, window); ok {
+		rv = append(rv, msg)
+	}
+
+	return rv
+}
+
+// ExpiryWarningOf returns the warning where the ciphertext expires within the warning window.
+func ExpiryWarningOf(header ConfidentialDataJsonHeader, now time.Time, window time.Duration) (string, bool) {
+	if !isWithinWarningWindow(header.Expiry, now, window) {
+		return "", false
+	}
+
+	return fmt.Sprintf("ciphertext %s expires on %s (%s)", header.Uuid, limitDateOf(header.Expiry), remainingOf(header.Expiry, now)), true
+}
+
+// CreateLimitWarningOf returns the warning where the time to create objects from the ciphertext lapses
+// within the warning window.
+func CreateLimitWarningOf(header ConfidentialDataJsonHeader, now time.Time, window time.Duration) (string, bool) {
+	if !isWithinWarningWindow(header.CreateLimit, now, window) {
+		return "", false
+	}
+
+	return fmt.Sprintf("ciphertext %s can be used to create objects until %s (%s)", header.Uuid, limitDateOf(header.CreateLimit), remainingOf(header.CreateLimit, now)), true
+}
+
+func isWithinWarningWindow(limit int64, now time.Time, window time.Duration) bool {
+	if window <= 0 || limit <= 0 || now.Unix() > limit {
+		return false
+	}
+
+	return time.Unix(limit, 0).Sub(now) < window
+}
+
+func limitDateOf(limit int64) string {
+	return time.Unix(limit, 0).UTC().Format(time.RFC3339)
+}
+
+func remainingOf(limit int64, now time.Time) string {
+	days := int(time.Unix(limit, 0).Sub(now) / (time.Hour * 24))
+	switch days {
+	case 0:
+		return "less than a day remaining"
+	case 1:
+		return "1 day remaining"
+	default:
+		return fmt.Sprintf("%d days remaining", days)
+	}
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestExpiryWarningsOf_OutsideWindow(t *testing.T) {
+	now := time.Now()
+	header := ConfidentialDataJsonHeader{
+		Uuid:        "unit-test-uuid",
+		Expiry:      now.Add(ExpiryWarningWindowOf(60)).Unix(),
+		CreateLimit: now.Add(ExpiryWarningWindowOf(45)).Unix(),
+	}
+
+	assert.Empty(t, ExpiryWarningsOf(header, now, ExpiryWarningWindowOf(30)))
+}
+
+func TestExpiryWarningsOf_InsideWindow(t *testing.T) {
+	now := time.Now()
+	header := ConfidentialDataJsonHeader{
+		Uuid:        "unit-test-uuid",
+		Expiry:      now.Add(ExpiryWarningWindowOf(10) + time.Hour).Unix(),
+		CreateLimit: now.Add(ExpiryWarningWindowOf(1) + time.Hour).Unix(),
+	}
+
+	rv := ExpiryWarningsOf(header, now, ExpiryWarningWindowOf(30))
+	assert.Equal(t, 2, len(rv))
+	assert.Contains(t, rv[0], "unit-test-uuid expires on")
+	assert.Contains(t, rv[0], time.Unix(header.Expiry, 0).UTC().Format(time.RFC3339))
+	assert.Contains(t, rv[0], "10 days remaining")
+	assert.Contains(t, rv[1], "can be used to create objects until")
+	assert.Contains(t, rv[1], "1 day remaining")
+}
+
+func TestExpiryWarningsOf_LapsedLimitsAreNotWarnings(t *testing.T) {
+	now := time.Now()
+	header := ConfidentialDataJsonHeader{
+		Expiry:      now.Unix() - 100,
+		CreateLimit: now.Unix() - 100,
+	}
+
+	assert.Empty(t, ExpiryWarningsOf(header, now, ExpiryWarningWindowOf(30)))
+}
+
+func TestExpiryWarningsOf_DisabledWindow(t *testing.T) {
+	now := time.Now()
+	header := ConfidentialDataJsonHeader{
+		Expiry: now.Unix() + 100,
+	}
+
+	assert.Empty(t, ExpiryWarningsOf(header, now, 0))
+}
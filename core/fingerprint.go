@@ -0,0 +1,66 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// ConfidentialValueFingerprint the fingerprint of the confidential value placed on the Azure object. The
+// fingerprint is kept in the private state of the resource: it allows comparing the Azure object with
+// the value placed from the ciphertext without decrypting the ciphertext on every read.
+//
+// The fingerprint is a keyed HMAC of the ciphertext digest, the header, and the placed value. The header
+// is kept alongside, so that the checks of the ciphertext (revocation, expiry, placement) can be run
+// without decryption; the HMAC authenticates the header as it was decrypted.
+type ConfidentialValueFingerprint struct {
+	KeyId            string                     `json:"kid"`
+	CiphertextSha256 string                     `json:"ciphertext_sha256"`
+	Header           ConfidentialDataJsonHeader `json:"header"`
+	Fingerprint      string                     `json:"fingerprint"`
+}
+
+// fingerprintKeyId identifies the key of the fingerprint, so that the fingerprints computed with another key
+// (e.g. after the key is rotated) are not mistaken for the changed values.
+func fingerprintKeyId(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("az-confidential fingerprint key"))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+func computeFingerprint(key []byte, ciphertextSha256 string, header ConfidentialDataJsonHeader, value []byte) string {
+	headerJson, _ := json.Marshal(header)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(ciphertextSha256))
+	mac.Write(headerJson)
+	mac.Write(value)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewConfidentialValueFingerprint computes the fingerprint of the value placed from the ciphertext, as given in
+// the configuration, bearing the header.
+func NewConfidentialValueFingerprint(key []byte, ciphertext string, header ConfidentialDataJsonHeader, value []byte) ConfidentialValueFingerprint {
+	ciphertextSha256 := Sha256Of(ciphertext)
+
+	return ConfidentialValueFingerprint{
+		KeyId:            fingerprintKeyId(key),
+		CiphertextSha256: ciphertextSha256,
+		Header:           header,
+		Fingerprint:      computeFingerprint(key, ciphertextSha256, header, value),
+	}
+}
+
+// IsOf whether the fingerprint was computed with the key for the ciphertext. Where it is not, the ciphertext
+// has changed since the value was placed (or the key was rotated) and needs to be decrypted.
+func (f ConfidentialValueFingerprint) IsOf(key []byte, ciphertext string) bool {
+	return f.KeyId == fingerprintKeyId(key) && f.CiphertextSha256 == Sha256Of(ciphertext)
+}
+
+// Matches whether the value read from the Azure object is the value the fingerprint was computed for.
+func (f ConfidentialValueFingerprint) Matches(key []byte, value []byte) bool {
+	expected := computeFingerprint(key, f.CiphertextSha256, f.Header, value)
+	return hmac.Equal([]byte(expected), []byte(f.Fingerprint))
+}
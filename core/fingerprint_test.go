@@ -0,0 +1,26 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfidentialValueFingerprint(t *testing.T) {
+	key := []byte("unit-test-fingerprint-key")
+	header := ConfidentialDataJsonHeader{Uuid: "abc-123", Type: "unit-test"}
+
+	fp := NewConfidentialValueFingerprint(key, "ciphertext", header, []byte("value"))
+
+	assert.True(t, fp.IsOf(key, "ciphertext"))
+	assert.False(t, fp.IsOf(key, "another ciphertext"))
+	assert.False(t, fp.IsOf([]byte("rotated-key"), "ciphertext"))
+
+	assert.True(t, fp.Matches(key, []byte("value")))
+	assert.False(t, fp.Matches(key, []byte("changed value")))
+	assert.False(t, fp.Matches([]byte("another-key"), []byte("value")))
+
+	tampered := fp
+	tampered.Header.Uuid = "def-456"
+	assert.False(t, tampered.Matches(key, []byte("value")))
+}
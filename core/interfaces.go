@@ -9,6 +9,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"time"
 )
 
 type AzSecretsClientAbstraction interface {
@@ -19,6 +20,8 @@ type AzSecretsClientAbstraction interface {
 
 type AzKeyClientAbstraction interface {
 	ImportKey(ctx context.Context, name string, parameters azkeys.ImportKeyParameters, options *azkeys.ImportKeyOptions) (azkeys.ImportKeyResponse, error)
+	// CreateKey creates the key in Key Vault. Where the key exists, its new version is created.
+	CreateKey(ctx context.Context, name string, parameters azkeys.CreateKeyParameters, options *azkeys.CreateKeyOptions) (azkeys.CreateKeyResponse, error)
 	Decrypt(ctx context.Context, name string, version string, parameters azkeys.KeyOperationParameters, options *azkeys.DecryptOptions) (azkeys.DecryptResponse, error)
 	UpdateKey(ctx context.Context, name string, version string, parameters azkeys.UpdateKeyParameters, options *azkeys.UpdateKeyOptions) (azkeys.UpdateKeyResponse, error)
 	GetKey(ctx context.Context, name string, version string, options *azkeys.GetKeyOptions) (azkeys.GetKeyResponse, error)
@@ -81,9 +84,52 @@ type AZClientsFactory interface {
 	IsObjectTrackingEnabled() bool
 	IsObjectIdTracked(ctx context.Context, id string) (bool, error)
 	GetTackedObjectUses(ctx context.Context, id string) (int, error)
-	TrackObjectId(ctx context.Context, id string) error
+	// TrackObjectId records the use of the ciphertext identified by id at the destination. The destination is
+	// empty where the ciphertext does not create Azure objects.
+	TrackObjectId(ctx context.Context, id string, destination string) error
+
+	// ListTrackedObjects returns the records of the tracked ciphertexts identified by uuids. Where no uuids are
+	// given, all ciphertexts tracked by the provider are returned. Uuids that are not tracked are omitted.
+	ListTrackedObjects(ctx context.Context, uuids []string) ([]TrackedObject, error)
 
 	GetDecrypterFor(ctx context.Context, coord *WrappingKeyCoordinateModel) RSADecrypter
+
+	// GetMergedWrappingKeyCoordinate returns the coordinate of the wrapping key, merging the coordinate given by
+	// the resource with the provider defaults. Where the version is not given, the latest version of the key is
+	// returned.
+	GetMergedWrappingKeyCoordinate(ctx context.Context, param *WrappingKeyCoordinateModel) (WrappingKeyCoordinate, error)
+
+	// GetCiphertextRevocation returns the revocation record of the ciphertext identified by uuid, or nil where
+	// the ciphertext is not revoked. Where revocation is not configured, no ciphertext is revoked. An error is
+	// returned where the configured revocation list cannot be consulted.
+	GetCiphertextRevocation(ctx context.Context, uuid string) (*CiphertextRevocation, error)
+
+	// RevokeCiphertext records the revocation of the ciphertext identified by uuid in the object tracker and
+	// returns the recorded revocation. An error is returned where the provider does not consult the object
+	// tracker for the revoked ciphertexts.
+	RevokeCiphertext(ctx context.Context, uuid string, reason string) (*CiphertextRevocation, error)
+
+	// GetExpiryWarningWindow returns the duration before the ciphertext expiry (or create limit) during
+	// which the warnings are issued. Zero duration disables the warnings.
+	GetExpiryWarningWindow() time.Duration
+
+	// IsProvenanceTaggingEnabled whether the provider stamps provenance tags on the Azure objects it creates.
+	IsProvenanceTaggingEnabled() bool
+
+	// GetProvenanceTags returns the provenance tags to stamp on the Azure object created from the ciphertext
+	// given in the configuration, or nil where the provider does not stamp provenance tags.
+	GetProvenanceTags(header ConfidentialDataJsonHeader, ciphertext string) map[string]string
+
+	// GetFingerprintKey returns the key of the fingerprints of the confidential values kept in the private state
+	// of the resources, or nil where the provider does not fingerprint the values. Without the fingerprints,
+	// the ciphertext is decrypted on every read.
+	GetFingerprintKey() []byte
+
+	// RecordAuditEvent passes the event to the audit sink configured on the provider. The wrapping key
+	// coordinate is resolved against the provider defaults and recorded in the event. Where the sink cannot
+	// record the event, a warning is added to the diagnostics. Where audit is not configured, the event is
+	// discarded.
+	RecordAuditEvent(ctx context.Context, event AuditEvent, coord *WrappingKeyCoordinateModel, diagnostics *diag.Diagnostics)
 }
 
 // TODO Probaaby this model needs to be deleted as not useful
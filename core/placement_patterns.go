@@ -0,0 +1,119 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	// PlacementGlobPrefix marks the placement constraint as a glob pattern, e.g.
+	// glob:az-c-keyvault://kv-prod-*@secrets=app-*
+	PlacementGlobPrefix = "glob:"
+	// PlacementRegexPrefix marks the placement constraint as a regular expression, e.g.
+	// regex:az-c-keyvault://kv-prod-[0-9]+@secrets=app-.*
+	PlacementRegexPrefix = "regex:"
+
+	labelSchemeSeparator = "://"
+)
+
+var labelSchemeExpr = regexp.MustCompile("^[a-z0-9-]+$")
+
+// IsPattern whether the placement constraint is a glob or a regular expression rather than an exact label.
+func (p PlacementConstraint) IsPattern() bool {
+	return strings.HasPrefix(string(p), PlacementGlobPrefix) || strings.HasPrefix(string(p), PlacementRegexPrefix)
+}
+
+// PlacementMatcher matches the label of the destination against the placement constraint. The scheme of
+// the label (e.g. az-c-keyvault) identifies the destination type; it is always matched exactly, so that
+// a pattern written for one destination type never matches the destination of another type. The pattern
+// applies to the rest of the label.
+type PlacementMatcher struct {
+	Constraint PlacementConstraint
+
+	scheme  string
+	matchFn func(string) bool
+}
+
+// Kind returns the kind of the matching: exact, glob, or regex.
+func (m PlacementMatcher) Kind() string {
+	c := string(m.Constraint)
+	if strings.HasPrefix(c, PlacementGlobPrefix) {
+		return "glob"
+	} else if strings.HasPrefix(c, PlacementRegexPrefix) {
+		return "regex"
+	}
+	return "exact"
+}
+
+// Matches whether the destination label satisfies the constraint.
+func (m PlacementMatcher) Matches(label string) bool {
+	if m.matchFn == nil {
+		return string(m.Constraint) == label
+	}
+
+	scheme, rest, found := strings.Cut(label, labelSchemeSeparator)
+	if !found || scheme != m.scheme {
+		return false
+	}
+
+	return m.matchFn(rest)
+}
+
+// ParsePlacementConstraint parses the placement constraint into the matcher. Constraints without glob: or
+// regex: prefix are matched exactly.
+func ParsePlacementConstraint(c PlacementConstraint) (PlacementMatcher, error) {
+	rv := PlacementMatcher{Constraint: c}
+	if !c.IsPattern() {
+		return rv, nil
+	}
+
+	kind := rv.Kind()
+	pattern := strings.TrimPrefix(strings.TrimPrefix(string(c), PlacementGlobPrefix), PlacementRegexPrefix)
+
+	scheme, rest, found := strings.Cut(pattern, labelSchemeSeparator)
+	if !found || !labelSchemeExpr.MatchString(scheme) {
+		return rv, fmt.Errorf("%s pattern must start with the destination type, e.g. az-c-keyvault://", kind)
+	}
+	if len(rest) == 0 {
+		return rv, fmt.Errorf("%s pattern does not specify the destination", kind)
+	}
+	rv.scheme = scheme
+
+	if kind == "glob" {
+		if _, err := path.Match(rest, ""); err != nil {
+			return rv, fmt.Errorf("invalid glob pattern: %s", err.Error())
+		}
+		rv.matchFn = func(s string) bool {
+			ok, _ := path.Match(rest, s)
+			return ok
+		}
+	} else {
+		expr, err := regexp.Compile("^(?:" + rest + ")$")
+		if err != nil {
+			return rv, fmt.Errorf("invalid regular expression: %s", err.Error())
+		}
+		rv.matchFn = expr.MatchString
+	}
+
+	return rv, nil
+}
+
+// ParsePlacementConstraints parses all placement constraints, returning the errors of all constraints
+// that cannot be parsed.
+func ParsePlacementConstraints(constraints []PlacementConstraint) ([]PlacementMatcher, error) {
+	rv := make([]PlacementMatcher, 0, len(constraints))
+	var errs []error
+
+	for _, c := range constraints {
+		if m, err := ParsePlacementConstraint(c); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", c, err.Error()))
+		} else {
+			rv = append(rv, m)
+		}
+	}
+
+	return rv, errors.Join(errs...)
+}
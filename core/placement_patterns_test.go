@@ -0,0 +1,55 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePlacementConstraint_Exact(t *testing.T) {
+	m, err := ParsePlacementConstraint("az-c-keyvault://kv@secrets=a")
+	assert.Nil(t, err)
+	assert.Equal(t, "exact", m.Kind())
+	assert.True(t, m.Matches("az-c-keyvault://kv@secrets=a"))
+	assert.False(t, m.Matches("az-c-keyvault://kv@secrets=b"))
+}
+
+func TestParsePlacementConstraint_Glob(t *testing.T) {
+	m, err := ParsePlacementConstraint("glob:az-c-keyvault://kv-prod-*@secrets=app-*")
+	assert.Nil(t, err)
+	assert.Equal(t, "glob", m.Kind())
+	assert.True(t, m.Matches("az-c-keyvault://kv-prod-weu@secrets=app-password"))
+	assert.False(t, m.Matches("az-c-keyvault://kv-test-weu@secrets=app-password"))
+	assert.False(t, m.Matches("az-c-keyvault://kv-prod-weu@keys=app-password"))
+}
+
+func TestParsePlacementConstraint_GlobDoesNotCrossDestinationTypes(t *testing.T) {
+	m, err := ParsePlacementConstraint("glob:az-c-label:///subscriptions/*/resourceGroups/*/providers/Microsoft.ApiManagement/service/*/namedValues/*")
+	assert.Nil(t, err)
+	assert.True(t, m.Matches("az-c-label:///subscriptions/s/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/namedValues/nv"))
+	assert.False(t, m.Matches("az-c-keyvault:///subscriptions/s/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/namedValues/nv"))
+}
+
+func TestParsePlacementConstraint_Regex(t *testing.T) {
+	m, err := ParsePlacementConstraint("regex:az-c-keyvault://kv-prod-[0-9]+@secrets=app-.*")
+	assert.Nil(t, err)
+	assert.Equal(t, "regex", m.Kind())
+	assert.True(t, m.Matches("az-c-keyvault://kv-prod-01@secrets=app-password"))
+	assert.False(t, m.Matches("az-c-keyvault://kv-prod-weu@secrets=app-password"))
+	// The expression is anchored
+	assert.False(t, m.Matches("az-c-keyvault://old-kv-prod-01@secrets=app-password"))
+}
+
+func TestParsePlacementConstraint_Invalid(t *testing.T) {
+	_, err := ParsePlacementConstraint("glob:kv-prod-*@secrets=app-*")
+	assert.NotNil(t, err)
+
+	_, err = ParsePlacementConstraint("glob:az-c-*://kv-prod-*@secrets=app-*")
+	assert.NotNil(t, err)
+
+	_, err = ParsePlacementConstraint("regex:az-c-keyvault://kv-prod-(@secrets=app-.*")
+	assert.NotNil(t, err)
+
+	_, err = ParsePlacementConstraints([]PlacementConstraint{"az-c-keyvault://kv@secrets=a", "glob:az-c-keyvault://"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "glob:az-c-keyvault://")
+}
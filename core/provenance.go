@@ -0,0 +1,177 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Provenance tags are stamped by the provider on the Azure objects it creates from the ciphertexts. These
+// identify the ciphertext the object was created from; the provider ignores these when comparing the tags
+// of the object with the tags of the configuration.
+const (
+	ProvenanceTagPrefix    = "az-confidential-"
+	ProvenanceUuidTag      = ProvenanceTagPrefix + "uuid"
+	ProvenanceSha256Tag    = ProvenanceTagPrefix + "sha256"
+	ProvenanceLabelsTag    = ProvenanceTagPrefix + "labels"
+	ProvenanceTimestampTag = ProvenanceTagPrefix + "timestamp"
+)
+
+// NewProvenanceTags returns the provenance tags of the object created from the ciphertext at the given time.
+// The ciphertext is the base64-encoded envelope as given in the configuration.
+func NewProvenanceTags(uuid string, ciphertext string, providerLabels []string, at time.Time) map[string]string {
+	digest := sha256.Sum256([]byte(ciphertext))
+
+	rv := map[string]string{
+		ProvenanceUuidTag:      uuid,
+		ProvenanceSha256Tag:    hex.EncodeToString(digest[:]),
+		ProvenanceTimestampTag: at.UTC().Format(time.RFC3339),
+	}
+
+	if len(providerLabels) > 0 {
+		labels := append([]string{}, providerLabels...)
+		sort.Strings(labels)
+		rv[ProvenanceLabelsTag] = strings.Join(labels, ",")
+	}
+
+	return rv
+}
+
+// IsProvenanceTag whether the tag name is the name of the provenance tag.
+func IsProvenanceTag(name string) bool {
+	return strings.HasPrefix(name, ProvenanceTagPrefix)
+}
+
+// SplitProvenanceTags splits the tags of an Azure object into the user tags and the provenance tags.
+func SplitProvenanceTags(tags map[string]*string) (map[string]*string, map[string]*string) {
+	var userTags, provenanceTags map[string]*string
+
+	for k, v := range tags {
+		if IsProvenanceTag(k) {
+			if provenanceTags == nil {
+				provenanceTags = map[string]*string{}
+			}
+			provenanceTags[k] = v
+		} else {
+			if userTags == nil {
+				userTags = map[string]*string{}
+			}
+			userTags[k] = v
+		}
+	}
+
+	return userTags, provenanceTags
+}
+
+// ProvenanceTagsOf the provenance tags among the tags of an Azure object, or nil where the object carries none.
+func ProvenanceTagsOf(tags map[string]*string) map[string]string {
+	_, provenanceTags := SplitProvenanceTags(tags)
+	if provenanceTags == nil {
+		return nil
+	}
+
+	rv := make(map[string]string, len(provenanceTags))
+	for k, v := range provenanceTags {
+		if v != nil {
+			rv[k] = *v
+		}
+	}
+
+	return rv
+}
+
+var invalidListTagChars = regexp.MustCompile("[^a-zA-Z0-9._-]")
+
+// maxListTagLength the maximum length of a tag Azure services accept for the objects that are tagged with
+// a list of names (e.g. API Management named values).
+const maxListTagLength = 80
+
+// compactTimestampFormat the timestamp in the list tags: RFC3339 uses colons that the tag names don't allow
+const compactTimestampFormat = "20060102T150405Z"
+
+// listTagDigestEncoding the encoding of the digest in the list tags: the hex-encoded SHA-256 digest doesn't fit
+// into the tag name together with the name of the tag
+var listTagDigestEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// encodeListTagValue encodes the value of the provenance tag so that it fits into the tag name unchanged. The
+// values of the other tags (i.e. the labels) are sanitized and truncated.
+func encodeListTagValue(name, value string) string {
+	switch name {
+	case ProvenanceSha256Tag:
+		if digest, err := hex.DecodeString(value); err == nil {
+			return strings.ToLower(listTagDigestEncoding.EncodeToString(digest))
+		}
+	case ProvenanceTimestampTag:
+		if at, err := time.Parse(time.RFC3339, value); err == nil {
+			return at.UTC().Format(compactTimestampFormat)
+		}
+	}
+
+	return invalidListTagChars.ReplaceAllString(value, "-")
+}
+
+// decodeListTagValue decodes the value of the provenance tag encoded by encodeListTagValue. The value that
+// cannot be decoded is returned as-is.
+func decodeListTagValue(name, value string) string {
+	switch name {
+	case ProvenanceSha256Tag:
+		if digest, err := listTagDigestEncoding.DecodeString(strings.ToUpper(value)); err == nil {
+			return hex.EncodeToString(digest)
+		}
+	case ProvenanceTimestampTag:
+		if at, err := time.Parse(compactTimestampFormat, value); err == nil {
+			return at.Format(time.RFC3339)
+		}
+	}
+
+	return value
+}
+
+// ProvenanceTagsAsList converts the provenance tags into the list of tag names for the objects that are tagged
+// with names rather than name/value pairs (e.g. API Management named values). Each tag is encoded as
+// `<name>.<value>`. The SHA-256 digest is encoded with base32 and the timestamp as `20060102T150405Z`, so that
+// these fit into the tag name and are read back unchanged by ProvenanceTagsOfList. In the provider labels,
+// the characters not allowed in the tag names are replaced with dashes; the labels that are too long to fit
+// into the tag name are truncated.
+func ProvenanceTagsAsList(tags map[string]string) []string {
+	var rv []string
+	for k, v := range tags {
+		tag := k + "." + encodeListTagValue(k, v)
+		if len(tag) > maxListTagLength {
+			tag = tag[:maxListTagLength]
+		}
+		rv = append(rv, tag)
+	}
+
+	sort.Strings(rv)
+	return rv
+}
+
+// ProvenanceTagsOfList reads the provenance tags from the list of tag names, as encoded by ProvenanceTagsAsList.
+// The tags that are not provenance tags are returned separately.
+func ProvenanceTagsOfList(tags []string) ([]string, map[string]string) {
+	var userTags []string
+	var provenanceTags map[string]string
+
+	for _, tag := range tags {
+		if !IsProvenanceTag(tag) {
+			userTags = append(userTags, tag)
+			continue
+		}
+
+		if provenanceTags == nil {
+			provenanceTags = map[string]string{}
+		}
+		if name, value, found := strings.Cut(tag, "."); found {
+			provenanceTags[name] = decodeListTagValue(name, value)
+		} else {
+			provenanceTags[tag] = ""
+		}
+	}
+
+	return userTags, provenanceTags
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvenanceTags(t *testing.T) {
+	at := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+	tags := NewProvenanceTags("abc-123", "ciphertext", []string{"prod", "acme"}, at)
+
+	assert.Equal(t, "abc-123", tags[ProvenanceUuidTag])
+	assert.Equal(t, "305531dcc50ebca31cf1d5b31e9fc76ed51f66b3b6dd5a030c6539ae6532f979", tags[ProvenanceSha256Tag])
+	assert.Equal(t, "acme,prod", tags[ProvenanceLabelsTag])
+	assert.Equal(t, "2026-01-31T10:00:00Z", tags[ProvenanceTimestampTag])
+
+	_, hasLabels := NewProvenanceTags("abc-123", "ciphertext", nil, at)[ProvenanceLabelsTag]
+	assert.False(t, hasLabels)
+}
+
+func TestSplitProvenanceTags(t *testing.T) {
+	v := "v"
+	userTags, provenanceTags := SplitProvenanceTags(map[string]*string{
+		"env":             &v,
+		ProvenanceUuidTag: &v,
+	})
+
+	assert.Equal(t, map[string]*string{"env": &v}, userTags)
+	assert.Equal(t, map[string]*string{ProvenanceUuidTag: &v}, provenanceTags)
+
+	userTags, provenanceTags = SplitProvenanceTags(nil)
+	assert.Nil(t, userTags)
+	assert.Nil(t, provenanceTags)
+}
+
+func TestProvenanceTagsAsList(t *testing.T) {
+	tags := ProvenanceTagsAsList(map[string]string{
+		ProvenanceUuidTag:      "abc-123",
+		ProvenanceTimestampTag: "2026-01-31T10:00:00Z",
+		ProvenanceLabelsTag:    "acme,prod",
+	})
+
+	assert.Equal(t, []string{
+		"az-confidential-labels.acme-prod",
+		"az-confidential-timestamp.20260131T100000Z",
+		"az-confidential-uuid.abc-123",
+	}, tags)
+
+	userTags, provenanceTags := ProvenanceTagsOfList(append(tags, "env"))
+	assert.Equal(t, []string{"env"}, userTags)
+	assert.Equal(t, "abc-123", provenanceTags[ProvenanceUuidTag])
+	assert.Equal(t, "2026-01-31T10:00:00Z", provenanceTags[ProvenanceTimestampTag])
+}
+
+func TestProvenanceTagsAsList_RoundTripsApimTags(t *testing.T) {
+	at := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+	expected := NewProvenanceTags("3d6d2a2e-6b7f-4b8e-9f0a-6a1f2b3c4d5e", "ciphertext", nil, at)
+
+	tags := ProvenanceTagsAsList(expected)
+	for _, tag := range tags {
+		assert.LessOrEqual(t, len(tag), maxListTagLength)
+		assert.False(t, invalidListTagChars.MatchString(tag), tag)
+	}
+
+	// API management returns the tags of the named value in no particular order, next to the user tags
+	apimTags := append([]string{"env-prod"}, tags...)
+	apimTags[0], apimTags[len(apimTags)-1] = apimTags[len(apimTags)-1], apimTags[0]
+
+	userTags, provenanceTags := ProvenanceTagsOfList(apimTags)
+	assert.Equal(t, []string{"env-prod"}, userTags)
+	assert.Equal(t, expected, provenanceTags)
+	assert.Equal(t, "305531dcc50ebca31cf1d5b31e9fc76ed51f66b3b6dd5a030c6539ae6532f979", provenanceTags[ProvenanceSha256Tag])
+}
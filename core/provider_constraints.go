@@ -0,0 +1,215 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The provider constraint groups are encoded as prefixed provider constraints, e.g. all-of:prod|weu. The
+// provider that does not understand the groups would treat these as plain labels it isn't labelled with, and
+// would refuse to create the objects from such ciphertext.
+const (
+	ProviderAllOfPrefix  = "all-of:"
+	ProviderAnyOfPrefix  = "any-of:"
+	ProviderNoneOfPrefix = "none-of:"
+
+	providerGroupSeparator = "|"
+)
+
+// The Azure bindings restrict the ciphertext to the Azure tenants and/or subscriptions the provider runs in,
+// e.g. azure-tenant:00000000-0000-0000-0000-000000000000. Similar to the groups, the provider that does not
+// understand the bindings would refuse to create the objects from such ciphertext.
+const (
+	AzureTenantPrefix       = "azure-tenant:"
+	AzureSubscriptionPrefix = "azure-subscription:"
+)
+
+// AllOfProviderConstraint the provider must bear all the labels.
+func AllOfProviderConstraint(labels ...string) ProviderConstraint {
+	return ProviderConstraint(ProviderAllOfPrefix + strings.Join(labels, providerGroupSeparator))
+}
+
+// AnyOfProviderConstraint the provider must bear at least one of the labels.
+func AnyOfProviderConstraint(labels ...string) ProviderConstraint {
+	return ProviderConstraint(ProviderAnyOfPrefix + strings.Join(labels, providerGroupSeparator))
+}
+
+// NoneOfProviderConstraint the provider must bear none of the labels.
+func NoneOfProviderConstraint(labels ...string) ProviderConstraint {
+	return ProviderConstraint(ProviderNoneOfPrefix + strings.Join(labels, providerGroupSeparator))
+}
+
+// AzureTenantConstraint the credential of the provider must belong to the tenant. Where several tenants
+// are given, the credential must belong to any of these.
+func AzureTenantConstraint(tenantId string) ProviderConstraint {
+	return ProviderConstraint(AzureTenantPrefix + tenantId)
+}
+
+// AzureSubscriptionConstraint the default subscription of the provider must be the given subscription. Where
+// several subscriptions are given, the default subscription must be any of these.
+func AzureSubscriptionConstraint(subscriptionId string) ProviderConstraint {
+	return ProviderConstraint(AzureSubscriptionPrefix + subscriptionId)
+}
+
+// IsAzureBinding whether the provider constraint binds the ciphertext to an Azure tenant or subscription.
+func (p ProviderConstraint) IsAzureBinding() bool {
+	s := string(p)
+	return strings.HasPrefix(s, AzureTenantPrefix) || strings.HasPrefix(s, AzureSubscriptionPrefix)
+}
+
+// IsGroup whether the provider constraint is a constraint group rather than a plain label.
+func (p ProviderConstraint) IsGroup() bool {
+	s := string(p)
+	return strings.HasPrefix(s, ProviderAllOfPrefix) ||
+		strings.HasPrefix(s, ProviderAnyOfPrefix) ||
+		strings.HasPrefix(s, ProviderNoneOfPrefix)
+}
+
+// ProviderConstraintExpression the provider constraints of the ciphertext. The plain labels retain their
+// original meaning: the provider must bear at least one of these. The groups further require the
+// provider to bear all labels of each all-of group, at least one label of each any-of group, and
+// no labels of any none-of group. The Azure bindings are checked against the tenant and the subscription
+// of the provider rather than its labels.
+type ProviderConstraintExpression struct {
+	Labels []string
+	AllOf  [][]string
+	AnyOf  [][]string
+	NoneOf [][]string
+
+	AzureTenants       []string
+	AzureSubscriptions []string
+}
+
+// ParseProviderConstraints builds the expression out of the provider constraints of the ciphertext.
+func ParseProviderConstraints(constraints []ProviderConstraint) (ProviderConstraintExpression, error) {
+	rv := ProviderConstraintExpression{}
+
+	for _, c := range constraints {
+		s := string(c)
+
+		if id, ok := strings.CutPrefix(s, AzureTenantPrefix); ok {
+			if len(id) == 0 {
+				return rv, fmt.Errorf("provider constraint %s does not specify the tenant", s)
+			}
+			rv.AzureTenants = append(rv.AzureTenants, id)
+			continue
+		} else if id, ok = strings.CutPrefix(s, AzureSubscriptionPrefix); ok {
+			if len(id) == 0 {
+				return rv, fmt.Errorf("provider constraint %s does not specify the subscription", s)
+			}
+			rv.AzureSubscriptions = append(rv.AzureSubscriptions, id)
+			continue
+		}
+
+		var target *[][]string
+		var prefix string
+		if strings.HasPrefix(s, ProviderAllOfPrefix) {
+			target, prefix = &rv.AllOf, ProviderAllOfPrefix
+		} else if strings.HasPrefix(s, ProviderAnyOfPrefix) {
+			target, prefix = &rv.AnyOf, ProviderAnyOfPrefix
+		} else if strings.HasPrefix(s, ProviderNoneOfPrefix) {
+			target, prefix = &rv.NoneOf, ProviderNoneOfPrefix
+		} else {
+			rv.Labels = append(rv.Labels, s)
+			continue
+		}
+
+		group := strings.Split(strings.TrimPrefix(s, prefix), providerGroupSeparator)
+		if Contains("", group) {
+			return rv, fmt.Errorf("provider constraint %s contains an empty label", s)
+		}
+		*target = append(*target, group)
+	}
+
+	return rv, nil
+}
+
+// RequiresLabels whether the provider must be labelled to satisfy the expression.
+func (e ProviderConstraintExpression) RequiresLabels() bool {
+	return len(e.Labels) > 0 || len(e.AllOf) > 0 || len(e.AnyOf) > 0
+}
+
+// BindsAzureTenant whether the ciphertext can be used only within specific Azure tenants.
+func (e ProviderConstraintExpression) BindsAzureTenant() bool {
+	return len(e.AzureTenants) > 0
+}
+
+// BindsAzureSubscription whether the ciphertext can be used only within specific Azure subscriptions.
+func (e ProviderConstraintExpression) BindsAzureSubscription() bool {
+	return len(e.AzureSubscriptions) > 0
+}
+
+// AllowsAzureTenant whether the ciphertext may be used in the tenant. Tenant ids are compared case-insensitively.
+func (e ProviderConstraintExpression) AllowsAzureTenant(tenantId string) bool {
+	return !e.BindsAzureTenant() || containsFold(e.AzureTenants, tenantId)
+}
+
+// AllowsAzureSubscription whether the ciphertext may be used in the subscription. Subscription ids are
+// compared case-insensitively.
+func (e ProviderConstraintExpression) AllowsAzureSubscription(subscriptionId string) bool {
+	return !e.BindsAzureSubscription() || containsFold(e.AzureSubscriptions, subscriptionId)
+}
+
+func containsFold(values []string, v string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSatisfiedBy whether the provider labels satisfy the expression.
+func (e ProviderConstraintExpression) IsSatisfiedBy(providerLabels []string) bool {
+	if len(e.Labels) > 0 && !AnyIsIn(e.Labels, providerLabels) {
+		return false
+	}
+
+	for _, group := range e.AllOf {
+		for _, label := range group {
+			if !Contains(label, providerLabels) {
+				return false
+			}
+		}
+	}
+
+	for _, group := range e.AnyOf {
+		if !AnyIsIn(group, providerLabels) {
+			return false
+		}
+	}
+
+	for _, group := range e.NoneOf {
+		if AnyIsIn(group, providerLabels) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Describe lists the conditions of the expression in a human-readable form.
+func (e ProviderConstraintExpression) Describe() []string {
+	var rv []string
+
+	if len(e.Labels) > 0 {
+		rv = append(rv, fmt.Sprintf("any of: %s", strings.Join(e.Labels, ", ")))
+	}
+	for _, group := range e.AllOf {
+		rv = append(rv, fmt.Sprintf("all of: %s", strings.Join(group, ", ")))
+	}
+	for _, group := range e.AnyOf {
+		rv = append(rv, fmt.Sprintf("any of: %s", strings.Join(group, ", ")))
+	}
+	for _, group := range e.NoneOf {
+		rv = append(rv, fmt.Sprintf("none of: %s", strings.Join(group, ", ")))
+	}
+	if e.BindsAzureTenant() {
+		rv = append(rv, fmt.Sprintf("Azure tenant is any of: %s", strings.Join(e.AzureTenants, ", ")))
+	}
+	if e.BindsAzureSubscription() {
+		rv = append(rv, fmt.Sprintf("Azure subscription is any of: %s", strings.Join(e.AzureSubscriptions, ", ")))
+	}
+
+	return rv
+}
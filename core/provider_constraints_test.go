@@ -0,0 +1,66 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseProviderConstraints_FlatLabelsRetainAnyOfSemantics(t *testing.T) {
+	expr, err := ParseProviderConstraints([]ProviderConstraint{"prod", "uat"})
+	assert.Nil(t, err)
+	assert.True(t, expr.RequiresLabels())
+	assert.True(t, expr.IsSatisfiedBy([]string{"uat"}))
+	assert.False(t, expr.IsSatisfiedBy([]string{"dev"}))
+}
+
+func TestParseProviderConstraints_AllOf(t *testing.T) {
+	expr, err := ParseProviderConstraints([]ProviderConstraint{AllOfProviderConstraint("prod", "weu")})
+	assert.Nil(t, err)
+	assert.True(t, expr.IsSatisfiedBy([]string{"prod", "weu", "other"}))
+	assert.False(t, expr.IsSatisfiedBy([]string{"prod"}))
+	assert.Equal(t, []string{"all of: prod, weu"}, expr.Describe())
+}
+
+func TestParseProviderConstraints_AnyOfGroupsAreConjoined(t *testing.T) {
+	expr, err := ParseProviderConstraints([]ProviderConstraint{
+		AnyOfProviderConstraint("prod", "uat"),
+		AnyOfProviderConstraint("weu", "neu"),
+	})
+	assert.Nil(t, err)
+	assert.True(t, expr.IsSatisfiedBy([]string{"uat", "neu"}))
+	assert.False(t, expr.IsSatisfiedBy([]string{"uat"}))
+}
+
+func TestParseProviderConstraints_NoneOf(t *testing.T) {
+	expr, err := ParseProviderConstraints([]ProviderConstraint{"prod", NoneOfProviderConstraint("sandbox")})
+	assert.Nil(t, err)
+	assert.True(t, expr.IsSatisfiedBy([]string{"prod"}))
+	assert.False(t, expr.IsSatisfiedBy([]string{"prod", "sandbox"}))
+
+	noneOnly, err := ParseProviderConstraints([]ProviderConstraint{NoneOfProviderConstraint("sandbox")})
+	assert.Nil(t, err)
+	assert.False(t, noneOnly.RequiresLabels())
+	assert.True(t, noneOnly.IsSatisfiedBy(nil))
+}
+
+func TestParseProviderConstraints_EmptyLabel(t *testing.T) {
+	_, err := ParseProviderConstraints([]ProviderConstraint{"all-of:prod|"})
+	assert.NotNil(t, err)
+}
+
+func TestParseProviderConstraints_AzureBindings(t *testing.T) {
+	expr, err := ParseProviderConstraints([]ProviderConstraint{
+		AzureTenantConstraint("11111111-1111-1111-1111-111111111111"),
+		AzureSubscriptionConstraint("33333333-3333-3333-3333-333333333333"),
+	})
+	assert.Nil(t, err)
+	assert.False(t, expr.RequiresLabels())
+	assert.True(t, expr.IsSatisfiedBy(nil))
+	assert.True(t, expr.AllowsAzureTenant("11111111-1111-1111-1111-111111111111"))
+	assert.False(t, expr.AllowsAzureTenant("22222222-2222-2222-2222-222222222222"))
+	assert.True(t, expr.AllowsAzureSubscription("33333333-3333-3333-3333-333333333333"))
+	assert.Equal(t, 2, len(expr.Describe()))
+
+	_, err = ParseProviderConstraints([]ProviderConstraint{AzureTenantConstraint("")})
+	assert.NotNil(t, err)
+}
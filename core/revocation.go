@@ -0,0 +1,9 @@
+package core
+
+// CiphertextRevocation the record of a revoked ciphertext. The provider refuses to use the revoked
+// ciphertext regardless of its other protection parameters.
+type CiphertextRevocation struct {
+	Uuid      string `json:"uuid"`
+	Reason    string `json:"reason"`
+	RevokedAt string `json:"revoked_at,omitempty"`
+}
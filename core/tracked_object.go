@@ -0,0 +1,20 @@
+package core
+
+// TrackedObject the use of the ciphertext recorded by the object tracker. The timestamps are given in
+// RFC3339 format. The destinations are the labels of the destinations where the ciphertext was placed, in
+// the order of placement; the destination is the label where the ciphertext was first placed. These are
+// empty where the tracker didn't record the destinations.
+type TrackedObject struct {
+	Uuid         string
+	NumUses      int
+	FirstUsedAt  string
+	LastUsedAt   string
+	Destination  string
+	Destinations []string
+}
+
+// IsPlacedAt whether the ciphertext was placed at the destination. The ciphertext without recorded destinations
+// is considered to be placed anywhere.
+func (o TrackedObject) IsPlacedAt(label string) bool {
+	return len(o.Destinations) == 0 || Contains(label, o.Destinations)
+}
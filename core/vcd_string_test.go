@@ -65,3 +65,34 @@ func Test_VersionDataToEncryptedMessageConversionWithBase64(t *testing.T) {
 	assert.Equal(t, cm.Header.Type, origSecret.Header.Type)
 	assert.Equal(t, cm.Header.ModelReference, origSecret.Header.ModelReference)
 }
+
+func Test_UnverifiedHeaderOf_RoundTripsConstraintsWithCommas(t *testing.T) {
+	h := NewVersionedStringConfidentialDataHelper("example")
+	h.CreateConfidentialStringData("this is a secret", SecondaryProtectionParameters{
+		ProviderConstraints:  []ProviderConstraint{"test", "demo"},
+		PlacementConstraints: []PlacementConstraint{"az-c-label:///app-[a-z]{2,4}", "az-c-label:///shared"},
+	})
+
+	em, err := h.ToEncryptedMessage(LoadedEphemeralRsaPublicKey)
+	assert.Nil(t, err)
+
+	rbEm := EncryptedMessage{}
+	assert.Nil(t, rbEm.FromBase64PEM(em.ToBase64PEM()))
+
+	header := UnverifiedHeaderOf(rbEm)
+	assert.Equal(t, []ProviderConstraint{"test", "demo"}, header.ProviderConstraints)
+	assert.Equal(t, []PlacementConstraint{"az-c-label:///app-[a-z]{2,4}", "az-c-label:///shared"}, header.PlacementConstraints)
+}
+
+func Test_UnverifiedHeaderOf_ReadsCommaSeparatedConstraints(t *testing.T) {
+	em := EncryptedMessage{
+		headers: map[string]string{
+			"ProviderConstraints":  "test,demo",
+			"PlacementConstraints": "",
+		},
+	}
+
+	header := UnverifiedHeaderOf(em)
+	assert.Equal(t, []ProviderConstraint{"test", "demo"}, header.ProviderConstraints)
+	assert.Nil(t, header.PlacementConstraints)
+}
@@ -5,6 +5,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ type SecondaryProtectionParameters struct {
 	ProviderConstraints  []ProviderConstraint
 	PlacementConstraints []PlacementConstraint
 	CreateLimit          int64
+	NotBefore            int64
 	Expiry               int64
 	NumUses              int
 }
@@ -24,6 +26,7 @@ type SecondaryProtectionParameters struct {
 func (p *SecondaryProtectionParameters) SameAs(other SecondaryProtectionParameters) bool {
 	return p.NumUses == other.NumUses &&
 		p.CreateLimit == other.CreateLimit &&
+		p.NotBefore == other.NotBefore &&
 		p.Expiry == other.Expiry &&
 		SameBag(func(a, b PlacementConstraint) bool { return a == b }, p.PlacementConstraints, other.PlacementConstraints) &&
 		SameBag(func(a, b ProviderConstraint) bool { return a == b }, p.ProviderConstraints, other.ProviderConstraints)
@@ -37,10 +40,42 @@ func (p *SecondaryProtectionParameters) HasPlacementConstraints() bool {
 	return len(p.PlacementConstraints) > 0
 }
 
+// HasProviderConstraintGroups whether any provider constraint is an all-of, any-of, or none-of group, or
+// an Azure tenant or subscription binding.
+func (p *SecondaryProtectionParameters) HasProviderConstraintGroups() bool {
+	for _, c := range p.ProviderConstraints {
+		if c.IsGroup() || c.IsAzureBinding() {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeProviderConstraints lists the conditions the provider labels must satisfy.
+func (p *SecondaryProtectionParameters) DescribeProviderConstraints() []string {
+	expr, _ := ParseProviderConstraints(p.ProviderConstraints)
+	return expr.Describe()
+}
+
+// HasPlacementPatterns whether any placement constraint is a glob or a regular expression.
+func (p *SecondaryProtectionParameters) HasPlacementPatterns() bool {
+	for _, c := range p.PlacementConstraints {
+		if c.IsPattern() {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *SecondaryProtectionParameters) LimitsCreate() bool {
 	return p.CreateLimit > 0
 }
 
+// LimitsActivation whether the ciphertext cannot be used before a certain date.
+func (p *SecondaryProtectionParameters) LimitsActivation() bool {
+	return p.NotBefore > 0
+}
+
 func (p *SecondaryProtectionParameters) LimitsExpiry() bool {
 	return p.Expiry > 0
 }
@@ -66,6 +101,10 @@ func (p *SecondaryProtectionParameters) formatUnixTimestamp(limit int64) string
 	}
 }
 
+func (p *SecondaryProtectionParameters) GetNotBeforeTimestamp() string {
+	return p.formatUnixTimestamp(p.NotBefore)
+}
+
 func (p *SecondaryProtectionParameters) GetExpiryTimestamp() string {
 	return p.formatUnixTimestamp(p.Expiry)
 }
@@ -109,6 +148,7 @@ func (vcd *VersionedConfidentialDataHelperTemplate[T, TAtRest]) Set(p VersionedC
 		Uuid:                 uuid.New().String(),
 		Type:                 vcd.ObjectType,
 		CreateLimit:          p.CreateLimit,
+		NotBefore:            p.NotBefore,
 		Expiry:               p.Expiry,
 		ProviderConstraints:  p.ProviderConstraints,
 		PlacementConstraints: p.PlacementConstraints,
@@ -201,25 +241,15 @@ func (vcd *VersionedConfidentialDataHelperTemplate[T, TAtRest]) FromEncryptedMes
 func (vcd *VersionedConfidentialDataHelperTemplate[T, TAtRest]) ToEncryptedMessage(rsaKey *rsa.PublicKey) (EncryptedMessage, error) {
 	rv := EncryptedMessage{
 		headers: map[string]string{
-			"CreateLimit": fmt.Sprintf("%d", vcd.Header.CreateLimit),
-			"Expiry":      fmt.Sprintf("%d", vcd.Header.Expiry),
-			"ProviderConstraints": strings.Join(
-				MapSlice(
-					func(constraint ProviderConstraint) string { return string(constraint) },
-					vcd.Header.ProviderConstraints,
-				),
-				",",
-			),
-			"PlacementConstraints": strings.Join(
-				MapSlice(
-					func(constraint PlacementConstraint) string { return string(constraint) },
-					vcd.Header.PlacementConstraints,
-				),
-				",",
-			),
-			"NumUses":        fmt.Sprintf("%d", vcd.Header.NumUses),
-			"Type":           vcd.Header.Type,
-			"ModelReference": vcd.Header.ModelReference,
+			"CreateLimit":          fmt.Sprintf("%d", vcd.Header.CreateLimit),
+			"NotBefore":            fmt.Sprintf("%d", vcd.Header.NotBefore),
+			"Expiry":               fmt.Sprintf("%d", vcd.Header.Expiry),
+			"ProviderConstraints":  constraintsHeaderOf(vcd.Header.ProviderConstraints),
+			"PlacementConstraints": constraintsHeaderOf(vcd.Header.PlacementConstraints),
+			"Uuid":                 vcd.Header.Uuid,
+			"NumUses":              fmt.Sprintf("%d", vcd.Header.NumUses),
+			"Type":                 vcd.Header.Type,
+			"ModelReference":       vcd.Header.ModelReference,
 		},
 	}
 	exportedBytes, exportErr := vcd.Export()
@@ -251,3 +281,72 @@ func (vcd *VersionedConfidentialDataHelperTemplate[T, TAtRest]) Import(msg json.
 	vcd.KnowValue = vcd.RestToValue(jsonMdl)
 	return vcd.KnowValue, nil
 }
+
+// UnverifiedHeaderOf reconstructs the header from the unencrypted PEM headers of the message. These
+// headers are not authenticated; the values must only be used where the ciphertext cannot be decrypted.
+func UnverifiedHeaderOf(em EncryptedMessage) ConfidentialDataJsonHeader {
+	rv := ConfidentialDataJsonHeader{
+		Uuid:           em.GetHeader("Uuid"),
+		Type:           em.GetHeader("Type"),
+		ModelReference: em.GetHeader("ModelReference"),
+	}
+
+	rv.CreateLimit, _ = strconv.ParseInt(em.GetHeader("CreateLimit"), 10, 64)
+	rv.NotBefore, _ = strconv.ParseInt(em.GetHeader("NotBefore"), 10, 64)
+	rv.Expiry, _ = strconv.ParseInt(em.GetHeader("Expiry"), 10, 64)
+	rv.NumUses, _ = strconv.Atoi(em.GetHeader("NumUses"))
+
+	rv.ProviderConstraints = constraintsFromHeader[ProviderConstraint](em.GetHeader("ProviderConstraints"))
+	rv.PlacementConstraints = constraintsFromHeader[PlacementConstraint](em.GetHeader("PlacementConstraints"))
+
+	return rv
+}
+
+// constraintsHeaderOf encodes the constraints as the JSON array, as the constraints (e.g. the regular
+// expressions) may contain commas.
+func constraintsHeaderOf[T ~string](constraints []T) string {
+	if len(constraints) == 0 {
+		return ""
+	}
+
+	data, _ := json.Marshal(constraints)
+	return string(data)
+}
+
+// constraintsFromHeader decodes the constraints header. The ciphertexts produced before the constraints were
+// encoded as the JSON array carry the comma-separated list.
+func constraintsFromHeader[T ~string](v string) []T {
+	if len(v) == 0 {
+		return nil
+	}
+
+	var rv []T
+	if strings.HasPrefix(v, "[") && json.Unmarshal([]byte(v), &rv) == nil {
+		return rv
+	}
+
+	rv = nil
+	for _, s := range strings.Split(v, ",") {
+		rv = append(rv, T(s))
+	}
+	return rv
+}
+
+// DecryptConfidentialDataMessage decrypts the message without interpreting the confidential data
+// it contains.
+func DecryptConfidentialDataMessage(em EncryptedMessage, decrypter RSADecrypter) (ConfidentialDataMessageJson, error) {
+	rv := ConfidentialDataMessageJson{}
+
+	plainText, decryptErr := em.ExtractPlainText(decrypter)
+	if decryptErr != nil {
+		return rv, decryptErr
+	}
+
+	gzip, gzipErr := GZipDecompress(plainText)
+	if gzipErr != nil {
+		return rv, gzipErr
+	}
+
+	jsonErr := json.Unmarshal(gzip, &rv)
+	return rv, jsonErr
+}
@@ -0,0 +1,6 @@
+data "az-confidential_general_tracked_ciphertexts" "all" {
+}
+
+output "overused" {
+  value = [for c in data.az-confidential_general_tracked_ciphertexts.all.ciphertexts : c.uuid if c.num_uses > 1]
+}
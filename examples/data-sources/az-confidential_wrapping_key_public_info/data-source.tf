@@ -0,0 +1,10 @@
+data "az-confidential_wrapping_key_public_info" "default" {
+}
+
+output "wrapping_key_pem" {
+  value = data.az-confidential_wrapping_key_public_info.default.public_key_pem
+}
+
+output "wrapping_key_thumbprint" {
+  value = data.az-confidential_wrapping_key_public_info.default.thumbprint
+}
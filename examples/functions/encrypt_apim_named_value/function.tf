@@ -28,6 +28,7 @@ output "encrypted_named_value" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -42,6 +43,7 @@ output "encrypted_named_value_without_destination_lock" {
     null,
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
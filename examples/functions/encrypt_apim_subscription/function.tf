@@ -34,6 +34,7 @@ output "encrypted_named_value" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -51,6 +52,7 @@ output "encrypted_named_value_without_destination_lock" {
     null,
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -33,6 +33,7 @@ output "encrypted_plain_certificate" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -53,6 +54,7 @@ output "encrypted_protected_certificate" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -73,6 +75,7 @@ output "encrypted_der_certificate" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -34,6 +34,7 @@ output "encrypted_rsa_key" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -54,6 +55,7 @@ output "encrypted_ec_key" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -74,6 +76,7 @@ output "encrypted_der_rsa_key" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -94,6 +97,7 @@ output "encrypted_der_encrypted_rsa_key" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -26,6 +26,7 @@ output "encrypted_secret" {
     },
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
@@ -40,6 +41,7 @@ output "encrypted_secret_without_destination_lock" {
     null,
     {
       create_limit         = "72h"
+      not_before           = ""
       expires_in           = 200
       num_uses             = 10
       provider_constraints = toset(["test", "acceptance"])
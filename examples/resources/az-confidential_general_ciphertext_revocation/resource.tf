@@ -0,0 +1,18 @@
+# ----------------------------------------------------------------------------
+#
+# Ciphertext Revocation
+#
+# The resource records the revocation of the ciphertext in the object tracker.
+# The provider must consult the tracker for the revoked ciphertexts:
+#
+# provider "az-confidential" {
+#   revocation = {
+#     use_tracker = true
+#   }
+# }
+# ----------------------------------------------------------------------------
+
+resource "az-confidential_general_ciphertext_revocation" "leaked" {
+  uuid   = "3d6d2a2e-6b7f-4b8e-9f0a-6a1f2b3c4d5e"
+  reason = "leaked with pipeline logs"
+}
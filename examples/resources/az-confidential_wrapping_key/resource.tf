@@ -0,0 +1,28 @@
+# ----------------------------------------------------------------------------
+#
+# Wrapping Key
+#
+# The resource creates the HSM-backed RSA key permitting only decrypt and
+# unwrapKey operations that cannot be exported. Changing the values of
+# rotate_when_changed creates the new version of the key.
+# ----------------------------------------------------------------------------
+
+resource "az-confidential_wrapping_key" "kek" {
+  vault_name = "my-vault"
+  name       = "wrapping-key"
+  key_size   = 4096
+
+  key_operations = ["unwrapKey"]
+
+  tags = {
+    purpose = "confidential-terraform"
+  }
+
+  rotate_when_changed = {
+    rotation = "2026-Q4"
+  }
+}
+
+output "wrapping_key_pem" {
+  value = az-confidential_wrapping_key.kek.public_key_pem
+}
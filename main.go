@@ -32,6 +32,7 @@ func main() {
 	}
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	provider.ZeroiseCEKCaches()
 
 	if err != nil {
 		log.Fatal(err.Error())
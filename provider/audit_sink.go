@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+)
+
+type AuditSink interface {
+	// RecordEvent durably records the audit event
+	RecordEvent(ctx context.Context, event core.AuditEvent) error
+}
+
+// JsonLinesFileAuditSink appends audit events as JSON lines to a local file.
+type JsonLinesFileAuditSink struct {
+	FilePath string
+
+	mutex sync.Mutex
+}
+
+func (s *JsonLinesFileAuditSink) RecordEvent(_ context.Context, event core.AuditEvent) error {
+	line, marshalErr := json.Marshal(event)
+	if marshalErr != nil {
+		return fmt.Errorf("cannot marshal audit event: %s", marshalErr.Error())
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, openErr := os.OpenFile(s.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if openErr != nil {
+		return fmt.Errorf("cannot open audit file %s: %s", s.FilePath, openErr.Error())
+	}
+
+	_, writeErr := file.Write(append(line, '\n'))
+	closeErr := file.Close()
+
+	return errors.Join(writeErr, closeErr)
+}
+
+func NewJsonLinesFileAuditSink(filePath string) (*JsonLinesFileAuditSink, error) {
+	if len(filePath) == 0 {
+		return nil, errors.New("audit file path must not be empty")
+	}
+
+	return &JsonLinesFileAuditSink{
+		FilePath: filePath,
+	}, nil
+}
+
+// MultiAuditSink passes the events to several sinks, e.g. to the local file and to the storage account table.
+type MultiAuditSink []AuditSink
+
+func (m MultiAuditSink) RecordEvent(ctx context.Context, event core.AuditEvent) error {
+	var rv []error
+	for _, sink := range m {
+		rv = append(rv, sink.RecordEvent(ctx, event))
+	}
+
+	return errors.Join(rv...)
+}
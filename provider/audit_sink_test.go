@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuditSinkMock struct {
+	mock.Mock
+}
+
+func (m *AuditSinkMock) RecordEvent(ctx context.Context, event core.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func Test_JsonLinesFileAuditSink_AppendsEvents(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewJsonLinesFileAuditSink(filePath)
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	for _, op := range []core.AuditOperation{core.AuditOperationCreate, core.AuditOperationDelete} {
+		recErr := sink.RecordEvent(ctx, core.AuditEvent{
+			Timestamp:      time.Now(),
+			Operation:      op,
+			CiphertextUuid: "unit-test-uuid",
+			ObjectType:     "kv/secret",
+			Destination:    "az-c-keyvault://vault@secrets=name",
+			WrappingKey:    "vault/key/version (RSA-OAEP-256)",
+			Outcome:        core.AuditOutcomeSuccess,
+		})
+		assert.Nil(t, recErr)
+	}
+
+	file, openErr := os.Open(filePath)
+	assert.Nil(t, openErr)
+	defer file.Close()
+
+	var events []core.AuditEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		event := core.AuditEvent{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, core.AuditOperationCreate, events[0].Operation)
+	assert.Equal(t, core.AuditOperationDelete, events[1].Operation)
+	assert.Equal(t, "unit-test-uuid", events[1].CiphertextUuid)
+}
+
+func Test_JsonLinesFileAuditSink_RequiresPath(t *testing.T) {
+	_, err := NewJsonLinesFileAuditSink("")
+	assert.NotNil(t, err)
+}
+
+func Test_AZCF_RecordAuditEvent_IgnoredWithoutSink(t *testing.T) {
+	factory := &AZClientsFactoryImpl{}
+
+	dg := diag.Diagnostics{}
+	factory.RecordAuditEvent(context.Background(), core.AuditEvent{Operation: core.AuditOperationCreate}, nil, &dg)
+	assert.Equal(t, 0, len(dg))
+}
+
+func Test_AZCF_RecordAuditEvent_WarnsOnSinkError(t *testing.T) {
+	sink := &AuditSinkMock{}
+	sink.On("RecordEvent", mock.Anything, mock.MatchedBy(func(e core.AuditEvent) bool {
+		return e.Operation == core.AuditOperationCreate && e.WrappingKey == "unresolved" && !e.Timestamp.IsZero()
+	})).Return(errors.New("unit-test-error"))
+
+	factory := &AZClientsFactoryImpl{
+		auditSink: sink,
+	}
+
+	dg := diag.Diagnostics{}
+	factory.RecordAuditEvent(context.Background(), core.AuditEvent{Operation: core.AuditOperationCreate}, nil, &dg)
+
+	sink.AssertExpectations(t)
+	assert.False(t, dg.HasError())
+	assert.Equal(t, 1, dg.WarningsCount())
+}
+
+func Test_AZCF_GetDecrypterFor_RecordsFailedDecryption(t *testing.T) {
+	sink := &AuditSinkMock{}
+	sink.On("RecordEvent", mock.Anything, mock.MatchedBy(func(e core.AuditEvent) bool {
+		return e.Operation == core.AuditOperationDecrypt && e.Outcome == core.AuditOutcomeFailure && len(e.Message) > 0
+	})).Return(nil).Once()
+
+	factory := &AZClientsFactoryImpl{
+		auditSink: sink,
+	}
+
+	decrypter := factory.GetDecrypterFor(context.Background(), nil)
+	_, err := decrypter([]byte("ciphertext"))
+
+	assert.NotNil(t, err)
+	sink.AssertExpectations(t)
+}
+
+func Test_AZCF_GetDecrypterFor_AttributesDecryptionToAuditSubject(t *testing.T) {
+	sink := &AuditSinkMock{}
+	sink.On("RecordEvent", mock.Anything, mock.MatchedBy(func(e core.AuditEvent) bool {
+		return e.Operation == core.AuditOperationDecrypt &&
+			e.CiphertextUuid == "ciphertext-uuid" &&
+			e.ObjectType == "secret" &&
+			e.Destination == "vault/secret"
+	})).Return(nil).Once()
+
+	factory := &AZClientsFactoryImpl{
+		auditSink: sink,
+	}
+
+	ctx := core.WithAuditSubject(context.Background(), core.AuditSubject{
+		CiphertextUuid: "ciphertext-uuid",
+		ObjectType:     "secret",
+		Destination:    "vault/secret",
+	})
+	_, _ = factory.GetDecrypterFor(ctx, nil)([]byte("ciphertext"))
+
+	sink.AssertExpectations(t)
+}
+
+func Test_MultiAuditSink_PassesEventToAllSinks(t *testing.T) {
+	a := &AuditSinkMock{}
+	a.On("RecordEvent", mock.Anything, mock.Anything).Return(nil).Once()
+	b := &AuditSinkMock{}
+	b.On("RecordEvent", mock.Anything, mock.Anything).Return(errors.New("unit-test-error")).Once()
+
+	err := MultiAuditSink{a, b}.RecordEvent(context.Background(), core.AuditEvent{})
+	assert.NotNil(t, err)
+
+	a.AssertExpectations(t)
+	b.AssertExpectations(t)
+}
+
+// Test_AZTAAS_Integration runs against the table endpoint specified by the connection string, e.g.
+// Azurite's "UseDevelopmentStorage=true". The table must exist.
+func Test_AZTAAS_Integration(t *testing.T) {
+	connStr := os.Getenv("AZ_AUDIT_TABLE_CONNECTION_STRING")
+	tableName := os.Getenv("AZ_AUDIT_TABLE_NAME")
+	if len(connStr) == 0 || len(tableName) == 0 {
+		t.Skip("Az Table audit sink integration test skipped: no connection string set")
+	}
+
+	sink, err := NewAzStorageAccountTableAuditSink(nil, connStr, "", tableName, "acctest")
+	assert.Nil(t, err)
+
+	recErr := sink.RecordEvent(context.Background(), core.AuditEvent{
+		Timestamp:      time.Now(),
+		Operation:      core.AuditOperationCreate,
+		CiphertextUuid: "integration-test-uuid",
+		Outcome:        core.AuditOutcomeSuccess,
+	})
+	assert.Nil(t, recErr)
+}
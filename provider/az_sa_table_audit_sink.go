@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/data/aztables"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/google/uuid"
+)
+
+// AzStorageAccountTableAuditSink records audit events as entities in the Azure Storage Account table. The sink
+// connects either with the token credential to the named storage account, or with the connection string.
+// The latter allows running the sink against Azurite.
+type AzStorageAccountTableAuditSink struct {
+	Credential       azcore.TokenCredential
+	ConnectionString string
+
+	AccountName  string
+	TableName    string
+	PartitionKey string
+
+	service     *aztables.ServiceClient
+	tableClient *aztables.Client
+}
+
+func (a *AzStorageAccountTableAuditSink) getTableClient() (*aztables.Client, error) {
+	if a.service == nil {
+		var svc *aztables.ServiceClient
+		var initErr error
+
+		if len(a.ConnectionString) > 0 {
+			svc, initErr = aztables.NewServiceClientFromConnectionString(a.ConnectionString, nil)
+		} else {
+			svc, initErr = aztables.NewServiceClient(
+				fmt.Sprintf("https://%s.table.core.windows.net", a.AccountName),
+				a.Credential,
+				nil)
+		}
+
+		if initErr != nil {
+			return nil, fmt.Errorf("unable to create service client: %s", initErr.Error())
+		} else {
+			a.service = svc
+		}
+	}
+
+	if a.tableClient == nil {
+		a.tableClient = a.service.NewClient(a.TableName)
+	}
+
+	return a.tableClient, nil
+}
+
+// auditRowKey produces the row key ordering the most recent events first within the partition.
+func auditRowKey(ts time.Time) string {
+	return fmt.Sprintf("%019d-%s", math.MaxInt64-ts.UnixNano(), uuid.New().String())
+}
+
+func (a *AzStorageAccountTableAuditSink) RecordEvent(ctx context.Context, event core.AuditEvent) error {
+	client, err := a.getTableClient()
+	if err != nil {
+		return fmt.Errorf("cannot retrieve table client: %v", err.Error())
+	}
+
+	tableRec := aztables.EDMEntity{
+		Entity: aztables.Entity{
+			PartitionKey: a.PartitionKey,
+			RowKey:       auditRowKey(event.Timestamp),
+		},
+		Properties: map[string]any{
+			"eventAt":        event.Timestamp.Unix(),
+			"eventTimestamp": event.Timestamp.Format(time.RFC3339),
+			"operation":      string(event.Operation),
+			"uuid":           event.CiphertextUuid,
+			"objectType":     event.ObjectType,
+			"destination":    event.Destination,
+			"wrappingKey":    event.WrappingKey,
+			"outcome":        string(event.Outcome),
+			"message":        event.Message,
+		},
+	}
+
+	marshalled, _ := json.Marshal(tableRec)
+
+	if _, err = client.AddEntity(ctx, marshalled, nil); err != nil {
+		return fmt.Errorf("cannot record audit event: %s", err.Error())
+	}
+
+	return nil
+}
+
+func NewAzStorageAccountTableAuditSink(cred azcore.TokenCredential, connectionString, accountName, tableName, partitionKey string) (*AzStorageAccountTableAuditSink, error) {
+	if len(connectionString) == 0 && len(accountName) == 0 {
+		return nil, errors.New("either connection string or storage account name is required for the audit table")
+	}
+
+	rv := &AzStorageAccountTableAuditSink{
+		Credential:       cred,
+		ConnectionString: connectionString,
+		AccountName:      accountName,
+		TableName:        tableName,
+		PartitionKey:     partitionKey,
+	}
+	return rv, nil
+}
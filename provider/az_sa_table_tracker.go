@@ -7,9 +7,14 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/data/aztables"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"strings"
 	"time"
 )
 
+// trackerSelectedProperties the properties of the tracker table rows the tracker reads.
+const trackerSelectedProperties = "RowKey,PartitionKey,numUses,trackedTimestamp,updatedAtTimestamp,destination,destinations,revoked,revokedReason,revokedTimestamp"
+
 type AzStorageAccountTableTracker struct {
 	Credential azcore.TokenCredential
 
@@ -21,12 +26,14 @@ type AzStorageAccountTableTracker struct {
 	tableClient *aztables.Client
 }
 
+// IsObjectIdTracked whether the object id was used. The row recording only the revocation of the object id
+// that was never used does not track the object.
 func (a *AzStorageAccountTableTracker) IsObjectIdTracked(ctx context.Context, id string) (bool, error) {
 	rowEntity, err := a.getEntity(ctx, id)
 	if err != nil {
 		return false, err
 	} else {
-		return rowEntity != nil, nil
+		return rowEntity != nil && !a.isRevocationOnly(rowEntity), nil
 	}
 }
 
@@ -48,7 +55,7 @@ func (a *AzStorageAccountTableTracker) getEntity(ctx context.Context, id string)
 	filter := fmt.Sprintf("PartitionKey eq '%s' and RowKey eq '%s'", a.PartitionKey, id)
 	options := &aztables.ListEntitiesOptions{
 		Filter: &filter,
-		Select: to.Ptr("RowKey,PartitionKey,numUses"),
+		Select: to.Ptr(trackerSelectedProperties),
 		Top:    to.Ptr(int32(15)),
 	}
 
@@ -67,6 +74,101 @@ func (a *AzStorageAccountTableTracker) getEntity(ctx context.Context, id string)
 	return &rv, err
 }
 
+// ListTrackedObjects returns the records of the object ids. Where no ids are given, all records of the
+// tracker's partition are returned. The rows recording only the revocations are omitted.
+func (a *AzStorageAccountTableTracker) ListTrackedObjects(ctx context.Context, ids []string) ([]core.TrackedObject, error) {
+	var rv []core.TrackedObject
+
+	if len(ids) > 0 {
+		for _, id := range ids {
+			rowEntity, err := a.getEntity(ctx, id)
+			if err != nil {
+				return nil, err
+			} else if rowEntity != nil && !a.isRevocationOnly(rowEntity) {
+				rv = append(rv, a.trackedObjectOf(rowEntity))
+			}
+		}
+		return rv, nil
+	}
+
+	client, err := a.getTableClient()
+	if err != nil {
+		return nil, err
+	}
+
+	filter := fmt.Sprintf("PartitionKey eq '%s'", a.PartitionKey)
+	pager := client.NewListEntitiesPager(&aztables.ListEntitiesOptions{
+		Filter: &filter,
+		Select: to.Ptr(trackerSelectedProperties),
+	})
+
+	for pager.More() {
+		page, pageErr := pager.NextPage(ctx)
+		if pageErr != nil {
+			return nil, pageErr
+		}
+
+		for _, entityData := range page.Entities {
+			rowEntity := aztables.EDMEntity{}
+			if jsonErr := json.Unmarshal(entityData, &rowEntity); jsonErr != nil {
+				return nil, jsonErr
+			}
+			if !a.isRevocationOnly(&rowEntity) {
+				rv = append(rv, a.trackedObjectOf(&rowEntity))
+			}
+		}
+	}
+
+	return rv, nil
+}
+
+func (a *AzStorageAccountTableTracker) trackedObjectOf(tableRec *aztables.EDMEntity) core.TrackedObject {
+	rv := core.TrackedObject{
+		Uuid:    tableRec.RowKey,
+		NumUses: a.getNumUses(tableRec),
+	}
+
+	rv.FirstUsedAt, _ = tableRec.Properties["trackedTimestamp"].(string)
+	rv.Destination, _ = tableRec.Properties["destination"].(string)
+	rv.Destinations = destinationsOf(tableRec)
+	if lastUse, ok := tableRec.Properties["updatedAtTimestamp"].(string); ok {
+		rv.LastUsedAt = lastUse
+	} else {
+		rv.LastUsedAt = rv.FirstUsedAt
+	}
+
+	return rv
+}
+
+// destinationsOf the destination labels recorded on the tracker table row. The labels are stored in the
+// destinations property, one per line.
+func destinationsOf(tableRec *aztables.EDMEntity) []string {
+	if v, ok := tableRec.Properties["destinations"].(string); ok && len(v) > 0 {
+		return strings.Split(v, "\n")
+	}
+	if v, ok := tableRec.Properties["destination"].(string); ok && len(v) > 0 {
+		return []string{v}
+	}
+	return nil
+}
+
+// recordDestination records the destination label on the tracker table row. The first label is kept
+// in the destination property.
+func recordDestination(tableRec *aztables.EDMEntity, destination string) {
+	if len(destination) == 0 {
+		return
+	}
+
+	destinations := destinationsOf(tableRec)
+	if core.Contains(destination, destinations) {
+		return
+	}
+	if len(destinations) == 0 {
+		tableRec.Properties["destination"] = destination
+	}
+	tableRec.Properties["destinations"] = strings.Join(append(destinations, destination), "\n")
+}
+
 func (a *AzStorageAccountTableTracker) getTableClient() (*aztables.Client, error) {
 	if a.service == nil {
 		svc, initErr := aztables.NewServiceClient(
@@ -88,7 +190,7 @@ func (a *AzStorageAccountTableTracker) getTableClient() (*aztables.Client, error
 	return a.tableClient, nil
 }
 
-func (a *AzStorageAccountTableTracker) TrackObjectId(ctx context.Context, id string) error {
+func (a *AzStorageAccountTableTracker) TrackObjectId(ctx context.Context, id string, destination string) error {
 	client, err := a.getTableClient()
 	if err != nil {
 		return fmt.Errorf("cannot retrieve table client: %v", err.Error())
@@ -111,6 +213,7 @@ func (a *AzStorageAccountTableTracker) TrackObjectId(ctx context.Context, id str
 				"numUses":          1,
 			},
 		}
+		recordDestination(tableRec, destination)
 
 		marshalled, _ := json.Marshal(tableRec)
 
@@ -119,9 +222,16 @@ func (a *AzStorageAccountTableTracker) TrackObjectId(ctx context.Context, id str
 			return fmt.Errorf("cannot track object id: %s", err.Error())
 		}
 	} else {
+		if a.isRevocationOnly(tableRec) {
+			// The object revoked before its first use is now used for the first time
+			tableRec.Properties["trackedAt"] = time.Now().Unix()
+			tableRec.Properties["trackedTimestamp"] = time.Now().Format(time.RFC3339)
+		} else {
+			tableRec.Properties["updatedAt"] = time.Now().Unix()
+			tableRec.Properties["updatedAtTimestamp"] = time.Now().Format(time.RFC3339)
+		}
 		tableRec.Properties["numUses"] = a.getNumUses(tableRec) + 1
-		tableRec.Properties["updatedAt"] = time.Now().Unix()
-		tableRec.Properties["updatedAtTimestamp"] = time.Now().Format(time.RFC3339)
+		recordDestination(tableRec, destination)
 
 		marshalled, _ := json.Marshal(tableRec)
 
@@ -134,6 +244,78 @@ func (a *AzStorageAccountTableTracker) TrackObjectId(ctx context.Context, id str
 	return nil
 }
 
+// GetRevocation returns the revocation of the object id. The object is revoked where its row has the revoked
+// property set to true.
+func (a *AzStorageAccountTableTracker) GetRevocation(ctx context.Context, id string) (*core.CiphertextRevocation, error) {
+	rowEntity, err := a.getEntity(ctx, id)
+	if err != nil || rowEntity == nil {
+		return nil, err
+	}
+
+	if revoked, ok := rowEntity.Properties["revoked"].(bool); !ok || !revoked {
+		return nil, nil
+	}
+
+	rv := &core.CiphertextRevocation{Uuid: id}
+	rv.Reason, _ = rowEntity.Properties["revokedReason"].(string)
+	rv.RevokedAt, _ = rowEntity.Properties["revokedTimestamp"].(string)
+
+	return rv, nil
+}
+
+// RevokeObjectId marks the object id as revoked. The object that was never tracked is recorded with zero uses;
+// such row records only the revocation and does not track the object.
+func (a *AzStorageAccountTableTracker) RevokeObjectId(ctx context.Context, id string, reason string) error {
+	client, err := a.getTableClient()
+	if err != nil {
+		return fmt.Errorf("cannot retrieve table client: %v", err.Error())
+	}
+
+	tableRec, err := a.getEntity(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	revocationProps := map[string]any{
+		"revoked":          true,
+		"revokedReason":    reason,
+		"revokedAt":        time.Now().Unix(),
+		"revokedTimestamp": time.Now().Format(time.RFC3339),
+	}
+
+	if tableRec == nil {
+		revocationProps["numUses"] = 0
+		tableRec = &aztables.EDMEntity{
+			Entity: aztables.Entity{
+				PartitionKey: a.PartitionKey,
+				RowKey:       id,
+			},
+			Properties: revocationProps,
+		}
+
+		marshalled, _ := json.Marshal(tableRec)
+		if _, err = client.AddEntity(ctx, marshalled, nil); err != nil {
+			return fmt.Errorf("cannot record revocation of object id: %s", err.Error())
+		}
+	} else {
+		for k, v := range revocationProps {
+			tableRec.Properties[k] = v
+		}
+
+		marshalled, _ := json.Marshal(tableRec)
+		if _, err = client.UpdateEntity(ctx, marshalled, nil); err != nil {
+			return fmt.Errorf("cannot record revocation of object id: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// isRevocationOnly whether the row exists only to record the revocation of the object id that was never used
+func (a *AzStorageAccountTableTracker) isRevocationOnly(tableRec *aztables.EDMEntity) bool {
+	return a.getNumUses(tableRec) == 0
+}
+
 func (a *AzStorageAccountTableTracker) getNumUses(tableRec *aztables.EDMEntity) int {
 	numUses := 0
 
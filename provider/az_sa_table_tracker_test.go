@@ -6,7 +6,10 @@ import (
 	"os"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/data/aztables"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/acceptance/fakeazure"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,6 +34,24 @@ func getTestAzCredential(t *testing.T) *azidentity.ClientSecretCredential {
 	return cred
 }
 
+func Test_AZTATT_RecordDestination(t *testing.T) {
+	rec := &aztables.EDMEntity{Properties: map[string]any{}}
+
+	recordDestination(rec, "")
+	assert.Nil(t, destinationsOf(rec))
+
+	recordDestination(rec, "az-c-keyvault://vault-a@secrets=s")
+	recordDestination(rec, "az-c-keyvault://vault-b@secrets=s")
+	recordDestination(rec, "az-c-keyvault://vault-a@secrets=s")
+
+	assert.Equal(t, "az-c-keyvault://vault-a@secrets=s", rec.Properties["destination"])
+	assert.Equal(t, []string{"az-c-keyvault://vault-a@secrets=s", "az-c-keyvault://vault-b@secrets=s"}, destinationsOf(rec))
+
+	obj := (&AzStorageAccountTableTracker{}).trackedObjectOf(rec)
+	assert.True(t, obj.IsPlacedAt("az-c-keyvault://vault-b@secrets=s"))
+	assert.False(t, obj.IsPlacedAt("az-c-keyvault://vault-c@secrets=s"))
+}
+
 func Test_AZTATT_Integration(t *testing.T) {
 	cred := getTestAzCredential(t)
 	if cred == nil {
@@ -56,7 +77,7 @@ func Test_AZTATT_Integration(t *testing.T) {
 	assert.Nil(t, trackCheckErr)
 	assert.False(t, tracked)
 
-	trackStoreErr := tracker.TrackObjectId(ctx, testUUID)
+	trackStoreErr := tracker.TrackObjectId(ctx, testUUID, "")
 	assert.Nil(t, trackStoreErr)
 
 	fmt.Println("---object existence -2-")
@@ -94,7 +115,7 @@ func Test_AZTATT_ObjectCounting_Integration(t *testing.T) {
 	fmt.Println("---first iteration--")
 
 	// First iteration:
-	trackStoreErr := tracker.TrackObjectId(ctx, testUUID)
+	trackStoreErr := tracker.TrackObjectId(ctx, testUUID, "")
 	assert.Nil(t, trackStoreErr)
 
 	fmt.Println("---check first use--")
@@ -112,7 +133,7 @@ func Test_AZTATT_ObjectCounting_Integration(t *testing.T) {
 	fmt.Println("---second track--")
 
 	// First iteration:
-	trackStoreErr = tracker.TrackObjectId(ctx, testUUID)
+	trackStoreErr = tracker.TrackObjectId(ctx, testUUID, "")
 	assert.Nil(t, trackStoreErr)
 
 	fmt.Println("---check second use--")
@@ -121,3 +142,130 @@ func Test_AZTATT_ObjectCounting_Integration(t *testing.T) {
 	assert.Nil(t, trackCheckErr)
 	assert.Equal(t, 2, tracked)
 }
+
+func Test_AZTATT_Revocation_Integration(t *testing.T) {
+	cred := getTestAzCredential(t)
+	if cred == nil {
+		t.SkipNow()
+		fmt.Println("Az Table Tracker integration test skipped: no credential set")
+		return
+	}
+
+	tracker := AzStorageAccountTableTracker{
+		Credential:   cred,
+		AccountName:  os.Getenv("AZ_SA_ACCOUNT_NAME"),
+		TableName:    os.Getenv("AZ_SA_TABLE_NAME"),
+		PartitionKey: "acctest_rev",
+	}
+
+	testUUID := uuid.New().String()
+
+	ctx := context.Background()
+
+	revocation, revocationErr := tracker.GetRevocation(ctx, testUUID)
+	assert.Nil(t, revocationErr)
+	assert.Nil(t, revocation)
+
+	assert.Nil(t, tracker.TrackObjectId(ctx, testUUID, ""))
+	assert.Nil(t, tracker.RevokeObjectId(ctx, testUUID, "integration test"))
+
+	revocation, revocationErr = tracker.GetRevocation(ctx, testUUID)
+	assert.Nil(t, revocationErr)
+	assert.NotNil(t, revocation)
+	assert.Equal(t, "integration test", revocation.Reason)
+
+	uses, usesErr := tracker.GetTackedObjectUses(ctx, testUUID)
+	assert.Nil(t, usesErr)
+	assert.Equal(t, 1, uses)
+}
+
+// givenTrackerConnectedTo the tracker keeping its table in the fake Azure server
+func givenTrackerConnectedTo(t *testing.T, srv *fakeazure.Server) *AzStorageAccountTableTracker {
+	client, err := aztables.NewClient(srv.TableURL("tracker"), srv.Credential(), &aztables.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Transport: srv.Transport(),
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		},
+	})
+	assert.Nil(t, err)
+
+	tracker, err := NewAzStorageAccountTracker(srv.Credential(), "account", "tracker", "unittest")
+	assert.Nil(t, err)
+	tracker.tableClient = client
+
+	return tracker
+}
+
+func Test_AZTATT_RevocationBeforeFirstUseDoesNotTrack(t *testing.T) {
+	srv := fakeazure.NewServer()
+	defer srv.Close()
+
+	tracker := givenTrackerConnectedTo(t, srv)
+	ctx := context.Background()
+
+	assert.Nil(t, tracker.RevokeObjectId(ctx, "revoked-uuid", "leaked"))
+
+	revocation, err := tracker.GetRevocation(ctx, "revoked-uuid")
+	assert.Nil(t, err)
+	assert.NotNil(t, revocation)
+	assert.Equal(t, "leaked", revocation.Reason)
+
+	tracked, err := tracker.IsObjectIdTracked(ctx, "revoked-uuid")
+	assert.Nil(t, err)
+	assert.False(t, tracked)
+
+	all, err := tracker.ListTrackedObjects(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(all))
+
+	selected, err := tracker.ListTrackedObjects(ctx, []string{"revoked-uuid"})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(selected))
+}
+
+func Test_AZTATT_RevocationOfUsedObjectKeepsTracking(t *testing.T) {
+	srv := fakeazure.NewServer()
+	defer srv.Close()
+
+	tracker := givenTrackerConnectedTo(t, srv)
+	ctx := context.Background()
+
+	assert.Nil(t, tracker.TrackObjectId(ctx, "used-uuid", "az-c-keyvault://vault@secrets=s"))
+	assert.Nil(t, tracker.RevokeObjectId(ctx, "used-uuid", "leaked"))
+	assert.Nil(t, tracker.RevokeObjectId(ctx, "unused-uuid", "leaked"))
+
+	tracked, err := tracker.IsObjectIdTracked(ctx, "used-uuid")
+	assert.Nil(t, err)
+	assert.True(t, tracked)
+
+	all, err := tracker.ListTrackedObjects(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(all))
+	assert.Equal(t, "used-uuid", all[0].Uuid)
+	assert.Equal(t, 1, all[0].NumUses)
+	assert.Equal(t, "az-c-keyvault://vault@secrets=s", all[0].Destination)
+}
+
+func Test_AZTATT_FirstUseAfterRevocation(t *testing.T) {
+	srv := fakeazure.NewServer()
+	defer srv.Close()
+
+	tracker := givenTrackerConnectedTo(t, srv)
+	ctx := context.Background()
+
+	assert.Nil(t, tracker.RevokeObjectId(ctx, "revoked-uuid", "leaked"))
+	assert.Nil(t, tracker.TrackObjectId(ctx, "revoked-uuid", ""))
+
+	uses, err := tracker.GetTackedObjectUses(ctx, "revoked-uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, uses)
+
+	all, err := tracker.ListTrackedObjects(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(all))
+	assert.NotEmpty(t, all[0].FirstUsedAt)
+
+	revocation, err := tracker.GetRevocation(ctx, "revoked-uuid")
+	assert.Nil(t, err)
+	assert.NotNil(t, revocation)
+}
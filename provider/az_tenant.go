@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const azureManagementScope = "https://management.azure.com/.default"
+
+// TenantIdOfAccessToken reads the tenant id (tid claim) from the Entra ID access token. The signature of the
+// token is not verified: the token is obtained by the provider itself from the credential.
+func TenantIdOfAccessToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("access token is not a JSON web token")
+	}
+
+	payload, decodeErr := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if decodeErr != nil {
+		return "", fmt.Errorf("cannot decode access token payload: %s", decodeErr.Error())
+	}
+
+	claims := struct {
+		TenantId string `json:"tid"`
+	}{}
+	if jsonErr := json.Unmarshal(payload, &claims); jsonErr != nil {
+		return "", fmt.Errorf("cannot read access token claims: %s", jsonErr.Error())
+	}
+	if len(claims.TenantId) == 0 {
+		return "", errors.New("access token does not specify the tenant")
+	}
+
+	return claims.TenantId, nil
+}
+
+// TenantIdOfCredential obtains the Azure Resource Manager access token from the credential and returns the
+// tenant the token was issued by.
+func TenantIdOfCredential(ctx context.Context, cred azcore.TokenCredential) (string, error) {
+	if cred == nil {
+		return "", errors.New("provider has no Azure credential")
+	}
+
+	token, tokenErr := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureManagementScope}})
+	if tokenErr != nil {
+		return "", fmt.Errorf("cannot obtain access token: %s", tokenErr.Error())
+	}
+
+	return TenantIdOfAccessToken(token.Token)
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TenantIdOfAccessToken(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"aud":"https://management.azure.com","tid":"11111111-1111-1111-1111-111111111111"}`))
+
+	tenantId, err := TenantIdOfAccessToken("eyJhbGciOiJub25lIn0." + payload + ".signature")
+	assert.Nil(t, err)
+	assert.Equal(t, "11111111-1111-1111-1111-111111111111", tenantId)
+}
+
+func Test_TenantIdOfAccessToken_MissingTenant(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"aud":"https://management.azure.com"}`))
+
+	_, err := TenantIdOfAccessToken("eyJhbGciOiJub25lIn0." + payload + ".signature")
+	assert.NotNil(t, err)
+}
+
+func Test_TenantIdOfAccessToken_NotJwt(t *testing.T) {
+	_, err := TenantIdOfAccessToken("opaque-token")
+	assert.NotNil(t, err)
+}
+
+// countingCredential a credential counting the token requests
+type countingCredential struct {
+	calls atomic.Int32
+	token string
+	err   error
+}
+
+func (c *countingCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.calls.Add(1)
+	return azcore.AccessToken{Token: c.token}, c.err
+}
+
+func givenTenantIdConcurrentlyRead(factory *AZClientsFactoryImpl) ([]string, []error) {
+	const readers = 20
+
+	tenants := make([]string, readers)
+	errs := make([]error, readers)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tenants[i], errs[i] = factory.GetAzTenantId(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	return tenants, errs
+}
+
+func Test_GetAzTenantId_ReadsTokenOnce(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"tid":"11111111-1111-1111-1111-111111111111"}`))
+	cred := &countingCredential{token: "eyJhbGciOiJub25lIn0." + payload + ".signature"}
+
+	factory := &AZClientsFactoryImpl{}
+	factory.Credential = cred
+
+	tenants, errs := givenTenantIdConcurrentlyRead(factory)
+	for i := range tenants {
+		assert.Nil(t, errs[i])
+		assert.Equal(t, "11111111-1111-1111-1111-111111111111", tenants[i])
+	}
+	assert.Equal(t, int32(1), cred.calls.Load())
+}
+
+func Test_GetAzTenantId_CachesError(t *testing.T) {
+	cred := &countingCredential{err: errors.New("token endpoint unavailable")}
+
+	factory := &AZClientsFactoryImpl{}
+	factory.Credential = cred
+
+	_, errs := givenTenantIdConcurrentlyRead(factory)
+	for _, err := range errs {
+		assert.ErrorContains(t, err, "token endpoint unavailable")
+	}
+	assert.Equal(t, int32(1), cred.calls.Load())
+}
+
+func Test_GetAzTenantId_ConfiguredTenant(t *testing.T) {
+	cred := &countingCredential{err: errors.New("must not be called")}
+
+	factory := &AZClientsFactoryImpl{AzTenantId: "22222222-2222-2222-2222-222222222222"}
+	factory.Credential = cred
+
+	tenantId, err := factory.GetAzTenantId(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "22222222-2222-2222-2222-222222222222", tenantId)
+	assert.Equal(t, int32(0), cred.calls.Load())
+}
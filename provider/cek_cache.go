@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+)
+
+// DefaultCEKCacheSize the number of the content encryption keys the provider keeps unwrapped where the provider
+// configuration doesn't specify the size of the cache.
+const DefaultCEKCacheSize = 256
+
+type cekCacheEntry struct {
+	digest      [sha256.Size]byte
+	wrappingKey string
+	cek         []byte
+}
+
+// CEKCache keeps the content encryption keys (CEKs) unwrapped by the Key Vault within the provider process. Every
+// ciphertext carries its own CEK, so the cache saves a decrypt call only where the same ciphertext is decrypted
+// again by this process, e.g. where ModifyPlan has decrypted the ciphertext and Create or Update decrypts it
+// once more in the apply. The cache is keyed by the SHA-256 digest of the wrapped CEK and holds at most the
+// configured number of entries; the least recently used entries are evicted first. The evicted entries are
+// zeroised.
+type CEKCache struct {
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	order   *list.List
+}
+
+func NewCEKCache(maxEntries int) *CEKCache {
+	rv := &CEKCache{
+		maxEntries: maxEntries,
+		entries:    map[[sha256.Size]byte]*list.Element{},
+		order:      list.New(),
+	}
+
+	registerCEKCache(rv)
+	return rv
+}
+
+// Unwrap returns the unwrapped CEK from the cache, or calls the unwrap function where the cache doesn't hold the
+// CEK. The cached value is only served for the same wrapping key that has unwrapped it. Only the values that are
+// content encryption keys are cached: a short plaintext directly encrypted with RSA is never kept in memory.
+// The second return value indicates whether the value was served from the cache.
+func (c *CEKCache) Unwrap(wrappingKey string, wrapped []byte, unwrap core.RSADecrypter) ([]byte, bool, error) {
+	if c == nil || c.maxEntries <= 0 {
+		rv, err := unwrap(wrapped)
+		return rv, false, err
+	}
+
+	digest := sha256.Sum256(wrapped)
+	if rv, ok := c.get(digest, wrappingKey); ok {
+		return rv, true, nil
+	}
+
+	rv, err := unwrap(wrapped)
+	if err == nil && isContentEncryptionKey(rv) {
+		c.put(digest, wrappingKey, rv)
+	}
+
+	return rv, false, err
+}
+
+func isContentEncryptionKey(v []byte) bool {
+	aesData := core.AESData{}
+	return aesData.FromBytes(v) == nil && len(aesData.Key) > 0
+}
+
+func (c *CEKCache) get(digest [sha256.Size]byte, wrappingKey string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, ok := c.entries[digest]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cekCacheEntry)
+	if entry.wrappingKey != wrappingKey {
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return append([]byte{}, entry.cek...), true
+}
+
+func (c *CEKCache) put(digest [sha256.Size]byte, wrappingKey string, cek []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if elem, ok := c.entries[digest]; ok {
+		c.remove(elem)
+	}
+
+	c.entries[digest] = c.order.PushFront(&cekCacheEntry{
+		digest:      digest,
+		wrappingKey: wrappingKey,
+		cek:         append([]byte{}, cek...),
+	})
+
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *CEKCache) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*cekCacheEntry)
+	delete(c.entries, entry.digest)
+	clear(entry.cek)
+}
+
+// Len the number of the CEKs held in the cache
+func (c *CEKCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+// Zeroise overwrites and drops all the CEKs held in the cache.
+func (c *CEKCache) Zeroise() {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for c.order.Len() > 0 {
+		c.remove(c.order.Back())
+	}
+}
+
+var cekCaches struct {
+	mutex  sync.Mutex
+	caches map[*CEKCache]bool
+}
+
+func registerCEKCache(c *CEKCache) {
+	cekCaches.mutex.Lock()
+	defer cekCaches.mutex.Unlock()
+
+	if cekCaches.caches == nil {
+		cekCaches.caches = map[*CEKCache]bool{}
+	}
+	cekCaches.caches[c] = true
+}
+
+// Release zeroises the cache and removes it from the caches zeroised on shutdown.
+func (c *CEKCache) Release() {
+	if c == nil {
+		return
+	}
+
+	c.Zeroise()
+
+	cekCaches.mutex.Lock()
+	defer cekCaches.mutex.Unlock()
+
+	delete(cekCaches.caches, c)
+}
+
+// ZeroiseCEKCaches zeroises the CEK caches of all the provider instances in this process. It is called
+// when the provider server shuts down.
+func ZeroiseCEKCaches() {
+	cekCaches.mutex.Lock()
+	defer cekCaches.mutex.Unlock()
+
+	for c := range cekCaches.caches {
+		c.Zeroise()
+	}
+	cekCaches.caches = nil
+}
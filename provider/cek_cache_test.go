@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/acceptance/fakeazure"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const unitTestWrappingKey = "vault/key/version (RSA-OAEP-256)"
+
+func givenCountingUnwrapper(counter *int, result []byte) core.RSADecrypter {
+	return func(_ []byte) ([]byte, error) {
+		*counter++
+		return append([]byte{}, result...), nil
+	}
+}
+
+func givenContentEncryptionKey() []byte {
+	aesData := core.AESData{
+		IV:  []byte("unit-test-iv"),
+		Key: []byte("0123456789abcdef0123456789abcdef"),
+	}
+	return aesData.ToBytes()
+}
+
+// decryptCountingTransport counts the decrypt operations the Key Vault is asked to perform
+type decryptCountingTransport struct {
+	policy.Transporter
+	decrypts int
+}
+
+func (d *decryptCountingTransport) Do(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/decrypt") {
+		d.decrypts++
+	}
+	return d.Transporter.Do(req)
+}
+
+func Test_CEKCache_PlanAndApplyDecryptCiphertextOnce(t *testing.T) {
+	srv := fakeazure.NewServer()
+	defer srv.Close()
+
+	privateKey, err := core.PrivateKeyFromData(testkeymaterial.EphemeralRsaKeyText)
+	assert.Nil(t, err)
+	rsaKey := privateKey.(*rsa.PrivateKey)
+	version := srv.AddWrappingKey("vault", "wrapping-key", rsaKey)
+
+	transport := &decryptCountingTransport{Transporter: srv.Transport()}
+	factory := givenFactoryConnectedTo(srv)
+	factory.ClientOptions.Transport = transport
+	factory.CEKCache = NewCEKCache(DefaultCEKCacheSize)
+	defer factory.CEKCache.Release()
+
+	em, err := core.CreateEncryptedMessage(&rsaKey.PublicKey, []byte(strings.Repeat("confidential ", 100)))
+	assert.Nil(t, err)
+	assert.True(t, em.HasContentEncryptionKey())
+
+	coord := &core.WrappingKeyCoordinateModel{
+		VaultName:  types.StringValue("vault"),
+		KeyName:    types.StringValue("wrapping-key"),
+		KeyVersion: types.StringValue(version),
+	}
+
+	// ModifyPlan, and then Create, each obtain the decrypter and decrypt the same ciphertext
+	for _, step := range []string{"plan", "apply"} {
+		plaintext, err := em.ExtractPlainText(factory.GetDecrypterFor(context.Background(), coord))
+		assert.Nil(t, err, step)
+		assert.Equal(t, strings.Repeat("confidential ", 100), string(plaintext), step)
+	}
+	assert.Equal(t, 1, transport.decrypts)
+
+	other, err := core.CreateEncryptedMessage(&rsaKey.PublicKey, []byte(strings.Repeat("other ", 100)))
+	assert.Nil(t, err)
+	_, err = other.ExtractPlainText(factory.GetDecrypterFor(context.Background(), coord))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, transport.decrypts)
+}
+
+func Test_CEKCache_DisabledCallsDecryptEveryTime(t *testing.T) {
+	var cache *CEKCache
+
+	decrypts := 0
+	unwrapper := givenCountingUnwrapper(&decrypts, givenContentEncryptionKey())
+
+	for i := 0; i < 3; i++ {
+		_, cached, err := cache.Unwrap(unitTestWrappingKey, []byte("wrapped-cek"), unwrapper)
+		assert.Nil(t, err)
+		assert.False(t, cached)
+	}
+
+	assert.Equal(t, 3, decrypts)
+}
+
+func Test_CEKCache_ServesOnlyTheSameWrappingKey(t *testing.T) {
+	cache := NewCEKCache(DefaultCEKCacheSize)
+	defer cache.Release()
+
+	decrypts := 0
+	unwrapper := givenCountingUnwrapper(&decrypts, givenContentEncryptionKey())
+
+	_, _, _ = cache.Unwrap(unitTestWrappingKey, []byte("wrapped-cek"), unwrapper)
+	_, cached, _ := cache.Unwrap("vault/other-key/version (RSA-OAEP-256)", []byte("wrapped-cek"), unwrapper)
+
+	assert.False(t, cached)
+	assert.Equal(t, 2, decrypts)
+}
+
+func Test_CEKCache_DoesNotCachePlaintextOrErrors(t *testing.T) {
+	cache := NewCEKCache(DefaultCEKCacheSize)
+	defer cache.Release()
+
+	decrypts := 0
+	_, _, _ = cache.Unwrap(unitTestWrappingKey, []byte("rsa-ciphertext"), givenCountingUnwrapper(&decrypts, []byte("plain text")))
+	_, _, err := cache.Unwrap(unitTestWrappingKey, []byte("wrapped-cek"), func(_ []byte) ([]byte, error) {
+		return nil, errors.New("unit-test-error")
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func Test_CEKCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCEKCache(2)
+	defer cache.Release()
+
+	decrypts := 0
+	unwrapper := givenCountingUnwrapper(&decrypts, givenContentEncryptionKey())
+
+	for i := 0; i < 3; i++ {
+		_, _, _ = cache.Unwrap(unitTestWrappingKey, []byte(fmt.Sprintf("wrapped-cek-%d", i)), unwrapper)
+	}
+	assert.Equal(t, 2, cache.Len())
+
+	_, cached, _ := cache.Unwrap(unitTestWrappingKey, []byte("wrapped-cek-0"), unwrapper)
+	assert.False(t, cached)
+	_, cached, _ = cache.Unwrap(unitTestWrappingKey, []byte("wrapped-cek-2"), unwrapper)
+	assert.True(t, cached)
+}
+
+func Test_ZeroiseCEKCaches(t *testing.T) {
+	cache := NewCEKCache(DefaultCEKCacheSize)
+
+	decrypts := 0
+	_, _, _ = cache.Unwrap(unitTestWrappingKey, []byte("wrapped-cek"), givenCountingUnwrapper(&decrypts, givenContentEncryptionKey()))
+
+	elem := cache.order.Front()
+	cek := elem.Value.(*cekCacheEntry).cek
+
+	ZeroiseCEKCaches()
+
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, make([]byte, len(cek)), cek)
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	tfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+)
+
+// DefaultKeyVaultURLTemplate the URL of the Key Vault in the Azure public cloud; the vault name is substituted
+// for the %s verb.
+const DefaultKeyVaultURLTemplate = "https://%s.vault.azure.net"
+
+// AzEndpoints the endpoints of the Azure services the provider connects to. The empty values select the
+// endpoints of the Azure public cloud. The endpoints are overridden to connect the provider to a fake
+// Azure (e.g. to run the acceptance tests offline).
+type AzEndpoints struct {
+	// KeyVaultURLTemplate the URL of the Key Vault, where the vault name is substituted for the %s verb.
+	KeyVaultURLTemplate string
+
+	// ResourceManager the URL of the Azure Resource Manager API
+	ResourceManager string
+}
+
+// IsOverridden whether any endpoint differs from the Azure public cloud
+func (e AzEndpoints) IsOverridden() bool {
+	return len(e.KeyVaultURLTemplate) > 0 || len(e.ResourceManager) > 0
+}
+
+// GetKeyVaultURL the URL of the vault with the specified name
+func (e AzEndpoints) GetKeyVaultURL(vaultName string) string {
+	if len(e.KeyVaultURLTemplate) > 0 {
+		return fmt.Sprintf(e.KeyVaultURLTemplate, vaultName)
+	}
+	return fmt.Sprintf(DefaultKeyVaultURLTemplate, vaultName)
+}
+
+// DisableChallengeResourceVerification whether the Key Vault clients should accept the authentication challenge
+// that names a resource other than the vault's domain. This is the case only where the Key Vault endpoint is
+// overridden: a fake vault is not reachable under the vault.azure.net domain.
+func (e AzEndpoints) DisableChallengeResourceVerification() bool {
+	return len(e.KeyVaultURLTemplate) > 0
+}
+
+// GetArmClientOptions the options of the Azure Resource Manager clients
+func (e AzEndpoints) GetArmClientOptions(opts policy.ClientOptions) *arm.ClientOptions {
+	if len(e.ResourceManager) > 0 {
+		opts.Cloud = cloud.Configuration{
+			ActiveDirectoryAuthorityHost: cloud.AzurePublic.ActiveDirectoryAuthorityHost,
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				cloud.ResourceManager: {
+					Endpoint: e.ResourceManager,
+					Audience: cloud.AzurePublic.Services[cloud.ResourceManager].Audience,
+				},
+			},
+		}
+	}
+
+	return &arm.ClientOptions{ClientOptions: opts}
+}
+
+// AzureOverrides replaces the connection of the provider to Azure. The overrides are not configurable from
+// Terraform: these are set in the code instantiating the provider (e.g. the offline acceptance tests)
+type AzureOverrides struct {
+	// Credential the credential to use instead of the credential configured in the provider
+	Credential azcore.TokenCredential
+
+	// Transport the HTTP transport of the Azure clients
+	Transport policy.Transporter
+
+	Endpoints AzEndpoints
+}
+
+// NewWithAzureOverrides creates the provider connecting to Azure as the overrides specify.
+func NewWithAzureOverrides(version string, overrides AzureOverrides) func() tfprovider.Provider {
+	return func() tfprovider.Provider {
+		return &AZConnectorProviderImpl{
+			version:   version,
+			overrides: &overrides,
+		}
+	}
+}
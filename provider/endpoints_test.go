@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/acceptance/fakeazure"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func givenFactoryConnectedTo(srv *fakeazure.Server) *AZClientsFactoryImpl {
+	return &AZClientsFactoryImpl{
+		CachedAzClientsSupplier: CachedAzClientsSupplier{
+			Credential: srv.Credential(),
+			Endpoints: AzEndpoints{
+				KeyVaultURLTemplate: srv.KeyVaultURLTemplate(),
+				ResourceManager:     srv.ResourceManagerURL(),
+			},
+		},
+	}
+}
+
+func Test_AzEndpoints_DefaultsToPublicCloud(t *testing.T) {
+	e := AzEndpoints{}
+	assert.False(t, e.IsOverridden())
+	assert.False(t, e.DisableChallengeResourceVerification())
+	assert.Equal(t, "https://vault.vault.azure.net", e.GetKeyVaultURL("vault"))
+	assert.Empty(t, e.GetArmClientOptions(policy.ClientOptions{}).Cloud.Services)
+}
+
+func Test_AZClientsFactoryImpl_DecryptsWithOverriddenEndpoints(t *testing.T) {
+	srv := fakeazure.NewServer()
+	defer srv.Close()
+
+	privateKey, err := core.PrivateKeyFromData(testkeymaterial.EphemeralRsaKeyText)
+	assert.Nil(t, err)
+	rsaKey := privateKey.(*rsa.PrivateKey)
+	version := srv.AddWrappingKey("vault", "wrapping-key", rsaKey)
+
+	factory := givenFactoryConnectedTo(srv)
+	factory.ClientOptions.Transport = srv.Transport()
+
+	ciphertext, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, &rsaKey.PublicKey, []byte("plain text"), nil)
+	assert.Nil(t, err)
+
+	decrypter := factory.GetDecrypterFor(context.Background(), &core.WrappingKeyCoordinateModel{
+		VaultName:  types.StringValue("vault"),
+		KeyName:    types.StringValue("wrapping-key"),
+		KeyVersion: types.StringValue(version),
+	})
+	plaintext, err := decrypter(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain text", string(plaintext))
+
+	tenantId, err := factory.GetAzTenantId(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, fakeazure.DefaultTenantId, tenantId)
+}
+
+func Test_AZClientsFactoryImpl_ConnectsApimToOverriddenEndpoint(t *testing.T) {
+	srv := fakeazure.NewServer()
+	defer srv.Close()
+
+	factory := givenFactoryConnectedTo(srv)
+	factory.ClientOptions.Transport = srv.Transport()
+
+	client, err := factory.GetApimSubscriptionClient("sub")
+	assert.Nil(t, err)
+
+	_, err = client.CreateOrUpdate(context.Background(), "rg", "svc", "sid", armapimanagement.SubscriptionCreateParameters{
+		Properties: &armapimanagement.SubscriptionCreateParameterProperties{
+			DisplayName: to.Ptr("Subscription"),
+			Scope:       to.Ptr("/apis"),
+			PrimaryKey:  to.Ptr("primary"),
+		},
+	}, nil)
+	assert.Nil(t, err)
+
+	primary, _, ok := srv.SubscriptionKeys("sub", "rg", "svc", "sid")
+	assert.True(t, ok)
+	assert.Equal(t, "primary", primary)
+}
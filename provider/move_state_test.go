@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/apim"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/keyvault"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/stretchr/testify/assert"
+)
+
+// moveResourceState moves the azurerm state through the provider server, as Terraform would on the `moved` block
+func moveResourceState(t *testing.T, r resource.Resource, targetTypeName, sourceTypeName, sourceJson string) (tfsdk.State, *tfprotov6.MoveResourceStateResponse) {
+	ctx := context.Background()
+
+	server, err := providerserver.NewProtocol6WithError(New("unittest")())()
+	assert.Nil(t, err)
+
+	resp, err := server.MoveResourceState(ctx, &tfprotov6.MoveResourceStateRequest{
+		SourceProviderAddress: resources.AzureRMProviderAddress,
+		SourceTypeName:        sourceTypeName,
+		SourceState:           &tfprotov6.RawState{JSON: []byte(sourceJson)},
+		TargetTypeName:        targetTypeName,
+	})
+	assert.Nil(t, err)
+
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if resp.TargetState != nil {
+		state.Raw, err = resp.TargetState.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+		assert.Nil(t, err)
+	}
+
+	return state, resp
+}
+
+func Test_AZPI_MovesAzureRMKeyVaultSecret(t *testing.T) {
+	state, resp := moveResourceState(t, keyvault.NewSecretResource(),
+		"az-confidential_keyvault_secret",
+		keyvault.AzureRMKeyVaultSecretTypeName,
+		`{"id":"https://vault.vault.azure.net/secrets/name/version","name":"name","value":"plain-text","content_type":"text/plain","tags":{"a":"b"}}`,
+	)
+	assert.Empty(t, resp.Diagnostics)
+
+	mdl := keyvault.SecretModel{}
+	assert.False(t, state.Get(context.Background(), &mdl).HasError())
+	assert.Equal(t, "https://vault.vault.azure.net/secrets/name/version", mdl.Id.ValueString())
+	assert.Equal(t, "vault", mdl.DestinationSecret.VaultName.ValueString())
+	assert.True(t, resources.IsDriftMessage(mdl.EncryptedSecret.ValueString()))
+	assert.Nil(t, mdl.WrappingKeyCoordinate)
+	assert.True(t, mdl.Timeouts.IsNull())
+	assert.Contains(t, string(resp.TargetPrivate), resources.MovedObjectPrivateStateKey)
+	assert.NotContains(t, state.Raw.String(), "plain-text")
+}
+
+func Test_AZPI_MovesAzureRMNamedValue(t *testing.T) {
+	state, resp := moveResourceState(t, apim.NewNamedValueResource(),
+		"az-confidential_apim_named_value",
+		apim.AzureRMNamedValueTypeName,
+		`{"id":"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/namedValues/nv","display_name":"nv","value":"plain-text","secret":true,"tags":[],"value_from_key_vault":[]}`,
+	)
+	assert.Empty(t, resp.Diagnostics)
+
+	mdl := apim.NamedValueModel{}
+	assert.False(t, state.Get(context.Background(), &mdl).HasError())
+	assert.Equal(t, "nv", mdl.DestinationNamedValue.Name.ValueString())
+	assert.True(t, resources.IsDriftMessage(mdl.EncryptedSecret.ValueString()))
+	assert.Contains(t, string(resp.TargetPrivate), resources.MovedObjectPrivateStateKey)
+}
+
+func Test_AZPI_DoesNotMoveOtherResourceTypes(t *testing.T) {
+	_, resp := moveResourceState(t, keyvault.NewSecretResource(),
+		"az-confidential_keyvault_secret",
+		"azurerm_key_vault_key",
+		`{"id":"https://vault.vault.azure.net/keys/name/version"}`,
+	)
+
+	assert.Len(t, resp.Diagnostics, 1)
+	assert.Equal(t, tfprotov6.DiagnosticSeverityError, resp.Diagnostics[0].Severity)
+}
@@ -5,8 +5,12 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
@@ -16,11 +20,13 @@ import (
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/apim"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/general"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/keyvault"
+	tfint64validators "github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	tfsetvalidators "github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	tfstringvalidators "github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	tfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -36,13 +42,23 @@ type ObjectHashTracker interface {
 	// GetTackedObjectUses retrieves how many times a particular object was used.
 	GetTackedObjectUses(ctx context.Context, id string) (int, error)
 
-	// TrackObjectId Track object Id in the memory of seeing objects
-	TrackObjectId(ctx context.Context, id string) error
+	// TrackObjectId Track object Id in the memory of seeing objects, recording the destination label where the
+	// object was placed
+	TrackObjectId(ctx context.Context, id string, destination string) error
+
+	// ListTrackedObjects retrieves the records of the tracked object ids; all records where ids are not given.
+	ListTrackedObjects(ctx context.Context, ids []string) ([]core.TrackedObject, error)
 }
 
 type CachedAzClientsSupplier struct {
 	Credential azcore.TokenCredential
 
+	// ClientOptions the options (e.g. the retry policy) of every client this supplier creates
+	ClientOptions policy.ClientOptions
+
+	// Endpoints the endpoints of the Azure services; the Azure public cloud where empty
+	Endpoints AzEndpoints
+
 	apimSubscriptionClients map[string]*armapimanagement.SubscriptionClient
 	apimNamedValueClients   map[string]core.ApimNamedValueClientAbstraction
 	secretClients           map[string]*azsecrets.Client
@@ -61,7 +77,7 @@ func (css *CachedAzClientsSupplier) GetApimNamedValueClient(subscriptionId strin
 		return client, nil
 	}
 
-	client, err := armapimanagement.NewNamedValueClient(subscriptionId, css.Credential, nil)
+	client, err := armapimanagement.NewNamedValueClient(subscriptionId, css.Credential, css.Endpoints.GetArmClientOptions(css.ClientOptions))
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +99,7 @@ func (css *CachedAzClientsSupplier) GetApimSubscriptionClient(subscriptionId str
 		return client, nil
 	}
 
-	client, err := armapimanagement.NewSubscriptionClient(subscriptionId, css.Credential, nil)
+	client, err := armapimanagement.NewSubscriptionClient(subscriptionId, css.Credential, css.Endpoints.GetArmClientOptions(css.ClientOptions))
 	if err != nil {
 		return nil, err
 	}
@@ -98,13 +114,16 @@ func (ccs *CachedAzClientsSupplier) GetSecretsClient(vaultName string) (core.AzS
 		ccs.secretClients = map[string]*azsecrets.Client{}
 	}
 
-	vaultUrl := fmt.Sprintf("https://%s.vault.azure.net", vaultName)
+	vaultUrl := ccs.Endpoints.GetKeyVaultURL(vaultName)
 
 	if client, ok := ccs.secretClients[vaultUrl]; ok {
 		return client, nil
 	}
 
-	client, err := azsecrets.NewClient(vaultUrl, ccs.Credential, nil)
+	client, err := azsecrets.NewClient(vaultUrl, ccs.Credential, &azsecrets.ClientOptions{
+		ClientOptions:                        ccs.ClientOptions,
+		DisableChallengeResourceVerification: ccs.Endpoints.DisableChallengeResourceVerification(),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -120,13 +139,16 @@ func (ccs *CachedAzClientsSupplier) GetKeysClient(vaultName string) (core.AzKeyC
 		ccs.keysClients = map[string]*azkeys.Client{}
 	}
 
-	vaultUrl := fmt.Sprintf("https://%s.vault.azure.net", vaultName)
+	vaultUrl := ccs.Endpoints.GetKeyVaultURL(vaultName)
 
 	if client, ok := ccs.keysClients[vaultUrl]; ok {
 		return client, nil
 	}
 
-	client, err := azkeys.NewClient(vaultUrl, ccs.Credential, nil)
+	client, err := azkeys.NewClient(vaultUrl, ccs.Credential, &azkeys.ClientOptions{
+		ClientOptions:                        ccs.ClientOptions,
+		DisableChallengeResourceVerification: ccs.Endpoints.DisableChallengeResourceVerification(),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -142,13 +164,16 @@ func (ccs *CachedAzClientsSupplier) GetCertificateClient(vaultName string) (core
 		ccs.certificateClients = map[string]*azcertificates.Client{}
 	}
 
-	vaultUrl := fmt.Sprintf("https://%s.vault.azure.net", vaultName)
+	vaultUrl := ccs.Endpoints.GetKeyVaultURL(vaultName)
 
 	if client, ok := ccs.certificateClients[vaultUrl]; ok {
 		return client, nil
 	}
 
-	client, err := azcertificates.NewClient(vaultUrl, ccs.Credential, nil)
+	client, err := azcertificates.NewClient(vaultUrl, ccs.Credential, &azcertificates.ClientOptions{
+		ClientOptions:                        ccs.ClientOptions,
+		DisableChallengeResourceVerification: ccs.Endpoints.DisableChallengeResourceVerification(),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -177,9 +202,30 @@ type AZClientsFactoryImpl struct {
 	DefaultDestinationVault              string
 	DefaultAzSubscriptionId              string
 
+	// AzTenantId the tenant of the provider's credential. Where empty, it is read from the access token
+	// when a ciphertext bound to Azure tenants is first used.
+	AzTenantId string
+
+	tenantIdOnce sync.Once
+	tenantIdErr  error
+
 	ProviderLabels []string
 
-	hashTacker ObjectHashTracker
+	ExpiryWarningWindow time.Duration
+
+	// ProvenanceTagging whether provenance tags are stamped on the created Azure objects
+	ProvenanceTagging bool
+
+	// FingerprintKey the key of the fingerprints of the confidential values kept in the private state of the
+	// resources; fingerprinting is disabled where empty
+	FingerprintKey []byte
+
+	// CEKCache the content encryption keys unwrapped within this provider process; nil where caching is disabled
+	CEKCache *CEKCache
+
+	hashTacker     ObjectHashTracker
+	auditSink      AuditSink
+	revocationList RevocationList
 }
 
 func (f *AZClientsFactoryImpl) GetAzSubscription(v string) (string, error) {
@@ -216,14 +262,87 @@ func (f *AZClientsFactoryImpl) GetDecrypterFor(ctx context.Context, coord *core.
 	wrappingKeyCoordinate, coordErr := f.GetMergedWrappingKeyCoordinate(ctx, coord)
 	return func(input []byte) ([]byte, error) {
 		if coordErr != nil {
+			f.recordDecryptAuditEvent(ctx, "", coordErr, "")
 			return []byte{}, coordErr
 		}
 
-		return f.AzKeyVaultRSADecrypt(ctx, input, wrappingKeyCoordinate)
+		wrappingKey := core.WrappingKeyAuditLabel(wrappingKeyCoordinate)
+		rv, cached, err := f.CEKCache.Unwrap(wrappingKey, input, func(in []byte) ([]byte, error) {
+			return f.AzKeyVaultRSADecrypt(ctx, in, wrappingKeyCoordinate)
+		})
+
+		if cached {
+			tflog.Debug(ctx, "Content encryption key was served from the provider cache")
+			f.recordDecryptAuditEvent(ctx, wrappingKey, nil, "content encryption key served from the provider cache")
+		} else {
+			f.recordDecryptAuditEvent(ctx, wrappingKey, err, "")
+		}
+		return rv, err
+	}
+}
+
+func (f *AZClientsFactoryImpl) recordDecryptAuditEvent(ctx context.Context, wrappingKey string, err error, message string) {
+	if f.auditSink == nil {
+		return
+	}
+
+	subject := core.AuditSubjectOf(ctx)
+	event := core.AuditEvent{
+		Operation:      core.AuditOperationDecrypt,
+		CiphertextUuid: subject.CiphertextUuid,
+		ObjectType:     subject.ObjectType,
+		Destination:    subject.Destination,
+		WrappingKey:    wrappingKey,
+		Outcome:        core.AuditOutcomeSuccess,
+		Message:        message,
+	}
+
+	if len(wrappingKey) == 0 {
+		event.WrappingKey = "unresolved"
+	}
+	if err != nil {
+		event.Outcome = core.AuditOutcomeFailure
+		event.Message = err.Error()
+	}
+
+	f.RecordAuditEvent(ctx, event, nil, nil)
+}
+
+func (f *AZClientsFactoryImpl) RecordAuditEvent(ctx context.Context, event core.AuditEvent, coord *core.WrappingKeyCoordinateModel, diagnostics *diag.Diagnostics) {
+	if f.auditSink == nil {
+		return
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	if len(event.WrappingKey) == 0 {
+		if wrappingKeyCoordinate, coordErr := f.GetMergedWrappingKeyCoordinate(ctx, coord); coordErr == nil {
+			event.WrappingKey = core.WrappingKeyAuditLabel(wrappingKeyCoordinate)
+		} else {
+			event.WrappingKey = "unresolved"
+		}
+	}
+
+	if err := f.auditSink.RecordEvent(ctx, event); err != nil {
+		tflog.Error(ctx, fmt.Sprintf("Audit event could not be recorded: %s", err.Error()))
+		if diagnostics != nil {
+			diagnostics.AddWarning(
+				"Audit event was not recorded",
+				fmt.Sprintf("The %s operation on the ciphertext %s completed, however the audit sink could not record it: %s", event.Operation, event.CiphertextUuid, err.Error()),
+			)
+		}
 	}
 }
 
 func (f *AZClientsFactoryImpl) GetMergedWrappingKeyCoordinate(ctx context.Context, param *core.WrappingKeyCoordinateModel) (core.WrappingKeyCoordinate, error) {
+	return f.MergeWrappingKeyCoordinate(ctx, param, f.GetKeysClient)
+}
+
+// MergeWrappingKeyCoordinate merges the wrapping key coordinate with the provider defaults; the version of the key
+// is established with the keys client the supplier returns.
+func (f *AZClientsFactoryImpl) MergeWrappingKeyCoordinate(ctx context.Context, param *core.WrappingKeyCoordinateModel, keysClient func(vaultName string) (core.AzKeyClientAbstraction, error)) (core.WrappingKeyCoordinate, error) {
 
 	if f.DisallowResourceSpecifiedWrappingKey && param != nil {
 		pc := param.AsCoordinate()
@@ -249,7 +368,7 @@ func (f *AZClientsFactoryImpl) GetMergedWrappingKeyCoordinate(ctx context.Contex
 			}
 		}
 
-		if kClient, err := f.GetKeysClient(base.VaultName); err != nil {
+		if kClient, err := keysClient(base.VaultName); err != nil {
 			return core.WrappingKeyCoordinate{}, fmt.Errorf("cannot obtain key client: %s", err.Error())
 		} else {
 			if fillDefaultsErr := base.FillDefaults(ctx, kClient); fillDefaultsErr == nil {
@@ -299,21 +418,96 @@ func (f *AZClientsFactoryImpl) GetTackedObjectUses(ctx context.Context, id strin
 	}
 }
 
-func (f *AZClientsFactoryImpl) TrackObjectId(ctx context.Context, id string) error {
+func (f *AZClientsFactoryImpl) TrackObjectId(ctx context.Context, id string, destination string) error {
+	if f.hashTacker != nil {
+		return f.hashTacker.TrackObjectId(ctx, id, destination)
+	} else {
+		return nil
+	}
+}
+
+func (f *AZClientsFactoryImpl) ListTrackedObjects(ctx context.Context, uuids []string) ([]core.TrackedObject, error) {
 	if f.hashTacker != nil {
-		return f.hashTacker.TrackObjectId(ctx, id)
+		return f.hashTacker.ListTrackedObjects(ctx, uuids)
 	} else {
+		return nil, errors.New("object tracking is not configured on the provider")
+	}
+}
+
+func (f *AZClientsFactoryImpl) GetExpiryWarningWindow() time.Duration {
+	return f.ExpiryWarningWindow
+}
+
+func (f *AZClientsFactoryImpl) IsProvenanceTaggingEnabled() bool {
+	return f.ProvenanceTagging
+}
+
+func (f *AZClientsFactoryImpl) GetProvenanceTags(header core.ConfidentialDataJsonHeader, ciphertext string) map[string]string {
+	if !f.ProvenanceTagging {
+		return nil
+	}
+
+	return core.NewProvenanceTags(header.Uuid, ciphertext, f.ProviderLabels, time.Now())
+}
+
+func (f *AZClientsFactoryImpl) GetFingerprintKey() []byte {
+	if len(f.FingerprintKey) == 0 {
 		return nil
 	}
+	return f.FingerprintKey
+}
+
+func (f *AZClientsFactoryImpl) GetCiphertextRevocation(ctx context.Context, uuid string) (*core.CiphertextRevocation, error) {
+	if f.revocationList != nil {
+		return f.revocationList.GetRevocation(ctx, uuid)
+	} else {
+		return nil, nil
+	}
+}
+
+// RevokeCiphertext records the revocation in the object tracker. The revocation can be recorded only where the
+// provider consults the tracker for the revoked ciphertexts: otherwise, this provider would keep using the
+// ciphertext it has just revoked.
+func (f *AZClientsFactoryImpl) RevokeCiphertext(ctx context.Context, uuid string, reason string) (*core.CiphertextRevocation, error) {
+	recorder := revocationRecorderOf(f.revocationList)
+	if recorder == nil {
+		return nil, errors.New("ciphertexts can be revoked only where the provider consults the object tracker for the revoked ciphertexts; set revocation.use_tracker on the provider")
+	}
+
+	if err := recorder.RevokeObjectId(ctx, uuid, reason); err != nil {
+		return nil, err
+	}
+	return recorder.GetRevocation(ctx, uuid)
+}
+
+// GetAzTenantId returns the tenant of the provider's credential. The tenant is read from the access token
+// only once per provider process; the error reading it is returned to every subsequent caller.
+func (f *AZClientsFactoryImpl) GetAzTenantId(ctx context.Context) (string, error) {
+	f.tenantIdOnce.Do(func() {
+		if len(f.AzTenantId) == 0 {
+			f.AzTenantId, f.tenantIdErr = TenantIdOfCredential(ctx, f.Credential)
+		}
+	})
+
+	return f.AzTenantId, f.tenantIdErr
 }
 
 var _ core.AZClientsFactory = &AZClientsFactoryImpl{}
 
 // EnsureCanPlaceLabelledObjectAt verifies whether specific constraints for provider and placement are admissible
 // to place the object at the intended location.
-func (f *AZClientsFactoryImpl) EnsureCanPlaceLabelledObjectAt(_ context.Context, providerConstraints []core.ProviderConstraint, placementConstraints []core.PlacementConstraint, tfResourceType string, targetCoord core.LabelledObject, diagnostics *diag.Diagnostics) {
+func (f *AZClientsFactoryImpl) EnsureCanPlaceLabelledObjectAt(ctx context.Context, providerConstraints []core.ProviderConstraint, placementConstraints []core.PlacementConstraint, tfResourceType string, targetCoord core.LabelledObject, diagnostics *diag.Diagnostics) {
 	if len(providerConstraints) > 0 {
-		if len(f.ProviderLabels) == 0 {
+		expr, exprErr := core.ParseProviderConstraints(providerConstraints)
+		if exprErr != nil {
+			diagnostics.AddError(
+				"Invalid provider constraint",
+				fmt.Sprintf("The ciphertext embeds provider constraints that cannot be parsed: %s. Re-encrypt the ciphertext with correct provider constraints.", exprErr.Error()),
+			)
+			return
+		}
+
+		if expr.RequiresLabels() && len(f.ProviderLabels) == 0 {
 			diagnostics.AddError(
 				"Insure provider",
 				fmt.Sprintf("This %s defines constraints on the provider. This provider is not configured with any labels. Either replace ciphertext by removing the provider constriant, or label the provider.", tfResourceType),
@@ -321,25 +515,81 @@ func (f *AZClientsFactoryImpl) EnsureCanPlaceLabelledObjectAt(_ context.Context,
 			return
 		}
 
-		if !core.AnyIsInWithComparator(
-			f.ProviderLabels,
-			providerConstraints, func(a string, b core.ProviderConstraint) bool { return a == string(b) }) {
+		if !expr.IsSatisfiedBy(f.ProviderLabels) {
 			diagnostics.AddError("Mismatched placement", fmt.Sprintf("The constraints embedded into the ciphertext disallow placement of this %s by this provider. More information is not given for security reasons. Re-encrypt the ciphertext with correct provider constraints.", tfResourceType))
 		}
+
+		f.ensureAzureBindingsMatch(ctx, expr, tfResourceType, diagnostics)
 	}
 
 	if len(placementConstraints) > 0 {
 		if targetCoord == nil {
 			diagnostics.AddError("Nil target object", "The ciphertext embeds requirements as to the target objects that can be created using the contained information, however nil target address is calculated. Either the placement constraint is not necessary, or it's a provider bug that needs to be reported to the maintainer.")
 		} else {
-			if !core.ContainsWithComparator(
-				placementConstraints,
-				targetCoord.GetLabel(),
-				func(a core.PlacementConstraint, b string) bool { return string(a) == b }) {
-				diagnostics.AddError("Mismatched placement", fmt.Sprintf("The constraints embedded into the ciphertext disallow placement of this %s into the specified destination. More information is not given for security reasons. Re-encrypt the ciphertext with correct placement constraints.", tfResourceType))
-			}
+			ensurePlacementConstraintsMatch(placementConstraints, tfResourceType, targetCoord.GetLabel(), diagnostics)
+		}
+	}
+}
+
+// ensureAzureBindingsMatch checks that the provider runs in the Azure tenant and the subscription the
+// ciphertext is bound to.
+func (f *AZClientsFactoryImpl) ensureAzureBindingsMatch(ctx context.Context, expr core.ProviderConstraintExpression, tfResourceType string, diagnostics *diag.Diagnostics) {
+	if expr.BindsAzureTenant() {
+		if tenantId, err := f.GetAzTenantId(ctx); err != nil {
+			diagnostics.AddError(
+				"Cannot determine Azure tenant",
+				fmt.Sprintf("The ciphertext can be used only in specific Azure tenants, however the tenant of the provider's credential cannot be determined: %s", err.Error()),
+			)
+		} else if !expr.AllowsAzureTenant(tenantId) {
+			diagnostics.AddError(
+				"Mismatched Azure tenant",
+				fmt.Sprintf("The ciphertext disallows the placement of this %s in the tenant %s of the provider's credential. Re-encrypt the ciphertext for the correct tenant, or run the provider with the credential of the correct tenant.", tfResourceType, tenantId),
+			)
+		}
+	}
+
+	if expr.BindsAzureSubscription() {
+		if len(f.DefaultAzSubscriptionId) == 0 {
+			diagnostics.AddError(
+				"Missing Azure subscription",
+				fmt.Sprintf("The ciphertext can be used only in specific Azure subscriptions. Configure the provider's subscription_id to use this %s.", tfResourceType),
+			)
+		} else if !expr.AllowsAzureSubscription(f.DefaultAzSubscriptionId) {
+			diagnostics.AddError(
+				"Mismatched Azure subscription",
+				fmt.Sprintf("The ciphertext disallows the placement of this %s in the subscription %s configured on the provider. Re-encrypt the ciphertext for the correct subscription.", tfResourceType, f.DefaultAzSubscriptionId),
+			)
+		}
+	}
+}
+
+// ensurePlacementConstraintsMatch checks that at least one placement constraint matches the destination label.
+// Where the ciphertext uses patterns, the diagnostic lists the patterns that didn't match.
+func ensurePlacementConstraintsMatch(placementConstraints []core.PlacementConstraint, tfResourceType string, label string, diagnostics *diag.Diagnostics) {
+	matchers, parseErr := core.ParsePlacementConstraints(placementConstraints)
+	if parseErr != nil {
+		diagnostics.AddError(
+			"Invalid placement constraint",
+			fmt.Sprintf("The ciphertext embeds placement constraints that cannot be parsed: %s. Re-encrypt the ciphertext with correct placement constraints.", parseErr.Error()),
+		)
+		return
+	}
+
+	var failedPatterns []string
+	for _, m := range matchers {
+		if m.Matches(label) {
+			return
+		}
+		if m.Kind() != "exact" {
+			failedPatterns = append(failedPatterns, fmt.Sprintf("%s pattern %s", m.Kind(), m.Constraint))
 		}
 	}
+
+	if len(failedPatterns) == 0 {
+		diagnostics.AddError("Mismatched placement", fmt.Sprintf("The constraints embedded into the ciphertext disallow placement of this %s into the specified destination. More information is not given for security reasons. Re-encrypt the ciphertext with correct placement constraints.", tfResourceType))
+	} else {
+		diagnostics.AddError("Mismatched placement", fmt.Sprintf("The destination %s of this %s does not match any placement constraint embedded into the ciphertext. Not matching: %s. Re-encrypt the ciphertext with correct placement constraints.", label, tfResourceType, strings.Join(failedPatterns, "; ")))
+	}
 }
 
 type AZConnectorProviderImpl struct {
@@ -347,6 +597,24 @@ type AZConnectorProviderImpl struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// cekCache the CEK cache of the most recent configuration of this provider instance
+	cekCache *CEKCache
+
+	// overrides the connection to Azure set by NewWithAzureOverrides
+	overrides *AzureOverrides
+}
+
+// newCEKCache replaces the CEK cache of this provider instance, zeroising the cache of the previous
+// configuration. Returns nil where the caching is disabled.
+func (p *AZConnectorProviderImpl) newCEKCache(size int) *CEKCache {
+	p.cekCache.Release()
+	p.cekCache = nil
+
+	if size > 0 {
+		p.cekCache = NewCEKCache(size)
+	}
+	return p.cekCache
 }
 
 type AzStorageAccountTableTrackerConfigModel struct {
@@ -355,6 +623,23 @@ type AzStorageAccountTableTrackerConfigModel struct {
 	PartitionName types.String `tfsdk:"partition_name"`
 }
 
+type AzStorageAccountTableAuditConfigModel struct {
+	AccountName      types.String `tfsdk:"account_name"`
+	ConnectionString types.String `tfsdk:"connection_string"`
+	TableName        types.String `tfsdk:"table_name"`
+	PartitionName    types.String `tfsdk:"partition_name"`
+}
+
+type RevocationConfigModel struct {
+	FilePath   types.String `tfsdk:"file_path"`
+	UseTracker types.Bool   `tfsdk:"use_tracker"`
+}
+
+type AuditConfigModel struct {
+	FilePath       types.String                           `tfsdk:"file_path"`
+	StorageAccount *AzStorageAccountTableAuditConfigModel `tfsdk:"storage_account"`
+}
+
 type AZConnectorProviderImplModel struct {
 	TenantID                     types.String                     `tfsdk:"tenant_id"`
 	SubscriptionID               types.String                     `tfsdk:"subscription_id"`
@@ -366,6 +651,33 @@ type AZConnectorProviderImplModel struct {
 	DefaultDestinationVaultName          types.String                             `tfsdk:"default_destination_vault_name"`
 	Constraints                          types.Set                                `tfsdk:"constraints"`
 	StorageAccountTracker                *AzStorageAccountTableTrackerConfigModel `tfsdk:"storage_account_tracker"`
+	Audit                                *AuditConfigModel                        `tfsdk:"audit"`
+	Revocation                           *RevocationConfigModel                   `tfsdk:"revocation"`
+	ExpiryWarningDays                    types.Int64                              `tfsdk:"expiry_warning_days"`
+	ProvenanceTags                       types.Bool                               `tfsdk:"provenance_tags"`
+	Retry                                *RetryConfigModel                        `tfsdk:"retry"`
+	CEKCacheSize                         types.Int64                              `tfsdk:"cek_cache_size"`
+	FingerprintKey                       types.String                             `tfsdk:"fingerprint_key"`
+}
+
+// GetExpiryWarningWindow returns the expiry warning window configured on the provider, or the default
+// window if the provider does not configure it.
+func (pm *AZConnectorProviderImplModel) GetExpiryWarningWindow() time.Duration {
+	if pm.ExpiryWarningDays.IsNull() || pm.ExpiryWarningDays.IsUnknown() {
+		return core.ExpiryWarningWindowOf(core.DefaultExpiryWarningDays)
+	}
+
+	return core.ExpiryWarningWindowOf(pm.ExpiryWarningDays.ValueInt64())
+}
+
+// GetCEKCacheSize returns the number of the content encryption keys the provider should cache, or the default
+// size if the provider does not configure it.
+func (pm *AZConnectorProviderImplModel) GetCEKCacheSize() int {
+	if pm.CEKCacheSize.IsNull() || pm.CEKCacheSize.IsUnknown() {
+		return DefaultCEKCacheSize
+	}
+
+	return int(pm.CEKCacheSize.ValueInt64())
 }
 
 func (pm *AZConnectorProviderImplModel) GetProviderLabels(ctx context.Context) []string {
@@ -463,6 +775,77 @@ func (p *AZConnectorProviderImpl) Schema(_ context.Context, _ tfprovider.SchemaR
 					},
 				},
 			},
+			"audit": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configures the audit log recording every decryption, create, update, and delete performed " +
+					"with the ciphertext. The audit events carry ciphertext uuid, object type, destination, wrapping key, and the " +
+					"outcome of the operation; the events never include the plaintext.",
+				Description: "Configures the audit log recording operations performed with the ciphertext",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"file_path": schema.StringAttribute{
+						MarkdownDescription: "Path to the file where audit events will be appended as JSON lines",
+						Description:         "Path to the file where audit events will be appended as JSON lines",
+						Optional:            true,
+						Validators: []validator.String{
+							tfstringvalidators.LengthAtLeast(1),
+						},
+					},
+					"storage_account": schema.SingleNestedAttribute{
+						MarkdownDescription: "Azure Storage Account table where audit events will be recorded",
+						Description:         "Azure Storage Account table where audit events will be recorded",
+						Optional:            true,
+						Attributes: map[string]schema.Attribute{
+							"account_name": schema.StringAttribute{
+								MarkdownDescription: "Storage account name to use. The provider credential is used to access the account",
+								Description:         "Storage account name to use",
+								Optional:            true,
+								Validators: []validator.String{
+									tfstringvalidators.ExactlyOneOf(path.MatchRelative().AtParent().AtName("connection_string")),
+								},
+							},
+							"connection_string": schema.StringAttribute{
+								MarkdownDescription: "Connection string of the storage account, e.g. to use Azurite",
+								Description:         "Connection string of the storage account",
+								Optional:            true,
+								Sensitive:           true,
+							},
+							"table_name": schema.StringAttribute{
+								MarkdownDescription: "Table name to use",
+								Description:         "Table name to use",
+								Required:            true,
+							},
+							"partition_name": schema.StringAttribute{
+								MarkdownDescription: "Partition name to use",
+								Description:         "Partition name to use",
+								Required:            true,
+							},
+						},
+					},
+				},
+			},
+			"revocation": schema.SingleNestedAttribute{
+				MarkdownDescription: "Configures the list of revoked ciphertexts. The provider refuses to use a revoked ciphertext " +
+					"to create, read, or update the objects, and to unpack the content. The list can be kept in a local JSON file, in " +
+					"the storage account table of the object tracker, or both.",
+				Description: "Configures the list of revoked ciphertexts",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"file_path": schema.StringAttribute{
+						MarkdownDescription: "Path to the JSON file containing an array of revocations, e.g. " +
+							"`[{\"uuid\": \"...\", \"reason\": \"...\"}]`",
+						Description: "Path to the JSON file containing an array of revocations",
+						Optional:    true,
+						Validators: []validator.String{
+							tfstringvalidators.LengthAtLeast(1),
+						},
+					},
+					"use_tracker": schema.BoolAttribute{
+						MarkdownDescription: "Consult the object tracker for revoked ciphertexts. Requires `storage_account_tracker`",
+						Description:         "Consult the object tracker for revoked ciphertexts",
+						Optional:            true,
+					},
+				},
+			},
 			"default_wrapping_key": schema.SingleNestedAttribute{
 				Attributes: map[string]schema.Attribute{
 					"vault_name": schema.StringAttribute{
@@ -490,6 +873,83 @@ func (p *AZConnectorProviderImpl) Schema(_ context.Context, _ tfprovider.SchemaR
 				Description:         "Disallow individual resources to specify resource-level unwrapping keys",
 				MarkdownDescription: "Disallow individual resources to specify resource-level unwrapping keys",
 			},
+			"expiry_warning_days": schema.Int64Attribute{
+				Optional: true,
+				Description: "Number of days before the ciphertext expires (or can no longer be used to create objects) " +
+					"during which plan and apply emit warnings. Defaults to 30; 0 disables the warnings",
+				MarkdownDescription: "Number of days before the ciphertext expires (or can no longer be used to create objects) " +
+					"during which plan and apply emit warnings. Defaults to `30`; `0` disables the warnings.",
+				Validators: []validator.Int64{
+					tfint64validators.AtLeast(0),
+				},
+			},
+			"retry": schema.SingleNestedAttribute{
+				MarkdownDescription: "Retry policy of the calls to Azure Key Vault and API Management, e.g. to weather Key Vault " +
+					"throttling. The settings that are not given retain the Azure SDK defaults.",
+				Description: "Retry policy of the calls to Azure Key Vault and API Management",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"max_retries": schema.Int64Attribute{
+						MarkdownDescription: "Maximum number of retries. `0` uses the SDK default of 3; `-1` disables the retries",
+						Description:         "Maximum number of retries. 0 uses the SDK default of 3; -1 disables the retries",
+						Optional:            true,
+						Validators: []validator.Int64{
+							tfint64validators.AtLeast(-1),
+						},
+					},
+					"retry_delay": schema.StringAttribute{
+						MarkdownDescription: "Initial delay before a retry, e.g. `4s`. The delay grows exponentially with each retry",
+						Description:         "Initial delay before a retry, e.g. 4s",
+						Optional:            true,
+					},
+					"max_retry_delay": schema.StringAttribute{
+						MarkdownDescription: "Maximum delay before a retry, e.g. `60s`",
+						Description:         "Maximum delay before a retry, e.g. 60s",
+						Optional:            true,
+					},
+					"try_timeout": schema.StringAttribute{
+						MarkdownDescription: "Maximum time allowed for a single attempt, e.g. `1m`",
+						Description:         "Maximum time allowed for a single attempt, e.g. 1m",
+						Optional:            true,
+					},
+					"status_codes": schema.SetAttribute{
+						MarkdownDescription: "HTTP status codes that are retried, e.g. `[408, 429, 500, 502, 503, 504]`",
+						Description:         "HTTP status codes that are retried",
+						Optional:            true,
+						ElementType:         types.Int64Type,
+					},
+				},
+			},
+			"cek_cache_size": schema.Int64Attribute{
+				Optional: true,
+				Description: "Number of the unwrapped content encryption keys the provider keeps in memory to reduce the number of " +
+					"Key Vault decrypt calls. Defaults to 256; 0 disables the cache",
+				MarkdownDescription: "Number of the unwrapped content encryption keys the provider keeps in memory to reduce the number of " +
+					"Key Vault decrypt calls. Defaults to `256`; `0` disables the cache.",
+				Validators: []validator.Int64{
+					tfint64validators.AtLeast(0),
+				},
+			},
+			"fingerprint_key": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "Secret key of the fingerprints of the confidential values kept in the private state of the resources. " +
+					"Where set, refreshing the API Management named values and subscriptions does not decrypt the ciphertext " +
+					"unless the ciphertext has changed",
+				MarkdownDescription: "Secret key of the fingerprints of the confidential values kept in the private state of the resources. " +
+					"Where set, refreshing the API Management named values and subscriptions does not decrypt the ciphertext " +
+					"unless the ciphertext has changed.",
+				Validators: []validator.String{
+					tfstringvalidators.LengthAtLeast(16),
+				},
+			},
+			"provenance_tags": schema.BoolAttribute{
+				Optional: true,
+				Description: "Stamp provenance tags (ciphertext uuid, SHA-256 of the ciphertext, provider labels, and timestamp) " +
+					"on the created Key Vault objects and API Management named values. Defaults to false",
+				MarkdownDescription: "Stamp provenance tags (ciphertext uuid, SHA-256 of the ciphertext, provider labels, and timestamp) " +
+					"on the created Key Vault objects and API Management named values. Defaults to `false`.",
+			},
 		},
 	}
 }
@@ -498,6 +958,8 @@ func (p *AZConnectorProviderImpl) DataSources(ctx context.Context) []func() data
 	tflog.Debug(ctx, "AzConfidential: initializing data sources")
 	return []func() datasource.DataSource{
 		general.NewConfidentialPasswordDataSource,
+		general.NewTrackedCiphertextsDataSource,
+		keyvault.NewWrappingKeyPublicInfoDataSource,
 	}
 }
 
@@ -507,6 +969,8 @@ func (p *AZConnectorProviderImpl) Resources(ctx context.Context) []func() resour
 		keyvault.NewSecretResource,
 		keyvault.NewKeyResource,
 		keyvault.NewCertificateResource,
+		keyvault.NewWrappingKeyResource,
+		general.NewCiphertextRevocationResource,
 		apim.NewNamedValueResource,
 		apim.NewSubscriptionResource,
 	}
@@ -524,6 +988,72 @@ func (p *AZConnectorProviderImpl) ConfigureHashTracker(_ context.Context, data A
 	return nil, nil
 }
 
+func (p *AZConnectorProviderImpl) ConfigureAuditSink(_ context.Context, data AZConnectorProviderImplModel, cred azcore.TokenCredential) (AuditSink, error) {
+	if data.Audit == nil {
+		return nil, nil
+	}
+
+	var sinks MultiAuditSink
+
+	if !data.Audit.FilePath.IsNull() {
+		fileSink, err := NewJsonLinesFileAuditSink(data.Audit.FilePath.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, fileSink)
+	}
+
+	if data.Audit.StorageAccount != nil {
+		tableSink, err := NewAzStorageAccountTableAuditSink(
+			cred,
+			data.Audit.StorageAccount.ConnectionString.ValueString(),
+			data.Audit.StorageAccount.AccountName.ValueString(),
+			data.Audit.StorageAccount.TableName.ValueString(),
+			data.Audit.StorageAccount.PartitionName.ValueString(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, tableSink)
+	}
+
+	if len(sinks) == 0 {
+		return nil, errors.New("audit block must specify at least a file path or a storage account")
+	}
+
+	return sinks, nil
+}
+
+func (p *AZConnectorProviderImpl) ConfigureRevocationList(_ context.Context, data AZConnectorProviderImplModel, hashTracker ObjectHashTracker) (RevocationList, error) {
+	if data.Revocation == nil {
+		return nil, nil
+	}
+
+	var lists MultiRevocationList
+
+	if !data.Revocation.FilePath.IsNull() {
+		fileList, err := NewFileRevocationList(data.Revocation.FilePath.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, fileList)
+	}
+
+	if data.Revocation.UseTracker.ValueBool() {
+		trackerList, ok := hashTracker.(RevocationList)
+		if !ok {
+			return nil, errors.New("revocation uses the object tracker, however storage_account_tracker is not configured")
+		}
+		lists = append(lists, trackerList)
+	}
+
+	if len(lists) == 0 {
+		return nil, errors.New("revocation block must specify at least a file path or use the tracker")
+	}
+
+	return lists, nil
+}
+
 func (p *AZConnectorProviderImpl) Configure(ctx context.Context, req tfprovider.ConfigureRequest, resp *tfprovider.ConfigureResponse) {
 	tflog.Debug(ctx, "AzConfidential: attempting to configure the provider")
 	var data AZConnectorProviderImplModel
@@ -538,7 +1068,9 @@ func (p *AZConnectorProviderImpl) Configure(ctx context.Context, req tfprovider.
 	var cred azcore.TokenCredential
 	var azCredError error
 
-	if data.SpecifiesCredentialParameters() {
+	if p.overrides != nil && p.overrides.Credential != nil {
+		cred = p.overrides.Credential
+	} else if data.SpecifiesCredentialParameters() {
 		cred, azCredError = data.GetExplicitCredential()
 	} else {
 		cred, azCredError = azidentity.NewDefaultAzureCredential(nil)
@@ -556,6 +1088,24 @@ func (p *AZConnectorProviderImpl) Configure(ctx context.Context, req tfprovider.
 		return
 	}
 
+	auditSink, auditSinkInitErr := p.ConfigureAuditSink(ctx, data, cred)
+	if auditSinkInitErr != nil {
+		resp.Diagnostics.AddError("Failed to initialize audit sink", auditSinkInitErr.Error())
+		return
+	}
+
+	revocationList, revocationInitErr := p.ConfigureRevocationList(ctx, data, hashTracker)
+	if revocationInitErr != nil {
+		resp.Diagnostics.AddError("Failed to initialize revocation list", revocationInitErr.Error())
+		return
+	}
+
+	retryOptions, retryErr := data.Retry.GetRetryOptions(ctx)
+	if retryErr != nil {
+		resp.Diagnostics.AddError("Invalid retry configuration", retryErr.Error())
+		return
+	}
+
 	tflog.Info(ctx, "AzConfidential provider was able to obtain access token to Azure API")
 
 	disallowResourceLevelWrappingKey := false
@@ -564,10 +1114,23 @@ func (p *AZConnectorProviderImpl) Configure(ctx context.Context, req tfprovider.
 		disallowResourceLevelWrappingKey = data.DisallowResourceSpecifiedWrappingKey.ValueBool()
 	}
 
-	factory := &AZClientsFactoryImpl{
-		CachedAzClientsSupplier: CachedAzClientsSupplier{
-			Credential: cred,
+	clientsSupplier := CachedAzClientsSupplier{
+		Credential: cred,
+		ClientOptions: policy.ClientOptions{
+			Retry: retryOptions,
 		},
+	}
+	if p.overrides != nil {
+		clientsSupplier.ClientOptions.Transport = p.overrides.Transport
+		clientsSupplier.Endpoints = p.overrides.Endpoints
+
+		if clientsSupplier.Endpoints.IsOverridden() {
+			tflog.Warn(ctx, "AzConfidential provider connects to the overridden Azure endpoints")
+		}
+	}
+
+	factory := &AZClientsFactoryImpl{
+		CachedAzClientsSupplier: clientsSupplier,
 
 		DefaultWrappingKey:                   data.DefaultWrappingKeyCoordinate,
 		DisallowResourceSpecifiedWrappingKey: disallowResourceLevelWrappingKey,
@@ -575,7 +1138,13 @@ func (p *AZConnectorProviderImpl) Configure(ctx context.Context, req tfprovider.
 
 		DefaultDestinationVault: data.DefaultDestinationVaultName.ValueString(),
 		ProviderLabels:          data.GetProviderLabels(ctx),
+		ExpiryWarningWindow:     data.GetExpiryWarningWindow(),
+		ProvenanceTagging:       data.ProvenanceTags.ValueBool(),
+		CEKCache:                p.newCEKCache(data.GetCEKCacheSize()),
+		FingerprintKey:          []byte(data.FingerprintKey.ValueString()),
 		hashTacker:              hashTracker,
+		auditSink:               auditSink,
+		revocationList:          revocationList,
 	}
 
 	resp.DataSourceData = factory
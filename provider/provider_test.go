@@ -53,8 +53,8 @@ func (m *HashTrackerMock) IsObjectIdTracked(ctx context.Context, id string) (boo
 }
 
 // TrackObjectId Track object Id in the memory of seeing objects
-func (m *HashTrackerMock) TrackObjectId(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *HashTrackerMock) TrackObjectId(ctx context.Context, id string, destination string) error {
+	args := m.Called(ctx, id, destination)
 	return args.Error(0)
 }
 
@@ -162,6 +162,165 @@ func Test_EnsureCanPlace_Errs_OnLabelMismatchForDataSource(t *testing.T) {
 		dg[0].Detail())
 }
 
+func Test_EnsureCanPlace_Ok_OnGlobPatternMatch(t *testing.T) {
+	factory := &AZClientsFactoryImpl{}
+
+	reqCoordinate := core.AzKeyVaultObjectCoordinate{
+		VaultName: "kv-prod-weu",
+		Type:      "secrets",
+		Name:      "app-password",
+	}
+
+	dg := diag.Diagnostics{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(),
+		nil,
+		[]core.PlacementConstraint{"az-c-keyvault://kv-test@secrets=app-password", "glob:az-c-keyvault://kv-prod-*@secrets=app-*"},
+		"secret",
+		&reqCoordinate,
+		&dg)
+	assert.False(t, dg.HasError())
+}
+
+func Test_EnsureCanPlace_Errs_OnPatternMismatch(t *testing.T) {
+	factory := &AZClientsFactoryImpl{}
+
+	reqCoordinate := core.AzKeyVaultObjectCoordinate{
+		VaultName: "kv-test-weu",
+		Type:      "secrets",
+		Name:      "app-password",
+	}
+
+	dg := diag.Diagnostics{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(),
+		nil,
+		[]core.PlacementConstraint{"regex:az-c-keyvault://kv-prod-.+@secrets=app-.*"},
+		"secret",
+		&reqCoordinate,
+		&dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Mismatched placement", dg[0].Summary())
+	assert.Contains(t, dg[0].Detail(), "az-c-keyvault://kv-test-weu@secrets=app-password")
+	assert.Contains(t, dg[0].Detail(), "regex pattern regex:az-c-keyvault://kv-prod-.+@secrets=app-.*")
+}
+
+func Test_EnsureCanPlace_Errs_OnInvalidPattern(t *testing.T) {
+	factory := &AZClientsFactoryImpl{}
+
+	reqCoordinate := core.AzKeyVaultObjectCoordinate{
+		VaultName: "kv-prod-weu",
+		Type:      "secrets",
+		Name:      "app-password",
+	}
+
+	dg := diag.Diagnostics{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(),
+		nil,
+		[]core.PlacementConstraint{"glob:az-c-keyvault://kv-prod-[@secrets=app-*"},
+		"secret",
+		&reqCoordinate,
+		&dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Invalid placement constraint", dg[0].Summary())
+}
+
+func Test_EnsureCanPlace_ProviderConstraintGroups(t *testing.T) {
+	factory := &AZClientsFactoryImpl{
+		ProviderLabels: []string{"prod", "weu"},
+	}
+
+	dg := diag.Diagnostics{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(),
+		[]core.ProviderConstraint{core.AllOfProviderConstraint("prod", "weu"), core.NoneOfProviderConstraint("sandbox")},
+		nil,
+		"secret",
+		nil,
+		&dg)
+	assert.False(t, dg.HasError())
+
+	dg = diag.Diagnostics{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(),
+		[]core.ProviderConstraint{core.AllOfProviderConstraint("prod", "neu")},
+		nil,
+		"secret",
+		nil,
+		&dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Mismatched placement", dg[0].Summary())
+}
+
+func Test_EnsureCanPlace_NoneOfAcceptsUnlabelledProvider(t *testing.T) {
+	factory := &AZClientsFactoryImpl{}
+
+	dg := diag.Diagnostics{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(),
+		[]core.ProviderConstraint{core.NoneOfProviderConstraint("sandbox")},
+		nil,
+		"secret",
+		nil,
+		&dg)
+	assert.False(t, dg.HasError())
+}
+
+func Test_EnsureCanPlace_AzureTenantBinding(t *testing.T) {
+	factory := &AZClientsFactoryImpl{
+		AzTenantId: "11111111-1111-1111-1111-111111111111",
+	}
+
+	dg := diag.Diagnostics{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(),
+		[]core.ProviderConstraint{core.AzureTenantConstraint("11111111-1111-1111-1111-111111111111")},
+		nil,
+		"secret",
+		nil,
+		&dg)
+	assert.False(t, dg.HasError())
+
+	dg = diag.Diagnostics{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(),
+		[]core.ProviderConstraint{core.AzureTenantConstraint("22222222-2222-2222-2222-222222222222")},
+		nil,
+		"secret",
+		nil,
+		&dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Mismatched Azure tenant", dg[0].Summary())
+}
+
+func Test_EnsureCanPlace_AzureTenantCannotBeDetermined(t *testing.T) {
+	factory := &AZClientsFactoryImpl{}
+
+	dg := diag.Diagnostics{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(),
+		[]core.ProviderConstraint{core.AzureTenantConstraint("11111111-1111-1111-1111-111111111111")},
+		nil,
+		"secret",
+		nil,
+		&dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Cannot determine Azure tenant", dg[0].Summary())
+}
+
+func Test_EnsureCanPlace_AzureSubscriptionBinding(t *testing.T) {
+	constraints := []core.ProviderConstraint{core.AzureSubscriptionConstraint("33333333-3333-3333-3333-333333333333")}
+
+	dg := diag.Diagnostics{}
+	factory := &AZClientsFactoryImpl{DefaultAzSubscriptionId: "33333333-3333-3333-3333-333333333333"}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(), constraints, nil, "secret", nil, &dg)
+	assert.False(t, dg.HasError())
+
+	dg = diag.Diagnostics{}
+	factory = &AZClientsFactoryImpl{DefaultAzSubscriptionId: "44444444-4444-4444-4444-444444444444"}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(), constraints, nil, "secret", nil, &dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Mismatched Azure subscription", dg[0].Summary())
+
+	dg = diag.Diagnostics{}
+	factory = &AZClientsFactoryImpl{}
+	factory.EnsureCanPlaceLabelledObjectAt(context.Background(), constraints, nil, "secret", nil, &dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Missing Azure subscription", dg[0].Summary())
+}
+
 func Test_AZCPIM_GetProviderLabels(t *testing.T) {
 	mdl := AZConnectorProviderImplModel{}
 
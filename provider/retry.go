@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// RetryConfigModel the retry policy of the Azure clients. The settings that are not given retain the
+// Azure SDK defaults.
+type RetryConfigModel struct {
+	MaxRetries    types.Int64  `tfsdk:"max_retries"`
+	RetryDelay    types.String `tfsdk:"retry_delay"`
+	MaxRetryDelay types.String `tfsdk:"max_retry_delay"`
+	TryTimeout    types.String `tfsdk:"try_timeout"`
+	StatusCodes   types.Set    `tfsdk:"status_codes"`
+}
+
+func parseRetryDuration(v types.String, attrName string) (time.Duration, error) {
+	if v.IsNull() || v.IsUnknown() {
+		return 0, nil
+	}
+
+	rv, err := time.ParseDuration(v.ValueString())
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a valid duration: %s", attrName, err.Error())
+	} else if rv < 0 {
+		return 0, fmt.Errorf("%s must not be negative", attrName)
+	}
+
+	return rv, nil
+}
+
+// GetRetryOptions converts the retry configuration into the retry options of the Azure SDK
+func (m *RetryConfigModel) GetRetryOptions(ctx context.Context) (policy.RetryOptions, error) {
+	rv := policy.RetryOptions{}
+	if m == nil {
+		return rv, nil
+	}
+
+	if !m.MaxRetries.IsNull() && !m.MaxRetries.IsUnknown() {
+		rv.MaxRetries = int32(m.MaxRetries.ValueInt64())
+	}
+
+	var err error
+	if rv.RetryDelay, err = parseRetryDuration(m.RetryDelay, "retry_delay"); err != nil {
+		return rv, err
+	}
+	if rv.MaxRetryDelay, err = parseRetryDuration(m.MaxRetryDelay, "max_retry_delay"); err != nil {
+		return rv, err
+	}
+	if rv.TryTimeout, err = parseRetryDuration(m.TryTimeout, "try_timeout"); err != nil {
+		return rv, err
+	}
+
+	if !m.StatusCodes.IsNull() && !m.StatusCodes.IsUnknown() {
+		var codes []int64
+		if dg := m.StatusCodes.ElementsAs(ctx, &codes, false); dg.HasError() {
+			return rv, fmt.Errorf("status_codes cannot be read: %v", dg)
+		}
+
+		rv.StatusCodes = make([]int, len(codes))
+		for i, c := range codes {
+			rv.StatusCodes[i] = int(c)
+		}
+	}
+
+	return rv, nil
+}
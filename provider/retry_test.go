@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RetryConfigModel_GetRetryOptions(t *testing.T) {
+	var noConfig *RetryConfigModel
+	rv, err := noConfig.GetRetryOptions(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), rv.MaxRetries)
+
+	cfg := &RetryConfigModel{
+		MaxRetries:    types.Int64Value(6),
+		RetryDelay:    types.StringValue("2s"),
+		MaxRetryDelay: types.StringValue("1m"),
+		TryTimeout:    types.StringNull(),
+		StatusCodes:   types.SetValueMust(types.Int64Type, []attr.Value{types.Int64Value(429)}),
+	}
+
+	rv, err = cfg.GetRetryOptions(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int32(6), rv.MaxRetries)
+	assert.Equal(t, 2*time.Second, rv.RetryDelay)
+	assert.Equal(t, time.Minute, rv.MaxRetryDelay)
+	assert.Equal(t, time.Duration(0), rv.TryTimeout)
+	assert.Equal(t, []int{429}, rv.StatusCodes)
+}
+
+func Test_RetryConfigModel_GetRetryOptions_RejectsInvalidDuration(t *testing.T) {
+	cfg := &RetryConfigModel{
+		MaxRetries:    types.Int64Null(),
+		RetryDelay:    types.StringValue("two seconds"),
+		MaxRetryDelay: types.StringNull(),
+		TryTimeout:    types.StringNull(),
+		StatusCodes:   types.SetNull(types.Int64Type),
+	}
+
+	_, err := cfg.GetRetryOptions(context.Background())
+	assert.NotNil(t, err)
+
+	cfg.RetryDelay = types.StringValue("-1s")
+	_, err = cfg.GetRetryOptions(context.Background())
+	assert.NotNil(t, err)
+}
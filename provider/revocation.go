@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+)
+
+type RevocationList interface {
+	// GetRevocation returns the revocation record of the ciphertext, or nil where the ciphertext is not revoked
+	GetRevocation(ctx context.Context, uuid string) (*core.CiphertextRevocation, error)
+}
+
+// RevocationRecorder the revocation list where the revocations can be recorded, i.e. the object tracker
+type RevocationRecorder interface {
+	RevocationList
+
+	// RevokeObjectId records the revocation of the ciphertext identified by id
+	RevokeObjectId(ctx context.Context, id string, reason string) error
+}
+
+// revocationRecorderOf returns the list of the configured revocation lists that records the revocations, or
+// nil where none of the lists can.
+func revocationRecorderOf(list RevocationList) RevocationRecorder {
+	switch v := list.(type) {
+	case RevocationRecorder:
+		return v
+	case MultiRevocationList:
+		for _, l := range v {
+			if rv := revocationRecorderOf(l); rv != nil {
+				return rv
+			}
+		}
+	}
+
+	return nil
+}
+
+// FileRevocationList the revocation list read from a local JSON file. The file contains an array of
+// revocation records, e.g.:
+//
+//	[
+//	  {"uuid": "...", "reason": "leaked with pipeline logs", "revoked_at": "2026-01-31T10:00:00Z"}
+//	]
+type FileRevocationList struct {
+	FilePath string
+
+	revocations map[string]core.CiphertextRevocation
+}
+
+func (f *FileRevocationList) GetRevocation(_ context.Context, uuid string) (*core.CiphertextRevocation, error) {
+	if rv, ok := f.revocations[strings.ToLower(uuid)]; ok {
+		return &rv, nil
+	}
+	return nil, nil
+}
+
+// NewFileRevocationList loads the revocation list from the file. The list is read once; the changes made to
+// the file are picked up by the next Terraform run.
+func NewFileRevocationList(filePath string) (*FileRevocationList, error) {
+	if len(filePath) == 0 {
+		return nil, errors.New("revocation file path must not be empty")
+	}
+
+	data, readErr := os.ReadFile(filePath)
+	if readErr != nil {
+		return nil, fmt.Errorf("cannot read revocation file %s: %s", filePath, readErr.Error())
+	}
+
+	var records []core.CiphertextRevocation
+	if jsonErr := json.Unmarshal(data, &records); jsonErr != nil {
+		return nil, fmt.Errorf("revocation file %s is not a JSON array of revocations: %s", filePath, jsonErr.Error())
+	}
+
+	rv := &FileRevocationList{
+		FilePath:    filePath,
+		revocations: make(map[string]core.CiphertextRevocation, len(records)),
+	}
+	for i, r := range records {
+		if len(r.Uuid) == 0 {
+			return nil, fmt.Errorf("revocation #%d in file %s does not specify the uuid", i, filePath)
+		}
+		rv.revocations[strings.ToLower(r.Uuid)] = r
+	}
+
+	return rv, nil
+}
+
+// MultiRevocationList consults several revocation lists, e.g. the local file and the object tracker. The
+// ciphertext is revoked where any list revokes it.
+type MultiRevocationList []RevocationList
+
+func (m MultiRevocationList) GetRevocation(ctx context.Context, uuid string) (*core.CiphertextRevocation, error) {
+	for _, list := range m {
+		if rv, err := list.GetRevocation(ctx, uuid); err != nil || rv != nil {
+			return rv, err
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/acceptance/fakeazure"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRevocationFile(t *testing.T, content string) string {
+	filePath := filepath.Join(t.TempDir(), "revoked.json")
+	assert.Nil(t, os.WriteFile(filePath, []byte(content), 0600))
+	return filePath
+}
+
+func Test_FileRevocationList_ReturnsRevocation(t *testing.T) {
+	filePath := writeRevocationFile(t, `[{"uuid": "ABC-123", "reason": "leaked with pipeline logs"}]`)
+
+	list, err := NewFileRevocationList(filePath)
+	assert.Nil(t, err)
+
+	rv, err := list.GetRevocation(context.Background(), "abc-123")
+	assert.Nil(t, err)
+	assert.NotNil(t, rv)
+	assert.Equal(t, "leaked with pipeline logs", rv.Reason)
+
+	rv, err = list.GetRevocation(context.Background(), "other-uuid")
+	assert.Nil(t, err)
+	assert.Nil(t, rv)
+}
+
+func Test_FileRevocationList_RejectsMalformedFile(t *testing.T) {
+	_, err := NewFileRevocationList(writeRevocationFile(t, `{"uuid": "abc"}`))
+	assert.NotNil(t, err)
+
+	_, err = NewFileRevocationList(writeRevocationFile(t, `[{"reason": "no uuid"}]`))
+	assert.NotNil(t, err)
+
+	_, err = NewFileRevocationList(filepath.Join(t.TempDir(), "missing.json"))
+	assert.NotNil(t, err)
+}
+
+func Test_ConfigureRevocationList(t *testing.T) {
+	p := AZConnectorProviderImpl{}
+
+	rv, err := p.ConfigureRevocationList(context.Background(), AZConnectorProviderImplModel{}, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, rv)
+
+	_, err = p.ConfigureRevocationList(context.Background(), AZConnectorProviderImplModel{
+		Revocation: &RevocationConfigModel{
+			FilePath:   types.StringNull(),
+			UseTracker: types.BoolValue(true),
+		},
+	}, nil)
+	assert.NotNil(t, err)
+
+	rv, err = p.ConfigureRevocationList(context.Background(), AZConnectorProviderImplModel{
+		Revocation: &RevocationConfigModel{
+			FilePath:   types.StringValue(writeRevocationFile(t, `[]`)),
+			UseTracker: types.BoolNull(),
+		},
+	}, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, rv)
+}
+
+func Test_RevokeCiphertext_RequiresTrackerRevocationList(t *testing.T) {
+	fileList, err := NewFileRevocationList(writeRevocationFile(t, `[]`))
+	assert.Nil(t, err)
+
+	factory := &AZClientsFactoryImpl{revocationList: MultiRevocationList{fileList}}
+	_, err = factory.RevokeCiphertext(context.Background(), "uuid", "leaked")
+	assert.ErrorContains(t, err, "revocation.use_tracker")
+
+	factory = &AZClientsFactoryImpl{}
+	_, err = factory.RevokeCiphertext(context.Background(), "uuid", "leaked")
+	assert.NotNil(t, err)
+}
+
+func Test_RevokeCiphertext_RecordsInTracker(t *testing.T) {
+	srv := fakeazure.NewServer()
+	defer srv.Close()
+
+	tracker := givenTrackerConnectedTo(t, srv)
+	fileList, err := NewFileRevocationList(writeRevocationFile(t, `[]`))
+	assert.Nil(t, err)
+
+	factory := &AZClientsFactoryImpl{
+		hashTacker:     tracker,
+		revocationList: MultiRevocationList{fileList, tracker},
+	}
+
+	revocation, err := factory.RevokeCiphertext(context.Background(), "uuid", "leaked")
+	assert.Nil(t, err)
+	assert.Equal(t, "uuid", revocation.Uuid)
+	assert.Equal(t, "leaked", revocation.Reason)
+	assert.NotEmpty(t, revocation.RevokedAt)
+
+	revocation, err = factory.GetCiphertextRevocation(context.Background(), "uuid")
+	assert.Nil(t, err)
+	assert.NotNil(t, revocation)
+}
@@ -0,0 +1,104 @@
+package apim
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+)
+
+// DestinationApiManagementIdentityModel the identity of the API management service hosting the object
+type DestinationApiManagementIdentityModel struct {
+	AzSubscriptionId string `tfsdk:"az_subscription_id"`
+	ResourceGroup    string `tfsdk:"resource_group"`
+	ServiceName      string `tfsdk:"api_management_name"`
+}
+
+// NamedValueIdentityModel the identity of the API management named value managed by the resource
+type NamedValueIdentityModel struct {
+	DestinationApiManagementIdentityModel
+	Name           string `tfsdk:"name"`
+	CiphertextUuid string `tfsdk:"ciphertext_uuid"`
+}
+
+// SubscriptionIdentityModel the identity of the API management subscription managed by the resource
+type SubscriptionIdentityModel struct {
+	DestinationApiManagementIdentityModel
+	SubscriptionId string `tfsdk:"apim_subscription_id"`
+	CiphertextUuid string `tfsdk:"ciphertext_uuid"`
+}
+
+func apimIdentitySchema(objectAttribute string, objectDescription string) identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"az_subscription_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Azure subscription where the API management service is deployed",
+			},
+			"resource_group": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Resource group of the API management service",
+			},
+			"api_management_name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Name of the API management service",
+			},
+			objectAttribute: identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       objectDescription,
+			},
+			resources.CiphertextUuidIdentityAttribute: identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description:       "Uuid of the ciphertext the object was created from",
+			},
+		},
+	}
+}
+
+func NamedValueIdentityModelSchema() identityschema.Schema {
+	return apimIdentitySchema("name", "Identifier of the named value")
+}
+
+func SubscriptionIdentityModelSchema() identityschema.Schema {
+	return apimIdentitySchema("apim_subscription_id", "Identifier of the API management subscription")
+}
+
+var namedValueIdRegexp = regexp.MustCompile("^/subscriptions/(.+)/resourceGroups/(.+)/providers/Microsoft.ApiManagement/service/(.+)/namedValues/(.+)$")
+
+// NamedValueIdentityOf the identity of the named value given the Azure resource identifier of the named value
+func NamedValueIdentityOf(id string, ciphertextUuid string) (NamedValueIdentityModel, error) {
+	matcher := namedValueIdRegexp.FindStringSubmatch(id)
+	if matcher == nil {
+		return NamedValueIdentityModel{}, fmt.Errorf("named value id (%s) does not match expected resource regular expression", id)
+	}
+
+	return NamedValueIdentityModel{
+		DestinationApiManagementIdentityModel: DestinationApiManagementIdentityModel{
+			AzSubscriptionId: matcher[1],
+			ResourceGroup:    matcher[2],
+			ServiceName:      matcher[3],
+		},
+		Name:           matcher[4],
+		CiphertextUuid: ciphertextUuid,
+	}, nil
+}
+
+// SubscriptionIdentityOf the identity of the API management subscription given the Azure resource identifier of
+// the subscription
+func SubscriptionIdentityOf(id string, ciphertextUuid string) (SubscriptionIdentityModel, error) {
+	matcher := subscriptionIdRegexp.FindStringSubmatch(id)
+	if matcher == nil {
+		return SubscriptionIdentityModel{}, fmt.Errorf("subscription id (%s) does not match expected resource regular expression", id)
+	}
+
+	return SubscriptionIdentityModel{
+		DestinationApiManagementIdentityModel: DestinationApiManagementIdentityModel{
+			AzSubscriptionId: matcher[1],
+			ResourceGroup:    matcher[2],
+			ServiceName:      matcher[3],
+		},
+		SubscriptionId: matcher[4],
+		CiphertextUuid: ciphertextUuid,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package apim
+
+import (
+	"testing"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NamedValueIdentityOf(t *testing.T) {
+	identity, err := NamedValueIdentityOf("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/namedValues/nv", "ciphertext-uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, "sub", identity.AzSubscriptionId)
+	assert.Equal(t, "rg", identity.ResourceGroup)
+	assert.Equal(t, "svc", identity.ServiceName)
+	assert.Equal(t, "nv", identity.Name)
+	assert.Equal(t, "ciphertext-uuid", identity.CiphertextUuid)
+}
+
+func Test_NamedValueIdentityOf_IfIdIsMalformed(t *testing.T) {
+	_, err := NamedValueIdentityOf("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/subscriptions/sid", "ciphertext-uuid")
+	assert.NotNil(t, err)
+}
+
+func Test_SubscriptionIdentityOf(t *testing.T) {
+	identity, err := SubscriptionIdentityOf("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/subscriptions/sid", "ciphertext-uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, "sub", identity.AzSubscriptionId)
+	assert.Equal(t, "rg", identity.ResourceGroup)
+	assert.Equal(t, "svc", identity.ServiceName)
+	assert.Equal(t, "sid", identity.SubscriptionId)
+	assert.Equal(t, "ciphertext-uuid", identity.CiphertextUuid)
+}
+
+func Test_SubscriptionIdentityOf_IfIdIsMalformed(t *testing.T) {
+	_, err := SubscriptionIdentityOf("", "ciphertext-uuid")
+	assert.NotNil(t, err)
+}
+
+func Test_IdentitySchemasRecordCiphertextUuid(t *testing.T) {
+	assert.Contains(t, NamedValueIdentityModelSchema().Attributes, "name")
+	assert.Contains(t, NamedValueIdentityModelSchema().Attributes, resources.CiphertextUuidIdentityAttribute)
+	assert.Contains(t, SubscriptionIdentityModelSchema().Attributes, "apim_subscription_id")
+	assert.Contains(t, SubscriptionIdentityModelSchema().Attributes, resources.CiphertextUuidIdentityAttribute)
+}
+
+func Test_NV_IdentityOf(t *testing.T) {
+	mdl := NamedValueModel{}
+	mdl.Id = types.StringValue("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/namedValues/nv")
+
+	s := NamedValueSpecializer{}
+	identity, err := s.IdentityOf(&mdl, "ciphertext-uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, "nv", identity.Name)
+}
+
+func Test_Sub_IdentityOf(t *testing.T) {
+	mdl := SubscriptionModel{}
+	mdl.Id = types.StringValue("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/subscriptions/sid")
+
+	s := SubscriptionSpecializer{}
+	identity, err := s.IdentityOf(&mdl, "ciphertext-uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, "sid", identity.SubscriptionId)
+}
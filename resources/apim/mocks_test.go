@@ -7,7 +7,14 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
 )
 
 func MockedAzObjectNotFoundError() error {
@@ -331,6 +338,28 @@ func (m *AZClientsFactoryMock) GivenIsObjectTrackingEnabled(enableOpt bool) {
 	m.On("IsObjectTrackingEnabled").Return(enableOpt)
 }
 
+func (m *AZClientsFactoryMock) GetExpiryWarningWindow() time.Duration {
+	rv := m.Mock.Called()
+	return rv.Get(0).(time.Duration)
+}
+
+func (m *AZClientsFactoryMock) GivenExpiryWarningWindow(d time.Duration) {
+	m.On("GetExpiryWarningWindow").Return(d).Maybe()
+}
+
+func (m *AZClientsFactoryMock) GetCiphertextRevocation(ctx context.Context, uuid string) (*core.CiphertextRevocation, error) {
+	rv := m.Mock.Called(ctx, uuid)
+	return rv.Get(0).(*core.CiphertextRevocation), rv.Error(1)
+}
+
+func (m *AZClientsFactoryMock) GivenCiphertextIsNotRevoked() {
+	m.On("GetCiphertextRevocation", mock.Anything, mock.Anything).Return((*core.CiphertextRevocation)(nil), nil).Maybe()
+}
+
+func (m *AZClientsFactoryMock) GivenCiphertextIsRevoked(reason string) {
+	m.On("GetCiphertextRevocation", mock.Anything, mock.Anything).Return(&core.CiphertextRevocation{Reason: reason}, nil)
+}
+
 func (m *AZClientsFactoryMock) GetAzSubscription(v string) (string, error) {
 	rv := m.Mock.Called(v)
 	return rv.Get(0).(string), rv.Error(1)
@@ -345,3 +374,29 @@ func (m *AZClientsFactoryMock) GivenGetAzSubscription(inSub, rvSub string) {
 	m.On("GetAzSubscription", inSub).
 		Return(rvSub, nil)
 }
+
+// upgradeStateFromJson upgrades the state of the resource as Terraform would have stored it with the prior version
+// of the resource schema.
+func upgradeStateFromJson(t *testing.T, r resource.Resource, priorVersion int64, stateJson string) (tfsdk.State, diag.Diagnostics) {
+	ctx := context.Background()
+
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	upgrader, ok := r.(resource.ResourceWithUpgradeState).UpgradeState(ctx)[priorVersion]
+	assert.True(t, ok, "resource does not upgrade state of version %d", priorVersion)
+
+	// The state is read strictly, so that an attribute of the prior state missing in the prior schema fails the test
+	priorValue, err := tftypes.ValueFromJSON([]byte(stateJson), upgrader.PriorSchema.Type().TerraformType(ctx))
+	assert.Nil(t, err)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: priorValue},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)},
+	}
+
+	upgrader.StateUpgrader(ctx, req, &resp)
+	return resp.State, resp.Diagnostics
+}
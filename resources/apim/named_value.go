@@ -1,11 +1,14 @@
 package apim
 
 import (
+	"bytes"
 	"context"
 	"crypto/rsa"
 	_ "embed"
+	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
@@ -28,9 +31,11 @@ import (
 
 type NamedValueModel struct {
 	resources.ConfidentialMaterialModel
+	resources.ResourceTimeoutsModel
 
 	DestinationNamedValue DestinationNamedValueModel `tfsdk:"destination_named_value"`
 	Tags                  types.Set                  `tfsdk:"tags"`
+	ProvenanceTags        types.Map                  `tfsdk:"provenance_tags"`
 	DisplayName           types.String               `tfsdk:"display_name"`
 	Secret                types.Bool                 `tfsdk:"secret"`
 }
@@ -62,6 +67,35 @@ func GetDestinationNamedValueLabel(azSubscriptionId string, resourceGroupName st
 	return mdl.GetLabel()
 }
 
+func (mdl *NamedValueModel) IsProvenanceTagsUnknown() bool {
+	return mdl.ProvenanceTags.IsUnknown()
+}
+
+func (mdl *NamedValueModel) SetProvenanceTags(tags map[string]string) {
+	tfTags := map[string]attr.Value{}
+	for k, v := range tags {
+		tfTags[k] = types.StringValue(v)
+	}
+
+	mdl.ProvenanceTags, _ = types.MapValue(types.StringType, tfTags)
+}
+
+// AzTagsAsPtr the tags to place on the named value: the tags of the configuration and the provenance tags
+func (mdl *NamedValueModel) AzTagsAsPtr(ctx context.Context) []*string {
+	rv := core.TerraformStringSetAsPtr(ctx, mdl.Tags)
+
+	if !mdl.ProvenanceTags.IsNull() && !mdl.ProvenanceTags.IsUnknown() {
+		provenanceTags := map[string]string{}
+		mdl.ProvenanceTags.ElementsAs(ctx, &provenanceTags, false)
+
+		for _, tag := range core.ProvenanceTagsAsList(provenanceTags) {
+			rv = append(rv, to.Ptr(tag))
+		}
+	}
+
+	return rv
+}
+
 func (mdl *NamedValueModel) ToNamedValueContract(ctx context.Context) armapimanagement.NamedValueCreateContract {
 	rv := armapimanagement.NamedValueCreateContract{
 		Name: mdl.DestinationNamedValue.Name.ValueStringPointer(),
@@ -69,7 +103,7 @@ func (mdl *NamedValueModel) ToNamedValueContract(ctx context.Context) armapimana
 			DisplayName: mdl.DisplayName.ValueStringPointer(),
 			KeyVault:    nil,
 			Secret:      mdl.Secret.ValueBoolPointer(),
-			Tags:        core.TerraformStringSetAsPtr(ctx, mdl.Tags),
+			Tags:        mdl.AzTagsAsPtr(ctx),
 		},
 	}
 	rv.Name = mdl.DestinationNamedValue.Name.ValueStringPointer()
@@ -85,7 +119,7 @@ func (mdl *NamedValueModel) ToUpdateProperties(ctx context.Context) armapimanage
 			DisplayName: mdl.DisplayName.ValueStringPointer(),
 			KeyVault:    nil,
 			Secret:      mdl.Secret.ValueBoolPointer(),
-			Tags:        core.TerraformStringSetAsPtr(ctx, mdl.Tags),
+			Tags:        mdl.AzTagsAsPtr(ctx),
 		},
 	}
 
@@ -104,17 +138,26 @@ func (mdl *NamedValueModel) Accept(v armapimanagement.NamedValueContract) {
 	// the nil value will be set only if the tag value is not currently known,
 	// or the state contains data. This is because an empty tag set is considered
 	// to be the same as an nil tag set.
-	if v.Properties.Tags != nil && len(v.Properties.Tags) > 0 {
-		tagSet, _ := core.ConvertToTerraformSet[*string](
-			func(s *string) attr.Value { return types.StringValue(*s) },
+	userTags, provenanceTags := core.ProvenanceTagsOfList(core.StringPtrsValues(v.Properties.Tags))
+
+	if len(userTags) > 0 {
+		tagSet, _ := core.ConvertToTerraformSet[string](
+			func(s string) attr.Value { return types.StringValue(s) },
 			types.StringType,
-			v.Properties.Tags...,
+			userTags...,
 		)
 		mdl.Tags = tagSet
 	} else if mdl.Tags.IsUnknown() || len(v.Properties.Tags) > 0 {
 		mdl.Tags = types.SetNull(types.StringType)
 	}
 
+	// Provenance tags are kept apart from the tags of the configuration so that these don't appear as drift
+	if len(provenanceTags) > 0 {
+		mdl.SetProvenanceTags(provenanceTags)
+	} else {
+		mdl.ProvenanceTags = types.MapNull(types.StringType)
+	}
+
 }
 
 type NamedValueSpecializer struct {
@@ -155,10 +198,67 @@ func (n *NamedValueSpecializer) CheckPlacement(ctx context.Context, pc []core.Pr
 	return rv
 }
 
+func (n *NamedValueSpecializer) GetDestinationLabel(tfModel *NamedValueModel) string {
+	return tfModel.DestinationNamedValue.GetLabel()
+}
+
+// VerifyMovedObjectDestination verifies that the named value moved into the resource is the named value of the
+// API management service that the configuration gives.
+func (n *NamedValueSpecializer) VerifyMovedObjectDestination(tfModel *NamedValueModel) error {
+	moved, err := NamedValueIdentityOf(tfModel.Id.ValueString(), "")
+	if err != nil {
+		return err
+	}
+
+	dest := tfModel.DestinationNamedValue
+	if !strings.EqualFold(moved.AzSubscriptionId, dest.AzSubscriptionId.ValueString()) ||
+		!strings.EqualFold(moved.ResourceGroup, dest.ResourceGroup.ValueString()) ||
+		!strings.EqualFold(moved.ServiceName, dest.ServiceName.ValueString()) ||
+		!strings.EqualFold(moved.Name, dest.Name.ValueString()) {
+		return fmt.Errorf("named value %s was moved, whereas the configuration places the named value at %s", tfModel.Id.ValueString(), dest.GetLabel())
+	}
+
+	return nil
+}
+
+func (n *NamedValueSpecializer) IdentityOf(tfModel *NamedValueModel, ciphertextUuid string) (NamedValueIdentityModel, error) {
+	return NamedValueIdentityOf(tfModel.Id.ValueString(), ciphertextUuid)
+}
+
 func (n *NamedValueSpecializer) GetJsonDataImporter() core.ObjectJsonImportSupport[core.ConfidentialStringData] {
 	return core.NewVersionedStringConfidentialDataHelper(NamedValueObjectType)
 }
 
+func (n *NamedValueSpecializer) GetDestinationProvenanceTags(ctx context.Context, data *NamedValueModel) (map[string]string, error) {
+	subscriptionId := data.DestinationNamedValue.AzSubscriptionId.ValueString()
+	namedValueClient, err := n.factory.GetApimNamedValueClient(subscriptionId)
+	if err != nil {
+		return nil, err
+	} else if namedValueClient == nil {
+		return nil, errors.New("API management client returned is nil")
+	}
+
+	resp, err := namedValueClient.Get(ctx,
+		data.DestinationNamedValue.ResourceGroup.ValueString(),
+		data.DestinationNamedValue.ServiceName.ValueString(),
+		data.DestinationNamedValue.Name.ValueString(),
+		nil,
+	)
+	if err != nil {
+		if core.IsResourceNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if resp.Properties == nil {
+		return nil, nil
+	}
+
+	_, provenanceTags := core.ProvenanceTagsOfList(core.StringPtrsValues(resp.Properties.Tags))
+	return provenanceTags, nil
+}
+
 func (n *NamedValueSpecializer) DoCreate(ctx context.Context, data *NamedValueModel, plainData core.ConfidentialStringData) (armapimanagement.NamedValueContract, diag.Diagnostics) {
 	rv := diag.Diagnostics{}
 
@@ -232,11 +332,32 @@ func (n *NamedValueSpecializer) DoDelete(ctx context.Context, data *NamedValueMo
 }
 
 func (n *NamedValueSpecializer) DoRead(ctx context.Context, data *NamedValueModel, plainData core.ConfidentialStringData) (armapimanagement.NamedValueContract, resources.ResourceExistenceCheck, diag.Diagnostics) {
+	namedValue, value, check, rv := n.DoReadConfidentialValue(ctx, data)
+	if check != resources.ResourceExists {
+		return namedValue, check, rv
+	}
+
+	if bytes.Equal(n.ConfidentialValueOf(plainData), value) {
+		return namedValue, resources.ResourceExists, rv
+	}
+
+	tflog.Warn(ctx, "Detected a drift in the confidential material")
+
+	return namedValue, resources.ResourceConfidentialDataDrift, rv
+}
+
+// ConfidentialValueOf returns the value of the named value
+func (n *NamedValueSpecializer) ConfidentialValueOf(plainData core.ConfidentialStringData) []byte {
+	return []byte(plainData.GetStingData())
+}
+
+// DoReadConfidentialValue reads the named value together with its value
+func (n *NamedValueSpecializer) DoReadConfidentialValue(ctx context.Context, data *NamedValueModel) (armapimanagement.NamedValueContract, []byte, resources.ResourceExistenceCheck, diag.Diagnostics) {
 	rv := diag.Diagnostics{}
 
 	// The key version was never created; nothing needs to be read here.
 	if data.Id.IsUnknown() {
-		return armapimanagement.NamedValueContract{}, resources.ResourceNotYetCreated, rv
+		return armapimanagement.NamedValueContract{}, nil, resources.ResourceNotYetCreated, rv
 
 	}
 
@@ -244,10 +365,10 @@ func (n *NamedValueSpecializer) DoRead(ctx context.Context, data *NamedValueMode
 	namedValueClient, err := n.factory.GetApimNamedValueClient(subscriptionId)
 	if err != nil {
 		rv.AddError("Cannot acquire API management named value client", fmt.Sprintf("Cannot acquire API management client to this subscription %s: %s", subscriptionId, err.Error()))
-		return armapimanagement.NamedValueContract{}, resources.ResourceCheckError, rv
+		return armapimanagement.NamedValueContract{}, nil, resources.ResourceCheckError, rv
 	} else if namedValueClient == nil {
 		rv.AddError("Cannot acquire API management named value client", "API management client returned is nil")
-		return armapimanagement.NamedValueContract{}, resources.ResourceCheckError, rv
+		return armapimanagement.NamedValueContract{}, nil, resources.ResourceCheckError, rv
 	}
 
 	resp, err := namedValueClient.Get(ctx,
@@ -270,14 +391,14 @@ func (n *NamedValueSpecializer) DoRead(ctx context.Context, data *NamedValueMode
 				)
 			}
 
-			return armapimanagement.NamedValueContract{}, resources.ResourceNotFound, rv
+			return armapimanagement.NamedValueContract{}, nil, resources.ResourceNotFound, rv
 		} else {
 			rv.AddError("Cannot read name value", fmt.Sprintf("Cannot read mamed value %s in API Management sertvice %s in group %s: %s",
 				data.DestinationNamedValue.Name.ValueString(),
 				data.DestinationNamedValue.ServiceName.ValueString(),
 				data.DestinationNamedValue.ResourceGroup.ValueString(),
 				err.Error()))
-			return armapimanagement.NamedValueContract{}, resources.ResourceCheckError, rv
+			return armapimanagement.NamedValueContract{}, nil, resources.ResourceCheckError, rv
 		}
 	}
 
@@ -291,18 +412,14 @@ func (n *NamedValueSpecializer) DoRead(ctx context.Context, data *NamedValueMode
 
 	if valueErr != nil {
 		rv.AddError("Cannot read named value content", valueErr.Error())
-		return armapimanagement.NamedValueContract{}, resources.ResourceCheckError, rv
+		return armapimanagement.NamedValueContract{}, nil, resources.ResourceCheckError, rv
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Detecting drift: want=%s, have=%s", plainData.GetStingData(), *value.Value))
-
-	if value.Value != nil && plainData.GetStingData() == *value.Value {
-		return resp.NamedValueContract, resources.ResourceExists, nil
+	if value.Value == nil {
+		return resp.NamedValueContract, nil, resources.ResourceExists, nil
 	}
 
-	tflog.Warn(ctx, "Detected a drift in the confidential material")
-
-	return resp.NamedValueContract, resources.ResourceConfidentialDataDrift, nil
+	return resp.NamedValueContract, []byte(*value.Value), resources.ResourceExists, nil
 }
 
 func (n *NamedValueSpecializer) SetDriftToConfidentialData(_ context.Context, planData *NamedValueModel) {
@@ -380,6 +497,7 @@ func NewNamedValueResource() resource.Resource {
 			Description: "Tags to place on this named value",
 			ElementType: types.StringType,
 		},
+		"provenance_tags": resources.ProvenanceTagsSchema(),
 		"destination_named_value": schema.SingleNestedAttribute{
 			Required:            true,
 			MarkdownDescription: "Destination named value",
@@ -421,6 +539,7 @@ func NewNamedValueResource() resource.Resource {
 	}
 
 	resourceSchema := schema.Schema{
+		Version:             1,
 		MarkdownDescription: namedValueResourceMarkdownDescription,
 
 		Attributes: resources.WrappedConfidentialMaterialModelSchema(specificAttrs, false),
@@ -428,11 +547,19 @@ func NewNamedValueResource() resource.Resource {
 
 	namedValueSpecializer := &NamedValueSpecializer{}
 
-	return &resources.ConfidentialGenericResource[NamedValueModel, int, core.ConfidentialStringData, armapimanagement.NamedValueContract]{
+	return &resources.ConfidentialGenericResource[NamedValueModel, NamedValueIdentityModel, core.ConfidentialStringData, armapimanagement.NamedValueContract]{
 		Specializer:    namedValueSpecializer,
 		MutableRU:      namedValueSpecializer,
 		ResourceName:   "apim_named_value",
 		ResourceSchema: resourceSchema,
+
+		ResourceIdentitySchema: NamedValueIdentityModelSchema(),
+		StateUpgraders: map[int64]resource.StateUpgrader{
+			0: resources.NewCarryOverStateUpgrader(namedValueSchemaV0()),
+		},
+		StateMovers: []resource.StateMover{
+			resources.NewStateMover(resources.AzureRMProviderAddress, AzureRMNamedValueTypeName, MoveAzureRMNamedValue),
+		},
 	}
 }
 
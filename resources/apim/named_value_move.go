@@ -0,0 +1,61 @@
+package apim
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// AzureRMNamedValueTypeName the type of the azurerm resource managing the API management named value
+const AzureRMNamedValueTypeName = "azurerm_api_management_named_value"
+
+// AzureRMNamedValueState the attributes of the azurerm_api_management_named_value state moved into the named
+// value resource. The value of the named value is deliberately not read.
+type AzureRMNamedValueState struct {
+	Id                string        `json:"id"`
+	DisplayName       *string       `json:"display_name"`
+	Secret            *bool         `json:"secret"`
+	Tags              []string      `json:"tags"`
+	ValueFromKeyVault []interface{} `json:"value_from_key_vault"`
+}
+
+// MoveAzureRMNamedValue moves the state of azurerm_api_management_named_value into the named value model. The named
+// values reading their value from the Key Vault have no confidential value to adopt and cannot be moved.
+func MoveAzureRMNamedValue(ctx context.Context, src AzureRMNamedValueState, mdl *NamedValueModel) diag.Diagnostics {
+	rv := diag.Diagnostics{}
+
+	if len(src.ValueFromKeyVault) > 0 {
+		rv.AddError(
+			"Named value cannot be moved",
+			"The named value reads its value from the Key Vault; the value is not confidential to this provider. Keep managing this named value with azurerm.",
+		)
+		return rv
+	}
+
+	identity, err := NamedValueIdentityOf(src.Id, "")
+	if err != nil {
+		rv.AddError("Named value identifier does not conform to the expected format", err.Error())
+		return rv
+	}
+
+	mdl.Id = types.StringValue(src.Id)
+	mdl.DestinationNamedValue = DestinationNamedValueModel{
+		DestinationApiManagement: DestinationApiManagement{
+			AzSubscriptionId: types.StringValue(identity.AzSubscriptionId),
+			ResourceGroup:    types.StringValue(identity.ResourceGroup),
+			ServiceName:      types.StringValue(identity.ServiceName),
+		},
+		Name: types.StringValue(identity.Name),
+	}
+	mdl.DisplayName = types.StringPointerValue(src.DisplayName)
+	mdl.Secret = types.BoolPointerValue(src.Secret)
+
+	if len(src.Tags) > 0 {
+		tags, tagsDg := types.SetValueFrom(ctx, types.StringType, src.Tags)
+		rv.Append(tagsDg...)
+		mdl.Tags = tags
+	}
+
+	return rv
+}
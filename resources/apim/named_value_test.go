@@ -8,10 +8,12 @@ import (
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func Test_GetDestinationNamedValueLabel(t *testing.T) {
@@ -19,6 +21,53 @@ func Test_GetDestinationNamedValueLabel(t *testing.T) {
 	assert.Equal(t, "az-c-label:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/apim/namedValues/nv", v)
 }
 
+func Test_NVM_ProvenanceTags(t *testing.T) {
+	mdl := NamedValueModel{
+		Tags:           types.SetValueMust(types.StringType, []attr.Value{types.StringValue("env")}),
+		ProvenanceTags: types.MapUnknown(types.StringType),
+	}
+	mdl.SetProvenanceTags(map[string]string{core.ProvenanceUuidTag: "unit-test-uuid"})
+
+	contract := mdl.ToNamedValueContract(context.Background())
+	assert.Equal(t, 2, len(contract.Properties.Tags))
+
+	accepted := NamedValueModel{}
+	accepted.Accept(armapimanagement.NamedValueContract{
+		Properties: &armapimanagement.NamedValueContractProperties{
+			Tags: contract.Properties.Tags,
+		},
+	})
+
+	assert.Equal(t, 1, len(accepted.Tags.Elements()))
+	assert.Equal(t, types.StringValue("unit-test-uuid"), accepted.ProvenanceTags.Elements()[core.ProvenanceUuidTag])
+}
+
+func Test_NVM_ProvenanceTagsSurviveApimTagList(t *testing.T) {
+	provenanceTags := core.NewProvenanceTags("3d6d2a2e-6b7f-4b8e-9f0a-6a1f2b3c4d5e", "ciphertext", nil, time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC))
+
+	mdl := NamedValueModel{
+		Tags:           types.SetNull(types.StringType),
+		ProvenanceTags: types.MapUnknown(types.StringType),
+	}
+	mdl.SetProvenanceTags(provenanceTags)
+
+	contract := mdl.ToNamedValueContract(context.Background())
+	for _, tag := range contract.Properties.Tags {
+		assert.LessOrEqual(t, len(*tag), 80)
+	}
+
+	accepted := NamedValueModel{}
+	accepted.Accept(armapimanagement.NamedValueContract{
+		Properties: &armapimanagement.NamedValueContractProperties{
+			Tags: contract.Properties.Tags,
+		},
+	})
+
+	assert.True(t, accepted.ProvenanceTags.Equal(mdl.ProvenanceTags))
+	assert.Equal(t, types.StringValue(provenanceTags[core.ProvenanceSha256Tag]), accepted.ProvenanceTags.Elements()[core.ProvenanceSha256Tag])
+	assert.Equal(t, types.StringValue("2026-01-31T10:00:00Z"), accepted.ProvenanceTags.Elements()[core.ProvenanceTimestampTag])
+}
+
 func Test_NV_DoRead_WhenNotCreated(t *testing.T) {
 	mdl := NamedValueModel{}
 	mdl.Id = types.StringUnknown()
@@ -703,3 +752,94 @@ func Test_CreateNamedValueEncryptedMessage_EncryptedMessage(t *testing.T) {
 		hdr.PlacementConstraints[0],
 	)
 }
+
+func Test_NV_UpgradeStateFromVersion0(t *testing.T) {
+	// The attributes of the named value resource as the provider wrote these before the schema declared its version
+	state, dg := upgradeStateFromJson(t, NewNamedValueResource(), 0, `{
+		"content": "unit-test-ciphertext",
+		"destination_named_value": {
+			"api_management_name": "svc",
+			"az_subscription_id": "sub",
+			"name": "nv",
+			"resource_group": "rg"
+		},
+		"display_name": "nv",
+		"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/namedValues/nv",
+		"secret": true,
+		"tags": ["unit-test"],
+		"wrapping_key": {
+			"algorithm": "RSA-OAEP-256",
+			"name": "wrapping-key",
+			"vault_name": "provider-vault",
+			"version": null
+		}
+	}`)
+	assert.False(t, dg.HasError())
+
+	mdl := NamedValueModel{}
+	assert.False(t, state.Get(context.Background(), &mdl).HasError())
+	assert.Equal(t, "unit-test-ciphertext", mdl.EncryptedSecret.ValueString())
+	assert.Equal(t, "nv", mdl.DestinationNamedValue.Name.ValueString())
+	assert.Equal(t, "svc", mdl.DestinationNamedValue.ServiceName.ValueString())
+	assert.True(t, mdl.Secret.ValueBool())
+	assert.Equal(t, 1, len(mdl.Tags.Elements()))
+	assert.Equal(t, "provider-vault", mdl.WrappingKeyCoordinate.VaultName.ValueString())
+	assert.Equal(t, "RSA-OAEP-256", mdl.WrappingKeyCoordinate.Algorithm.ValueString())
+	assert.True(t, mdl.ProvenanceTags.IsNull())
+	assert.True(t, mdl.Timeouts.IsNull())
+}
+
+func Test_NV_MoveAzureRMNamedValue(t *testing.T) {
+	mdl := NamedValueModel{}
+	dg := MoveAzureRMNamedValue(context.Background(), AzureRMNamedValueState{
+		Id:          "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/namedValues/nv",
+		DisplayName: to.Ptr("nv-display"),
+		Secret:      to.Ptr(true),
+		Tags:        []string{"env"},
+	}, &mdl)
+
+	assert.False(t, dg.HasError())
+	assert.Equal(t, "sub", mdl.DestinationNamedValue.AzSubscriptionId.ValueString())
+	assert.Equal(t, "rg", mdl.DestinationNamedValue.ResourceGroup.ValueString())
+	assert.Equal(t, "svc", mdl.DestinationNamedValue.ServiceName.ValueString())
+	assert.Equal(t, "nv", mdl.DestinationNamedValue.Name.ValueString())
+	assert.Equal(t, "nv-display", mdl.DisplayName.ValueString())
+	assert.True(t, mdl.Secret.ValueBool())
+	assert.Equal(t, 1, len(mdl.Tags.Elements()))
+}
+
+func Test_NV_MoveAzureRMNamedValue_FromKeyVault(t *testing.T) {
+	mdl := NamedValueModel{}
+	dg := MoveAzureRMNamedValue(context.Background(), AzureRMNamedValueState{
+		Id:                "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/namedValues/nv",
+		ValueFromKeyVault: []interface{}{map[string]interface{}{"secret_id": "https://vault.vault.azure.net/secrets/s"}},
+	}, &mdl)
+
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Named value cannot be moved", dg[0].Summary())
+}
+
+func Test_NV_MoveAzureRMNamedValue_MalformedId(t *testing.T) {
+	mdl := NamedValueModel{}
+	dg := MoveAzureRMNamedValue(context.Background(), AzureRMNamedValueState{Id: "nv"}, &mdl)
+
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Named value identifier does not conform to the expected format", dg[0].Summary())
+}
+
+func Test_NV_VerifyMovedObjectDestination(t *testing.T) {
+	mdl := NamedValueModel{}
+	dg := MoveAzureRMNamedValue(context.Background(), AzureRMNamedValueState{
+		Id: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/namedValues/nv",
+	}, &mdl)
+	assert.False(t, dg.HasError())
+
+	n := NamedValueSpecializer{}
+	assert.Nil(t, n.VerifyMovedObjectDestination(&mdl))
+
+	// The configuration places the named value in another API management service
+	mdl.DestinationNamedValue.ServiceName = types.StringValue("other-svc")
+	err := n.VerifyMovedObjectDestination(&mdl)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "service/other-svc/namedValues/nv")
+}
@@ -0,0 +1,53 @@
+package apim
+
+import (
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The schemas of the API Management resources as these were before the schemas declared their version. These
+// are frozen: the state written by the earlier releases of the provider is read with these schemas before it is
+// upgraded to the current version.
+
+func namedValueSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: resources.WrappedConfidentialMaterialModelSchemaV0(map[string]schema.Attribute{
+			"display_name": schema.StringAttribute{Optional: true, Computed: true},
+			"secret":       schema.BoolAttribute{Optional: true, Computed: true},
+			"tags":         schema.SetAttribute{Optional: true, ElementType: types.StringType},
+			"destination_named_value": schema.SingleNestedAttribute{
+				Required: true,
+				Attributes: map[string]schema.Attribute{
+					"az_subscription_id":  schema.StringAttribute{Required: true},
+					"resource_group":      schema.StringAttribute{Required: true},
+					"api_management_name": schema.StringAttribute{Required: true},
+					"name":                schema.StringAttribute{Required: true},
+				},
+			},
+		}),
+	}
+}
+
+func subscriptionSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: resources.WrappedConfidentialMaterialModelSchemaV0(map[string]schema.Attribute{
+			"allow_tracing":   schema.BoolAttribute{Optional: true, Computed: true},
+			"display_name":    schema.StringAttribute{Optional: true, Computed: true},
+			"state":           schema.StringAttribute{Optional: true, Computed: true},
+			"subscription_id": schema.StringAttribute{Computed: true},
+			"destination_subscription": schema.SingleNestedAttribute{
+				Required: true,
+				Attributes: map[string]schema.Attribute{
+					"az_subscription_id":   schema.StringAttribute{Optional: true},
+					"resource_group":       schema.StringAttribute{Required: true},
+					"api_management_name":  schema.StringAttribute{Required: true},
+					"apim_subscription_id": schema.StringAttribute{Optional: true},
+					"api_id":               schema.StringAttribute{Optional: true},
+					"product_id":           schema.StringAttribute{Optional: true},
+					"user_id":              schema.StringAttribute{Optional: true},
+				},
+			},
+		}),
+	}
+}
@@ -1,9 +1,11 @@
 package apim
 
 import (
+	"bytes"
 	"context"
 	"crypto/rsa"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"regexp"
 
@@ -123,6 +125,7 @@ func NewConfidentialSubscriptionHelper(objectType string) *ConfidentialSubscript
 
 type SubscriptionModel struct {
 	resources.ConfidentialMaterialModel
+	resources.ResourceTimeoutsModel
 
 	DestinationSubscription DestinationSubscriptionCoordinateModel `tfsdk:"destination_subscription"`
 	State                   types.String                           `tfsdk:"state"`
@@ -226,6 +229,14 @@ func (s *SubscriptionSpecializer) CheckPlacement(ctx context.Context, pc []core.
 	return rv
 }
 
+func (s *SubscriptionSpecializer) GetDestinationLabel(tfModel *SubscriptionModel) string {
+	return tfModel.DestinationSubscription.GetLabel()
+}
+
+func (s *SubscriptionSpecializer) IdentityOf(tfModel *SubscriptionModel, ciphertextUuid string) (SubscriptionIdentityModel, error) {
+	return SubscriptionIdentityOf(tfModel.Id.ValueString(), ciphertextUuid)
+}
+
 func (s *SubscriptionSpecializer) GetJsonDataImporter() core.ObjectJsonImportSupport[ConfidentialSubscriptionData] {
 	return NewConfidentialSubscriptionHelper(SubscriptionObjectType)
 }
@@ -233,9 +244,39 @@ func (s *SubscriptionSpecializer) GetJsonDataImporter() core.ObjectJsonImportSup
 var idExp = regexp.MustCompile("/subscriptions/(.*)/resourceGroups/(.*)/providers/Microsoft.ApiManagement/service/(.*)/subscriptions/(.*)")
 
 func (s *SubscriptionSpecializer) DoRead(ctx context.Context, planData *SubscriptionModel, plainData ConfidentialSubscriptionData) (armapimanagement.SubscriptionContract, resources.ResourceExistenceCheck, diag.Diagnostics) {
+	subscriptionState, keys, check, rv := s.DoReadConfidentialValue(ctx, planData)
+	if check != resources.ResourceExists {
+		return subscriptionState, check, rv
+	}
+
+	// Catch and detect the drift.
+	if !bytes.Equal(s.ConfidentialValueOf(plainData), keys) {
+		rv.AddWarning("Subscription keys have drifted from the state declared in ciphertext",
+			fmt.Sprintf("API management subscription %s does not match the state specified in ciphertext",
+				planData.Id.ValueString()),
+		)
+
+		return subscriptionState, resources.ResourceConfidentialDataDrift, rv
+	}
+
+	return subscriptionState, resources.ResourceExists, rv
+}
+
+func subscriptionKeysValue(primaryKey, secondaryKey string) []byte {
+	rv, _ := json.Marshal([]string{primaryKey, secondaryKey})
+	return rv
+}
+
+// ConfidentialValueOf returns the primary and the secondary keys of the subscription
+func (s *SubscriptionSpecializer) ConfidentialValueOf(plainData ConfidentialSubscriptionData) []byte {
+	return subscriptionKeysValue(plainData.GetPrimaryKey(), plainData.GetSecondaryKey())
+}
+
+// DoReadConfidentialValue reads the subscription together with its primary and secondary keys
+func (s *SubscriptionSpecializer) DoReadConfidentialValue(ctx context.Context, planData *SubscriptionModel) (armapimanagement.SubscriptionContract, []byte, resources.ResourceExistenceCheck, diag.Diagnostics) {
 	rv := diag.Diagnostics{}
 	if planData.Id.IsUnknown() {
-		return armapimanagement.SubscriptionContract{}, resources.ResourceNotYetCreated, nil
+		return armapimanagement.SubscriptionContract{}, nil, resources.ResourceNotYetCreated, nil
 	}
 
 	azSubscriptionId, azErr := s.factory.GetAzSubscription(planData.DestinationSubscription.AzSubscriptionId.ValueString())
@@ -243,13 +284,13 @@ func (s *SubscriptionSpecializer) DoRead(ctx context.Context, planData *Subscrip
 		rv.AddError(
 			"Missing Azure subscription Id",
 			"Creating Azure objects requires identifying a subscription; either on the level of the provider, or on the level of the resource")
-		return armapimanagement.SubscriptionContract{}, resources.ResourceCheckError, rv
+		return armapimanagement.SubscriptionContract{}, nil, resources.ResourceCheckError, rv
 	}
 
 	matcher := idExp.FindStringSubmatch(planData.Id.ValueString())
 	if matcher == nil {
 		rv.AddError("Resource identifier is malformed", fmt.Sprintf("The id of this resource (%s) does not match the expected format. This is a bug of the provider. Please report this", planData.Id.ValueString()))
-		return armapimanagement.SubscriptionContract{}, resources.ResourceCheckError, rv
+		return armapimanagement.SubscriptionContract{}, nil, resources.ResourceCheckError, rv
 	}
 	azSubscriptionIdFromId := matcher[1]
 	resourceGroup := matcher[2]
@@ -261,16 +302,16 @@ func (s *SubscriptionSpecializer) DoRead(ctx context.Context, planData *Subscrip
 			"Implicit move",
 			fmt.Sprintf("This APIM subscription is created in Azure subscription %s, whereas the configuration now requires it to be created in Azure subscription %s. Delete and recreate this resource", azSubscriptionIdFromId, azSubscriptionId),
 		)
-		return armapimanagement.SubscriptionContract{}, resources.ResourceCheckError, rv
+		return armapimanagement.SubscriptionContract{}, nil, resources.ResourceCheckError, rv
 	}
 
 	subscriptionClient, err := s.factory.GetApimSubscriptionClient(azSubscriptionIdFromId)
 	if err != nil {
 		rv.AddError("Cannot acquire APIM subscription client", fmt.Sprintf("Cannot acquire apim subscription client to subscription %s: %s", azSubscriptionIdFromId, err.Error()))
-		return armapimanagement.SubscriptionContract{}, resources.ResourceCheckError, rv
+		return armapimanagement.SubscriptionContract{}, nil, resources.ResourceCheckError, rv
 	} else if subscriptionClient == nil {
 		rv.AddError("Cannot acquire APIM subscription client", "Keys client returned is nil")
-		return armapimanagement.SubscriptionContract{}, resources.ResourceCheckError, rv
+		return armapimanagement.SubscriptionContract{}, nil, resources.ResourceCheckError, rv
 	}
 
 	subscriptionState, err := subscriptionClient.Get(
@@ -292,14 +333,14 @@ func (s *SubscriptionSpecializer) DoRead(ctx context.Context, planData *Subscrip
 				)
 			}
 
-			return armapimanagement.SubscriptionContract{}, resources.ResourceNotFound, rv
+			return armapimanagement.SubscriptionContract{}, nil, resources.ResourceNotFound, rv
 		} else {
 			rv.AddError("Cannot read subscription", fmt.Sprintf("Cannot read subscription %s of service %s in resource group %s: %s",
 				apimSubscriptionIdFromId,
 				apimServiceName,
 				resourceGroup,
 				err.Error()))
-			return armapimanagement.SubscriptionContract{}, resources.ResourceCheckError, rv
+			return armapimanagement.SubscriptionContract{}, nil, resources.ResourceCheckError, rv
 		}
 	}
 
@@ -310,22 +351,13 @@ func (s *SubscriptionSpecializer) DoRead(ctx context.Context, planData *Subscrip
 			apimServiceName,
 			resourceGroup,
 			keyReadErr.Error()))
-		return subscriptionState.SubscriptionContract, resources.ResourceCheckError, rv
-	}
-
-	// Catch and detect the drift.
-	if plainData.GetPrimaryKey() != *keys.PrimaryKey || plainData.GetSecondaryKey() != *keys.SecondaryKey {
-		rv.AddWarning("Subscription keys have drifted from the state declared in ciphertext",
-			fmt.Sprintf("API management subscription %s of service %s in resource group %s does not match the state specified in ciphertext",
-				apimSubscriptionIdFromId,
-				apimServiceName,
-				resourceGroup),
-		)
-
-		return subscriptionState.SubscriptionContract, resources.ResourceConfidentialDataDrift, rv
+		return subscriptionState.SubscriptionContract, nil, resources.ResourceCheckError, rv
 	}
 
-	return subscriptionState.SubscriptionContract, resources.ResourceExists, rv
+	return subscriptionState.SubscriptionContract,
+		subscriptionKeysValue(core.StringPtrValue(keys.PrimaryKey), core.StringPtrValue(keys.SecondaryKey)),
+		resources.ResourceExists,
+		rv
 }
 
 func (s *SubscriptionSpecializer) SetDriftToConfidentialData(_ context.Context, planData *SubscriptionModel) {
@@ -606,6 +638,7 @@ func NewSubscriptionResource() resource.Resource {
 	}
 
 	resourceSchema := schema.Schema{
+		Version:             1,
 		Description:         "Creates a subscription in the Azure API management service with pre-set primary and secondary subscription keys",
 		MarkdownDescription: subscriptionResourceMarkdownDescription,
 
@@ -614,11 +647,16 @@ func NewSubscriptionResource() resource.Resource {
 
 	apimSubscriptionSpecializer := &SubscriptionSpecializer{}
 
-	return &resources.ConfidentialGenericResource[SubscriptionModel, int, ConfidentialSubscriptionData, armapimanagement.SubscriptionContract]{
+	return &resources.ConfidentialGenericResource[SubscriptionModel, SubscriptionIdentityModel, ConfidentialSubscriptionData, armapimanagement.SubscriptionContract]{
 		Specializer:    apimSubscriptionSpecializer,
 		MutableRU:      apimSubscriptionSpecializer,
 		ResourceName:   "apim_subscription",
 		ResourceSchema: resourceSchema,
+
+		ResourceIdentitySchema: SubscriptionIdentityModelSchema(),
+		StateUpgraders: map[int64]resource.StateUpgrader{
+			0: resources.NewCarryOverStateUpgrader(subscriptionSchemaV0()),
+		},
 	}
 }
 
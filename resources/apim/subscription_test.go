@@ -662,3 +662,30 @@ func Test_CreateSubscriptionEncryptedMessage_Locking(t *testing.T) {
 		"az-c-label:///subscriptions//resourceGroups//providers/Microsoft.ApiManagement/service//subscriptions/?api=/product=productId/user=",
 		string(md.PlacementConstraints[0]))
 }
+
+func Test_Sub_UpgradeStateFromVersion0(t *testing.T) {
+	state, dg := upgradeStateFromJson(t, NewSubscriptionResource(), 0, `{
+		"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/subscriptions/sid",
+		"content": "unit-test-ciphertext",
+		"destination_subscription": {
+			"az_subscription_id": "sub",
+			"resource_group": "rg",
+			"api_management_name": "svc",
+			"apim_subscription_id": "sid",
+			"api_id": "echo-api"
+		},
+		"state": "active",
+		"display_name": "Unit test subscription",
+		"subscription_id": "sid",
+		"allow_tracing": false
+	}`)
+	assert.False(t, dg.HasError())
+
+	mdl := SubscriptionModel{}
+	assert.False(t, state.Get(context.Background(), &mdl).HasError())
+	assert.Equal(t, "unit-test-ciphertext", mdl.EncryptedSecret.ValueString())
+	assert.Equal(t, "sid", mdl.SubscriptionId.ValueString())
+	assert.Equal(t, "echo-api", mdl.DestinationSubscription.APIIdentifier.ValueString())
+	assert.True(t, mdl.DestinationSubscription.ProductIdentifier.IsNull())
+	assert.Equal(t, "active", mdl.State.ValueString())
+}
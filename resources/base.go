@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/schemasupport"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	tfstringvalidators "github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -16,7 +18,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	resourceSchema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -61,16 +63,99 @@ func (wcmm *ConfidentialMaterialModel) GetDestinationCoordinateFromId() (core.Az
 	return rv, err
 }
 
+// ResourceTimeoutsModel the timeouts of the resource operations, given in the `timeouts` attribute
+type ResourceTimeoutsModel struct {
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (m *ResourceTimeoutsModel) GetTimeouts() timeouts.Value {
+	return m.Timeouts
+}
+
+// TimeoutsModel is implemented by the models of the resources accepting the timeouts of the operations.
+type TimeoutsModel interface {
+	GetTimeouts() timeouts.Value
+}
+
 // WrappedAzKeyVaultObjectConfidentialMaterialModel a model for the Azure KeyVault
 // object. It includes wrapped confidential data and repeated elements (not-before, not-after,
 // tags, and enabled)
 type WrappedAzKeyVaultObjectConfidentialMaterialModel struct {
 	ConfidentialMaterialModel
+	ResourceTimeoutsModel
+
+	Tags           types.Map    `tfsdk:"tags"`
+	ProvenanceTags types.Map    `tfsdk:"provenance_tags"`
+	NotBefore      types.String `tfsdk:"not_before_date"`
+	NotAfter       types.String `tfsdk:"not_after_date"`
+	Enabled        types.Bool   `tfsdk:"enabled"`
+}
+
+// ProvenanceTaggedModel is implemented by the models of the resources whose Azure objects carry the
+// provenance tags.
+type ProvenanceTaggedModel interface {
+	// IsProvenanceTagsUnknown whether the provenance tags are yet to be stamped, i.e. the plan doesn't carry
+	// these from the state
+	IsProvenanceTagsUnknown() bool
+	SetProvenanceTags(tags map[string]string)
+}
+
+// ProvenanceTagReader is implemented by the specializers of the resources whose Azure objects carry the
+// provenance tags. It returns the provenance tags of the object currently at the destination of the
+// resource, or nil where the destination has no object.
+type ProvenanceTagReader[TMdl any] interface {
+	GetDestinationProvenanceTags(ctx context.Context, tfModel *TMdl) (map[string]string, error)
+}
+
+func (cm *WrappedAzKeyVaultObjectConfidentialMaterialModel) IsProvenanceTagsUnknown() bool {
+	return cm.ProvenanceTags.IsUnknown()
+}
+
+func (cm *WrappedAzKeyVaultObjectConfidentialMaterialModel) SetProvenanceTags(tags map[string]string) {
+	tfTags := map[string]attr.Value{}
+	for k, v := range tags {
+		tfTags[k] = types.StringValue(v)
+	}
 
-	Tags      types.Map    `tfsdk:"tags"`
-	NotBefore types.String `tfsdk:"not_before_date"`
-	NotAfter  types.String `tfsdk:"not_after_date"`
-	Enabled   types.Bool   `tfsdk:"enabled"`
+	cm.ProvenanceTags, _ = types.MapValue(types.StringType, tfTags)
+}
+
+// AzTagsAsPtr the tags to set on the Azure object: the tags of the configuration merged with the
+// provenance tags.
+func (cm *WrappedAzKeyVaultObjectConfidentialMaterialModel) AzTagsAsPtr() map[string]*string {
+	rv := cm.TagsAsPtr()
+
+	if !cm.ProvenanceTags.IsNull() && !cm.ProvenanceTags.IsUnknown() {
+		for k, v := range cm.ProvenanceTags.Elements() {
+			if strAttr, ok := v.(types.String); ok {
+				if rv == nil {
+					rv = map[string]*string{}
+				}
+				valueString := strAttr.ValueString()
+				rv[k] = &valueString
+			}
+		}
+	}
+
+	return rv
+}
+
+// AcceptAzTags reads the tags of the Azure object into the model. The provenance tags are kept apart
+// from the tags of the configuration so that these don't appear as drift.
+func (cm *WrappedAzKeyVaultObjectConfidentialMaterialModel) AcceptAzTags(p map[string]*string) {
+	userTags, provenanceTags := core.SplitProvenanceTags(p)
+	cm.ConvertAzMap(userTags, &cm.Tags)
+	cm.AcceptAzProvenanceTags(provenanceTags)
+}
+
+// AcceptAzProvenanceTags reads the provenance tags of the Azure object into the model.
+func (cm *WrappedAzKeyVaultObjectConfidentialMaterialModel) AcceptAzProvenanceTags(p map[string]*string) {
+	_, provenanceTags := core.SplitProvenanceTags(p)
+	if len(provenanceTags) > 0 {
+		cm.ProvenanceTags = ConvertStringPtrMapToTerraform(provenanceTags)
+	} else {
+		cm.ProvenanceTags = types.MapNull(types.StringType)
+	}
 }
 
 func (cm *WrappedAzKeyVaultObjectConfidentialMaterialModel) StringTypeAsPtr(tfVal *types.String) *string {
@@ -198,6 +283,8 @@ func WrappedAzKeyVaultObjectConfidentialMaterialModelSchema(oreAttrs map[string]
 			ElementType: types.StringType,
 		},
 
+		"provenance_tags": ProvenanceTagsSchema(),
+
 		"not_before_date": resourceSchema.StringAttribute{
 			Optional:    true,
 			Computed:    true,
@@ -226,16 +313,36 @@ func WrappedAzKeyVaultObjectConfidentialMaterialModelSchema(oreAttrs map[string]
 	return baseSchema
 }
 
+// ProvenanceTagsSchema the schema of the provenance tags the provider stamps on the Azure object
+func ProvenanceTagsSchema() resourceSchema.MapAttribute {
+	return resourceSchema.MapAttribute{
+		Computed:            true,
+		Description:         "Provenance tags the provider stamped on this object, where the provider is configured to do so",
+		MarkdownDescription: "Provenance tags the provider stamped on this object, where the provider is configured with `provenance_tags`",
+		ElementType:         types.StringType,
+		PlanModifiers: []planmodifier.Map{
+			mapplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
 func WrappedConfidentialMaterialModelSchema(moreAttrs map[string]resourceSchema.Attribute, requireReplace bool) map[string]resourceSchema.Attribute {
 	var contentPlanModifiers []planmodifier.String = nil
 
 	if requireReplace {
 		contentPlanModifiers = []planmodifier.String{
-			stringplanmodifier.RequiresReplace(),
+			StringRequiresReplaceUnlessMoved(),
 		}
 	}
 
 	baseSchema := map[string]resourceSchema.Attribute{
+		"timeouts": timeouts.Attributes(context.Background(), timeouts.Opts{
+			Create: true,
+			Read:   true,
+			Update: true,
+			Delete: true,
+		}),
+
 		"id": resourceSchema.StringAttribute{
 			MarkdownDescription: "Identifier of the decryption operation",
 			Computed:            true,
@@ -271,7 +378,7 @@ func WrappedConfidentialMaterialModelSchema(moreAttrs map[string]resourceSchema.
 			},
 
 			PlanModifiers: []planmodifier.Object{
-				objectplanmodifier.RequiresReplace(),
+				ObjectRequiresReplaceUnlessMoved(),
 			},
 		},
 
@@ -365,13 +472,10 @@ func (d *CommonConfidentialResource) CheckCiphertextExpiry(ctx context.Context,
 			return
 		}
 
-		warningDuration := time.Hour * 24 * 30
-		expiryTime := time.Unix(header.Expiry, 0)
-		diff := expiryTime.Sub(time.Now())
-		if diff < warningDuration {
+		if msg, warn := core.ExpiryWarningOf(header, now, d.Factory.GetExpiryWarningWindow()); warn {
 			dg.AddWarning(
 				"Ciphertext is about to expire",
-				"Ciphertext has less than 30 days remaining in the allowed use. Re-encrypt and replace the ciphertext of this resource before it expires",
+				fmt.Sprintf("The %s. Re-encrypt and replace the ciphertext of this resource before it expires", msg),
 			)
 		}
 	} else {
@@ -383,19 +487,92 @@ func (d *CommonConfidentialResource) CheckCiphertextCreateExpiry(ctx context.Con
 	if header.CreateLimit > 0 {
 		now := time.Now()
 
-		tflog.Info(ctx, fmt.Sprintf("Checking create limits: now unix is %d; header's create limit is %d", now.Unix(), header.Expiry))
+		tflog.Info(ctx, fmt.Sprintf("Checking create limits: now unix is %d; header's create limit is %d", now.Unix(), header.CreateLimit))
 
 		if now.Unix() > header.CreateLimit {
 			dg.AddError(
 				"Time to create Azure objects from this ciphertext has elapsed",
 				"The ciphertext author has placed the timing limits to create Azure object using the confidential material of this resource. These limits have now lapsed. Re-encrypt and replace the ciphertext of this resource",
 			)
+		} else if msg, warn := core.CreateLimitWarningOf(header, now, d.Factory.GetExpiryWarningWindow()); warn {
+			dg.AddWarning(
+				"Time to create Azure objects from this ciphertext is about to elapse",
+				fmt.Sprintf("The %s. Re-encrypt and replace the ciphertext of this resource if the objects will need to be re-created afterwards", msg),
+			)
 		}
 	} else {
 		tflog.Info(ctx, "Ciphertext does not constraint create limits")
 	}
 }
 
+// CheckCiphertextNotBefore checks that the ciphertext has become active.
+func (d *CommonConfidentialResource) CheckCiphertextNotBefore(ctx context.Context, header core.ConfidentialDataJsonHeader, dg *diag.Diagnostics) {
+	if header.NotBefore > 0 {
+		now := time.Now()
+
+		tflog.Info(ctx, fmt.Sprintf("Checking activation: now unix is %d; header's not-before is %d", now.Unix(), header.NotBefore))
+
+		if now.Unix() < header.NotBefore {
+			dg.AddError(
+				"Ciphertext is not yet active",
+				fmt.Sprintf("The ciphertext %s may not be used before %s. Wait until this date, or re-encrypt and replace the ciphertext of this resource", header.Uuid, time.Unix(header.NotBefore, 0).UTC().Format(time.RFC3339)),
+			)
+		}
+	} else {
+		tflog.Info(ctx, "Ciphertext does not constrain activation date")
+	}
+}
+
+// CheckCiphertextRevocation checks that the ciphertext is not revoked. Where the revocation list cannot be
+// consulted, the ciphertext is not used.
+func (d *CommonConfidentialResource) CheckCiphertextRevocation(ctx context.Context, header core.ConfidentialDataJsonHeader, dg *diag.Diagnostics) {
+	revocation, err := d.Factory.GetCiphertextRevocation(ctx, header.Uuid)
+	if err != nil {
+		tflog.Error(ctx, fmt.Sprintf("Revocation of ciphertext %s cannot be checked: %s", header.Uuid, err.Error()))
+		dg.AddError(
+			"Ciphertext revocation cannot be checked",
+			fmt.Sprintf("The provider is configured with the revocation list, however checking the revocation of ciphertext %s returned this error: %s", header.Uuid, err.Error()),
+		)
+	} else if revocation != nil {
+		reason := revocation.Reason
+		if len(reason) == 0 {
+			reason = "no reason given"
+		}
+		dg.AddError(
+			"Ciphertext has been revoked",
+			fmt.Sprintf("The ciphertext %s has been revoked (%s) and may no longer be used. Re-encrypt and replace the ciphertext of this resource", header.Uuid, reason),
+		)
+	}
+}
+
+// CheckCiphertextDestination checks that the tracker didn't record the ciphertext at destinations other than
+// the one given. This detects the ciphertext copied from another resource. The check is performed only where
+// the object tracking is enabled.
+func (d *CommonConfidentialResource) CheckCiphertextDestination(ctx context.Context, header core.ConfidentialDataJsonHeader, destination string, dg *diag.Diagnostics) {
+	if !d.Factory.IsObjectTrackingEnabled() || len(destination) == 0 {
+		return
+	}
+
+	tracked, err := d.Factory.ListTrackedObjects(ctx, []string{header.Uuid})
+	if err != nil {
+		tflog.Error(ctx, fmt.Sprintf("Destination of ciphertext %s cannot be checked: %s", header.Uuid, err.Error()))
+		dg.AddError(
+			"Ciphertext destination cannot be checked",
+			fmt.Sprintf("Attempting to read the destinations recorded for ciphertext %s returned this error: %s", header.Uuid, err.Error()),
+		)
+		return
+	}
+
+	for _, obj := range tracked {
+		if !obj.IsPlacedAt(destination) {
+			dg.AddError(
+				"Ciphertext is bound to another destination",
+				fmt.Sprintf("The ciphertext %s was placed at %s and cannot be used at %s. The ciphertext appears to be copied from another resource; encrypt the confidential material for this resource", header.Uuid, strings.Join(obj.Destinations, ", "), destination),
+			)
+		}
+	}
+}
+
 type ConfidentialDatasourceBase struct {
 	CommonConfidentialResource
 }
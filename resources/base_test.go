@@ -1,9 +1,15 @@
 package resources
 
 import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 func Test_WAKVOCMM_StringTypeAsPtr(t *testing.T) {
@@ -70,3 +76,33 @@ func Test_WAKVOCMM_ConvertAzMap_EmptyMapOverUnknownWillBeNull(t *testing.T) {
 	assert.False(t, refType.Tags.IsUnknown())
 	assert.True(t, refType.Tags.IsNull())
 }
+
+func Test_WithOperationTimeout_UsesResourceTimeouts(t *testing.T) {
+	mdl := WrappedAzKeyVaultObjectConfidentialMaterialModel{
+		ResourceTimeoutsModel: ResourceTimeoutsModel{
+			Timeouts: timeouts.Value{
+				Object: types.ObjectNull(map[string]attr.Type{"create": types.StringType}),
+			},
+		},
+	}
+
+	dg := diag.Diagnostics{}
+	ctx, cancel := WithOperationTimeout(context.Background(), &mdl, timeouts.Value.Create, time.Minute, &dg)
+	defer cancel()
+
+	assert.False(t, dg.HasError())
+	deadline, hasDeadline := ctx.Deadline()
+	assert.True(t, hasDeadline)
+	assert.True(t, time.Until(deadline) <= time.Minute)
+}
+
+func Test_WithOperationTimeout_IgnoresModelsWithoutTimeouts(t *testing.T) {
+	mdl := "model"
+
+	dg := diag.Diagnostics{}
+	ctx, cancel := WithOperationTimeout(context.Background(), &mdl, timeouts.Value.Create, time.Minute, &dg)
+	defer cancel()
+
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+}
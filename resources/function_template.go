@@ -68,15 +68,27 @@ func (p LimitedCreateProtectionParam) GetAttributeTypes() map[string]attr.Type {
 	}
 }
 
+type NotBeforeProtectionParam struct {
+	NotBefore types.String `tfsdk:"not_before"`
+}
+
+func (p NotBeforeProtectionParam) GetAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"not_before": types.StringType,
+	}
+}
+
 type ResourceProtectionParams struct {
 	ProtectionParams
 	LimitedCreateProtectionParam
+	NotBeforeProtectionParam
 }
 
 func (p ResourceProtectionParams) GetAttributeTypes() map[string]attr.Type {
 	rv := map[string]attr.Type{}
 	maps.Copy(rv, p.ProtectionParams.GetAttributeTypes())
 	maps.Copy(rv, p.LimitedCreateProtectionParam.GetAttributeTypes())
+	maps.Copy(rv, p.NotBeforeProtectionParam.GetAttributeTypes())
 
 	return rv
 }
@@ -95,6 +107,9 @@ func (p ResourceProtectionParams) Into(ctx context.Context, c *core.SecondaryPro
 	if err := p.LimitedCreateProtectionParam.Into(c); err != nil {
 		return err
 	}
+	if err := p.NotBeforeProtectionParam.Into(c); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -134,6 +149,19 @@ func (p LimitedCreateProtectionParam) Into(c *core.SecondaryProtectionParameters
 	return nil
 }
 
+func (p NotBeforeProtectionParam) Into(c *core.SecondaryProtectionParameters) error {
+	if !p.NotBefore.IsUnknown() && !p.NotBefore.IsNull() && len(p.NotBefore.ValueString()) > 0 {
+		t, timeErr := time.Parse(time.RFC3339, p.NotBefore.ValueString())
+		if timeErr != nil {
+			return timeErr
+		}
+
+		c.NotBefore = t.Unix()
+	}
+
+	return nil
+}
+
 type FunctionTemplate[TMdl any, TProtection ProtectionParameterized, DestMdl any] struct {
 	Name                                    string
 	Summary                                 string
@@ -0,0 +1,177 @@
+package general
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// CiphertextRevocationModel the revocation of the ciphertext recorded in the object tracker
+type CiphertextRevocationModel struct {
+	Id        types.String `tfsdk:"id"`
+	Uuid      types.String `tfsdk:"uuid"`
+	Reason    types.String `tfsdk:"reason"`
+	RevokedAt types.String `tfsdk:"revoked_at"`
+}
+
+func (m *CiphertextRevocationModel) Accept(revocation core.CiphertextRevocation) {
+	m.Id = types.StringValue(m.Uuid.ValueString())
+	m.Reason = types.StringValue(revocation.Reason)
+	m.RevokedAt = stringOrNull(revocation.RevokedAt)
+}
+
+type CiphertextRevocationResource struct {
+	resources.ConfidentialResourceBase
+}
+
+func (r *CiphertextRevocationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_general_ciphertext_revocation"
+}
+
+//go:embed ciphertext_revocation.md
+var ciphertextRevocationResourceMarkdownDescription string
+
+func (r *CiphertextRevocationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Revokes the ciphertext in the object tracker",
+		MarkdownDescription: ciphertextRevocationResourceMarkdownDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Uuid of the revoked ciphertext",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				Description:         "Uuid of the ciphertext to revoke, as tfgen inspect reports it",
+				MarkdownDescription: "Uuid of the ciphertext to revoke, as `tfgen inspect` reports it",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"reason": schema.StringAttribute{
+				Description: "Reason of the revocation, reported when the revoked ciphertext is used",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"revoked_at": schema.StringAttribute{
+				Description: "Date (RFC3339) when the revocation was recorded",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Revoke records the revocation of the ciphertext
+func (r *CiphertextRevocationResource) Revoke(ctx context.Context, data *CiphertextRevocationModel, dg *diag.Diagnostics) {
+	revocation, err := r.Factory.RevokeCiphertext(ctx, data.Uuid.ValueString(), data.Reason.ValueString())
+	if err != nil {
+		dg.AddError(
+			"Cannot revoke ciphertext",
+			fmt.Sprintf("Attempting to record the revocation of ciphertext %s returned this error: %s", data.Uuid.ValueString(), err.Error()),
+		)
+		return
+	} else if revocation == nil {
+		dg.AddError("Cannot revoke ciphertext", "The revocation was recorded, however it cannot be read back. This is a provider bug. Please report this")
+		return
+	}
+
+	data.Accept(*revocation)
+}
+
+func (r *CiphertextRevocationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data CiphertextRevocationModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.Revoke(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CiphertextRevocationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CiphertextRevocationModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	revocation, err := r.Factory.GetCiphertextRevocation(ctx, data.Uuid.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Cannot read revocation",
+			fmt.Sprintf("Attempting to read the revocation of ciphertext %s returned this error: %s", data.Uuid.ValueString(), err.Error()),
+		)
+		return
+	} else if revocation == nil {
+		tflog.Warn(ctx, fmt.Sprintf("Revocation of ciphertext %s was lifted outside of Terraform; it will be recorded again", data.Uuid.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.Accept(*revocation)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CiphertextRevocationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data CiphertextRevocationModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.Revoke(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete removes the revocation from the state only. The revocation is not lifted: a leaked ciphertext must not
+// become usable again because its revocation was removed from the configuration.
+func (r *CiphertextRevocationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data CiphertextRevocationModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Revocation is not lifted",
+		fmt.Sprintf("Ciphertext %s remains revoked in the object tracker; the revocation can only be lifted in the tracker table", data.Uuid.ValueString()),
+	)
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CiphertextRevocationResource{}
+var _ resource.ResourceWithConfigure = &CiphertextRevocationResource{}
+
+func NewCiphertextRevocationResource() resource.Resource {
+	return &CiphertextRevocationResource{}
+}
@@ -0,0 +1,55 @@
+package general
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_CiphertextRevocation_RecordsRevocation(t *testing.T) {
+	factory := FactoryMock{}
+	factory.On("RevokeCiphertext", mock.Anything, "uuid-1", "leaked").
+		Return(&core.CiphertextRevocation{Uuid: "uuid-1", Reason: "leaked", RevokedAt: "2026-10-19T10:00:00Z"}, nil)
+
+	r := CiphertextRevocationResource{}
+	r.Factory = &factory
+
+	mdl := CiphertextRevocationModel{
+		Uuid:   types.StringValue("uuid-1"),
+		Reason: types.StringValue("leaked"),
+	}
+	dg := diag.Diagnostics{}
+	r.Revoke(context.Background(), &mdl, &dg)
+
+	assert.False(t, dg.HasError())
+	assert.Equal(t, "uuid-1", mdl.Id.ValueString())
+	assert.Equal(t, "2026-10-19T10:00:00Z", mdl.RevokedAt.ValueString())
+	factory.AssertExpectations(t)
+}
+
+func Test_CiphertextRevocation_IfRevocationIsRefused(t *testing.T) {
+	factory := FactoryMock{}
+	factory.On("RevokeCiphertext", mock.Anything, "uuid-1", "leaked").
+		Return((*core.CiphertextRevocation)(nil), errors.New("set revocation.use_tracker on the provider"))
+
+	r := CiphertextRevocationResource{}
+	r.Factory = &factory
+
+	mdl := CiphertextRevocationModel{
+		Uuid:   types.StringValue("uuid-1"),
+		Reason: types.StringValue("leaked"),
+	}
+	dg := diag.Diagnostics{}
+	r.Revoke(context.Background(), &mdl, &dg)
+
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Cannot revoke ciphertext", dg[0].Summary())
+	assert.Contains(t, dg[0].Detail(), "revocation.use_tracker")
+	factory.AssertExpectations(t)
+}
@@ -149,7 +149,7 @@ func (d *ConfidentialContentDataSource) Read(ctx context.Context, req datasource
 	dg.Append(resp.State.Set(ctx, &data)...)
 
 	if header.NumUses > 0 {
-		if trackErr := d.Factory.TrackObjectId(ctx, header.Uuid); trackErr != nil {
+		if trackErr := d.Factory.TrackObjectId(ctx, header.Uuid, ""); trackErr != nil {
 			dg.AddError(
 				"Content usage cannot be tracked",
 				fmt.Sprintf("This content has a limit as to how much time it can be read. Trackign the usage returned this error: %s", trackErr.Error()),
@@ -159,6 +159,11 @@ func (d *ConfidentialContentDataSource) Read(ctx context.Context, req datasource
 }
 
 func (d *ConfidentialContentDataSource) CheckUnpackCondition(ctx context.Context, header core.ConfidentialDataJsonHeader, dg *diag.Diagnostics) {
+	d.CheckCiphertextRevocation(ctx, header, dg)
+	if dg.HasError() {
+		return
+	}
+
 	d.CheckCiphertextExpiry(ctx, header, dg)
 	if dg.HasError() {
 		return
@@ -90,8 +90,30 @@ func Test_CreateContentEncryptedMessage_EncryptedMessage(t *testing.T) {
 	assert.Nil(t, hdr.PlacementConstraints)
 }
 
+func Test_Content_CheckUnpackCondition_IfCiphertextRevoked(t *testing.T) {
+	mock := FactoryMock{}
+	ds := ConfidentialContentDataSource{}
+	ds.Factory = &mock
+	mock.GivenCiphertextIsRevoked("leaked")
+	dg := diag.Diagnostics{}
+
+	hdr := core.ConfidentialDataJsonHeader{
+		Uuid:   "revoked-uuid",
+		Expiry: time.Now().Unix() + 1000,
+	}
+
+	ds.CheckUnpackCondition(context.Background(), hdr, &dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Ciphertext has been revoked", dg[0].Summary())
+	assert.Contains(t, dg[0].Detail(), "leaked")
+	mock.AssertExpectations(t)
+}
+
 func Test_Content_CheckUnpackCondition_IfCiphertextExpired(t *testing.T) {
+	mock := FactoryMock{}
 	ds := ConfidentialContentDataSource{}
+	ds.Factory = &mock
+	mock.GivenCiphertextIsNotRevoked()
 	dg := diag.Diagnostics{}
 
 	hdr := core.ConfidentialDataJsonHeader{
@@ -107,6 +129,8 @@ func Test_Content_CheckUnpackCondition_IfPlacementIsNotPossible(t *testing.T) {
 	mock := FactoryMock{}
 	ds := ConfidentialContentDataSource{}
 	ds.Factory = &mock
+	mock.GivenExpiryWarningWindow(core.ExpiryWarningWindowOf(core.DefaultExpiryWarningDays))
+	mock.GivenCiphertextIsNotRevoked()
 
 	dg := diag.Diagnostics{}
 
@@ -127,6 +151,8 @@ func Test_Content_CheckUnpackCondition_IfNumberOfUserForUnconfiguredFactory(t *t
 	mock := FactoryMock{}
 	ds := ConfidentialContentDataSource{}
 	ds.Factory = &mock
+	mock.GivenExpiryWarningWindow(core.ExpiryWarningWindowOf(core.DefaultExpiryWarningDays))
+	mock.GivenCiphertextIsNotRevoked()
 
 	dg := diag.Diagnostics{}
 
@@ -149,6 +175,8 @@ func Test_Content_CheckUnpackCondition_IfNumberOfUsesErrs(t *testing.T) {
 	mock := FactoryMock{}
 	ds := ConfidentialContentDataSource{}
 	ds.Factory = &mock
+	mock.GivenExpiryWarningWindow(core.ExpiryWarningWindowOf(core.DefaultExpiryWarningDays))
+	mock.GivenCiphertextIsNotRevoked()
 
 	dg := diag.Diagnostics{}
 
@@ -173,6 +201,8 @@ func Test_Content_CheckUnpackCondition_IfUsesDepleted(t *testing.T) {
 	mock := FactoryMock{}
 	ds := ConfidentialContentDataSource{}
 	ds.Factory = &mock
+	mock.GivenExpiryWarningWindow(core.ExpiryWarningWindowOf(core.DefaultExpiryWarningDays))
+	mock.GivenCiphertextIsNotRevoked()
 
 	dg := diag.Diagnostics{}
 
@@ -197,6 +227,8 @@ func Test_Content_CheckUnpackCondition_IfUsesAlmostDepleted(t *testing.T) {
 	mock := FactoryMock{}
 	ds := ConfidentialContentDataSource{}
 	ds.Factory = &mock
+	mock.GivenExpiryWarningWindow(core.ExpiryWarningWindowOf(core.DefaultExpiryWarningDays))
+	mock.GivenCiphertextIsNotRevoked()
 
 	dg := diag.Diagnostics{}
 
@@ -217,3 +249,27 @@ func Test_Content_CheckUnpackCondition_IfUsesAlmostDepleted(t *testing.T) {
 
 	mock.AssertExpectations(t)
 }
+
+func Test_Content_CheckUnpackCondition_WarnsIfExpiryIsWithinWindow(t *testing.T) {
+	mock := FactoryMock{}
+	ds := ConfidentialContentDataSource{}
+	ds.Factory = &mock
+	mock.GivenExpiryWarningWindow(core.ExpiryWarningWindowOf(90))
+	mock.GivenCiphertextIsNotRevoked()
+
+	dg := diag.Diagnostics{}
+
+	hdr := core.ConfidentialDataJsonHeader{
+		Uuid:   "uuid",
+		Expiry: time.Now().Unix() + 60*24*60*60,
+	}
+
+	mock.GivenEnsureCanPlaceLabelledObject(ContentObjectType)
+
+	ds.CheckUnpackCondition(context.Background(), hdr, &dg)
+	assert.False(t, dg.HasError())
+	assert.Equal(t, "Ciphertext is about to expire", dg[0].Summary())
+	assert.Contains(t, dg[0].Detail(), "ciphertext uuid expires on")
+
+	mock.AssertExpectations(t)
+}
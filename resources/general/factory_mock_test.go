@@ -3,6 +3,7 @@ package general
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -27,6 +28,42 @@ func (m *FactoryMock) GivenIsObjectTrackingEnabled(how bool) {
 	m.On("IsObjectTrackingEnabled").Return(how)
 }
 
+func (m *FactoryMock) GetExpiryWarningWindow() time.Duration {
+	args := m.Called()
+	return args.Get(0).(time.Duration)
+}
+
+func (m *FactoryMock) GivenExpiryWarningWindow(d time.Duration) {
+	m.On("GetExpiryWarningWindow").Return(d).Maybe()
+}
+
+func (m *FactoryMock) RevokeCiphertext(ctx context.Context, uuid string, reason string) (*core.CiphertextRevocation, error) {
+	rv := m.Called(ctx, uuid, reason)
+	return rv.Get(0).(*core.CiphertextRevocation), rv.Error(1)
+}
+
+func (m *FactoryMock) GetCiphertextRevocation(ctx context.Context, uuid string) (*core.CiphertextRevocation, error) {
+	rv := m.Called(ctx, uuid)
+	return rv.Get(0).(*core.CiphertextRevocation), rv.Error(1)
+}
+
+func (m *FactoryMock) GivenCiphertextIsNotRevoked() {
+	m.On("GetCiphertextRevocation", mock.Anything, mock.Anything).Return((*core.CiphertextRevocation)(nil), nil).Maybe()
+}
+
+func (m *FactoryMock) GivenCiphertextIsRevoked(reason string) {
+	m.On("GetCiphertextRevocation", mock.Anything, mock.Anything).Return(&core.CiphertextRevocation{Reason: reason}, nil)
+}
+
+func (m *FactoryMock) ListTrackedObjects(ctx context.Context, uuids []string) ([]core.TrackedObject, error) {
+	args := m.Called(ctx, uuids)
+	return args.Get(0).([]core.TrackedObject), args.Error(1)
+}
+
+func (m *FactoryMock) GivenListTrackedObjects(objects []core.TrackedObject) {
+	m.On("ListTrackedObjects", mock.Anything, mock.Anything).Return(objects, nil)
+}
+
 func (m *FactoryMock) GetTackedObjectUses(ctx context.Context, id string) (int, error) {
 	args := m.Called(ctx, id)
 	return args.Int(0), args.Error(1)
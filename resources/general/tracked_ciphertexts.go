@@ -0,0 +1,160 @@
+package general
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TrackedCiphertextModel the use of a single ciphertext recorded by the object tracker.
+type TrackedCiphertextModel struct {
+	Uuid        types.String `tfsdk:"uuid"`
+	NumUses     types.Int64  `tfsdk:"num_uses"`
+	FirstUse    types.String `tfsdk:"first_use"`
+	LastUse     types.String `tfsdk:"last_use"`
+	Destination types.String `tfsdk:"destination"`
+}
+
+func (m *TrackedCiphertextModel) Accept(obj core.TrackedObject) {
+	m.Uuid = types.StringValue(obj.Uuid)
+	m.NumUses = types.Int64Value(int64(obj.NumUses))
+	m.FirstUse = stringOrNull(obj.FirstUsedAt)
+	m.LastUse = stringOrNull(obj.LastUsedAt)
+	m.Destination = stringOrNull(obj.Destination)
+}
+
+func stringOrNull(v string) types.String {
+	if len(v) > 0 {
+		return types.StringValue(v)
+	}
+	return types.StringNull()
+}
+
+// TrackedCiphertextsModel Model of the data source reporting the uses of the ciphertexts.
+type TrackedCiphertextsModel struct {
+	Uuids       types.Set                `tfsdk:"uuids"`
+	Ciphertexts []TrackedCiphertextModel `tfsdk:"ciphertexts"`
+}
+
+type TrackedCiphertextsDataSource struct {
+	resources.ConfidentialDatasourceBase
+}
+
+func (d *TrackedCiphertextsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_general_tracked_ciphertexts"
+}
+
+//go:embed tracked_ciphertexts.md
+var trackedCiphertextsDataSourceMarkdownDescription string
+
+func (d *TrackedCiphertextsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Datasource reporting the uses of the ciphertexts recorded by the object tracker",
+		MarkdownDescription: trackedCiphertextsDataSourceMarkdownDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"uuids": schema.SetAttribute{
+				Description:         "Uuids of the ciphertexts to report. Where not specified, all tracked ciphertexts are reported",
+				MarkdownDescription: "Uuids of the ciphertexts to report. Where not specified, all tracked ciphertexts are reported",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"ciphertexts": schema.ListNestedAttribute{
+				Description:         "Tracked ciphertexts",
+				MarkdownDescription: "Tracked ciphertexts. Uuids that are not tracked are omitted",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"uuid": schema.StringAttribute{
+							Description: "Uuid of the ciphertext",
+							Computed:    true,
+						},
+						"num_uses": schema.Int64Attribute{
+							Description: "Number of times the ciphertext was used",
+							Computed:    true,
+						},
+						"first_use": schema.StringAttribute{
+							Description: "Date (RFC3339) when the ciphertext was first used",
+							Computed:    true,
+						},
+						"last_use": schema.StringAttribute{
+							Description: "Date (RFC3339) when the ciphertext was last used",
+							Computed:    true,
+						},
+						"destination": schema.StringAttribute{
+							Description: "Destination label where the ciphertext was first placed, if recorded",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ListTrackedCiphertexts lists the tracked ciphertexts identified by uuids; all tracked ciphertexts where
+// uuids are not given.
+func (d *TrackedCiphertextsDataSource) ListTrackedCiphertexts(ctx context.Context, uuids []string, dg *diag.Diagnostics) []TrackedCiphertextModel {
+	if !d.Factory.IsObjectTrackingEnabled() {
+		dg.AddError(
+			"Object tracking is not enabled",
+			"Tracked ciphertexts can be reported only where the provider is configured with the object tracker",
+		)
+		return nil
+	}
+
+	objects, err := d.Factory.ListTrackedObjects(ctx, uuids)
+	if err != nil {
+		dg.AddError(
+			"Object tracking errored",
+			fmt.Sprintf("Attempting to list tracked ciphertexts returned this error: %s", err.Error()),
+		)
+		return nil
+	}
+
+	rv := make([]TrackedCiphertextModel, len(objects))
+	for i, obj := range objects {
+		rv[i].Accept(obj)
+	}
+
+	return rv
+}
+
+func (d *TrackedCiphertextsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TrackedCiphertextsModel
+
+	dg := &resp.Diagnostics
+	dg.Append(req.Config.Get(ctx, &data)...)
+	if dg.HasError() {
+		return
+	}
+
+	var uuids []string
+	if !data.Uuids.IsNull() && !data.Uuids.IsUnknown() {
+		dg.Append(data.Uuids.ElementsAs(ctx, &uuids, false)...)
+		if dg.HasError() {
+			return
+		}
+	}
+
+	data.Ciphertexts = d.ListTrackedCiphertexts(ctx, uuids, dg)
+	if dg.HasError() {
+		return
+	}
+
+	dg.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TrackedCiphertextsDataSource{}
+
+func NewTrackedCiphertextsDataSource() datasource.DataSource {
+	return &TrackedCiphertextsDataSource{}
+}
@@ -0,0 +1,57 @@
+package general
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TrackedCiphertexts_IfTrackingIsNotEnabled(t *testing.T) {
+	mock := FactoryMock{}
+	ds := TrackedCiphertextsDataSource{}
+	ds.Factory = &mock
+	mock.GivenIsObjectTrackingEnabled(false)
+	dg := diag.Diagnostics{}
+
+	rv := ds.ListTrackedCiphertexts(context.Background(), nil, &dg)
+	assert.Nil(t, rv)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Object tracking is not enabled", dg[0].Summary())
+	mock.AssertExpectations(t)
+}
+
+func Test_TrackedCiphertexts_ListsTrackedObjects(t *testing.T) {
+	mock := FactoryMock{}
+	ds := TrackedCiphertextsDataSource{}
+	ds.Factory = &mock
+	mock.GivenIsObjectTrackingEnabled(true)
+	mock.GivenListTrackedObjects([]core.TrackedObject{
+		{
+			Uuid:        "uuid-1",
+			NumUses:     2,
+			FirstUsedAt: "2026-01-01T10:00:00Z",
+			LastUsedAt:  "2026-02-01T10:00:00Z",
+			Destination: "az-c-keyvault://vault@secrets=name",
+		},
+		{
+			Uuid:        "uuid-2",
+			NumUses:     1,
+			FirstUsedAt: "2026-01-01T10:00:00Z",
+			LastUsedAt:  "2026-01-01T10:00:00Z",
+		},
+	})
+	dg := diag.Diagnostics{}
+
+	rv := ds.ListTrackedCiphertexts(context.Background(), []string{"uuid-1", "uuid-2"}, &dg)
+	assert.False(t, dg.HasError())
+	assert.Equal(t, 2, len(rv))
+	assert.Equal(t, "uuid-1", rv[0].Uuid.ValueString())
+	assert.Equal(t, int64(2), rv[0].NumUses.ValueInt64())
+	assert.Equal(t, "2026-02-01T10:00:00Z", rv[0].LastUse.ValueString())
+	assert.Equal(t, "az-c-keyvault://vault@secrets=name", rv[0].Destination.ValueString())
+	assert.True(t, rv[1].Destination.IsNull())
+	mock.AssertExpectations(t)
+}
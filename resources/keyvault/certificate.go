@@ -42,6 +42,7 @@ type CertificateModel struct {
 
 func (cm *CertificateModel) Accept(cert azcertificates.Certificate) {
 	cm.AssignId(cert)
+	cm.AcceptAzProvenanceTags(cert.Tags)
 
 	tfSecretIdVal := types.StringNull()
 	tfVersionlessSecretIdVal := types.StringNull()
@@ -116,7 +117,7 @@ func (d *CertificateModel) ConvertToImportCertParam() azcertificates.ImportCerti
 			},
 		},
 		Password: to.Ptr(""),
-		Tags:     d.TagsAsPtr(),
+		Tags:     d.AzTagsAsPtr(),
 	}
 
 	return rv
@@ -130,7 +131,7 @@ func (d *CertificateModel) ConvertToUpdateCertParam() azcertificates.UpdateCerti
 	}
 	rv := azcertificates.UpdateCertificateParameters{
 		CertificateAttributes: &certAttr,
-		Tags:                  d.TagsAsPtr(),
+		Tags:                  d.AzTagsAsPtr(),
 	}
 
 	return rv
@@ -173,6 +174,36 @@ func (a *AzKeyVaultCertificateResourceSpecializer) CheckPlacement(ctx context.Co
 	return rv
 }
 
+func (a *AzKeyVaultCertificateResourceSpecializer) GetDestinationLabel(tfModel *CertificateModel) string {
+	destCertCoordinate := a.factory.GetDestinationVaultObjectCoordinate(tfModel.DestinationCert, "certificates")
+	return destCertCoordinate.GetLabel()
+}
+
+func (a *AzKeyVaultCertificateResourceSpecializer) IdentityOf(tfModel *CertificateModel, ciphertextUuid string) (AzKeyVaultObjectIdentityModel, error) {
+	return AzKeyVaultObjectIdentityOf(&tfModel.ConfidentialMaterialModel, ciphertextUuid)
+}
+
+func (a *AzKeyVaultCertificateResourceSpecializer) GetDestinationProvenanceTags(ctx context.Context, tfModel *CertificateModel) (map[string]string, error) {
+	destCertCoordinate := a.factory.GetDestinationVaultObjectCoordinate(tfModel.DestinationCert, "certificates")
+
+	certClient, err := a.factory.GetCertificateClient(destCertCoordinate.VaultName)
+	if err != nil {
+		return nil, err
+	} else if certClient == nil {
+		return nil, errors.New("certificates client returned is nil")
+	}
+
+	certState, err := certClient.GetCertificate(ctx, destCertCoordinate.Name, "", nil)
+	if err != nil {
+		if core.IsResourceNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return core.ProvenanceTagsOf(certState.Tags), nil
+}
+
 func (a *AzKeyVaultCertificateResourceSpecializer) DoRead(ctx context.Context, data *CertificateModel) (azcertificates.Certificate, resources.ResourceExistenceCheck, diag.Diagnostics) {
 	rv := diag.Diagnostics{}
 	// The key version was never created; nothing needs to be read here.
@@ -396,6 +427,7 @@ func NewCertificateResource() resource.Resource {
 	}
 
 	resourceSchema := schema.Schema{
+		Version:             1,
 		Description:         "Create a certificate in Azure KeyVault without revealing its value in state",
 		MarkdownDescription: certificateResourceMarkdownDescription,
 
@@ -404,11 +436,16 @@ func NewCertificateResource() resource.Resource {
 
 	keyVaultCertSpecializer := &AzKeyVaultCertificateResourceSpecializer{}
 
-	return &resources.ConfidentialGenericResource[CertificateModel, int, core.ConfidentialCertificateData, azcertificates.Certificate]{
+	return &resources.ConfidentialGenericResource[CertificateModel, AzKeyVaultObjectIdentityModel, core.ConfidentialCertificateData, azcertificates.Certificate]{
 		Specializer:    keyVaultCertSpecializer,
 		ImmutableRU:    keyVaultCertSpecializer,
 		ResourceName:   "keyvault_certificate",
 		ResourceSchema: resourceSchema,
+
+		ResourceIdentitySchema: AzKeyVaultObjectIdentityModelSchema(),
+		StateUpgraders: map[int64]resource.StateUpgrader{
+			0: resources.NewCarryOverStateUpgrader(certificateSchemaV0()),
+		},
 	}
 }
 
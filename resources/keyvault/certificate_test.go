@@ -563,3 +563,35 @@ func Test_NewCertificateEncryptorFunction_WillReturn(t *testing.T) {
 	fn := NewCertificateEncryptorFunction()
 	assert.NotNil(t, fn)
 }
+
+func Test_CAzKVCert_IdentityOf(t *testing.T) {
+	mdl := CertificateModel{}
+	mdl.Id = types.StringValue("https://cfg-vault.vault.azure.net/certificates/certName/certVersion")
+
+	r := AzKeyVaultCertificateResourceSpecializer{}
+	identity, err := r.IdentityOf(&mdl, "ciphertext-uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, AzKeyVaultObjectIdentityModel{
+		VaultName:      "cfg-vault",
+		ObjectType:     "certificates",
+		ObjectName:     "certName",
+		CiphertextUuid: "ciphertext-uuid",
+	}, identity)
+}
+
+func Test_CAzKVCert_UpgradeStateFromVersion0(t *testing.T) {
+	state, dg := upgradeStateFromJson(t, NewCertificateResource(), 0, `{
+		"id": "https://cfg-vault.vault.azure.net/certificates/certName/certVersion",
+		"content": "unit-test-ciphertext",
+		"destination_certificate": {"vault_name": "cfg-vault", "name": "certName"},
+		"version": "certVersion",
+		"thumbprint": "unit-test-thumbprint"
+	}`)
+	assert.False(t, dg.HasError())
+
+	mdl := CertificateModel{}
+	assert.False(t, state.Get(context.Background(), &mdl).HasError())
+	assert.Equal(t, "unit-test-ciphertext", mdl.EncryptedSecret.ValueString())
+	assert.Equal(t, "certVersion", mdl.CertificateVersion.ValueString())
+	assert.Equal(t, "unit-test-thumbprint", mdl.Thumbprint.ValueString())
+}
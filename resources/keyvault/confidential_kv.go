@@ -2,37 +2,61 @@ package keyvault
 
 import (
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
 	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 )
 
-// Models that could be used in combination with the Terraform identity.
-
+// AzKeyVaultObjectIdentityModel the identity of the Key Vault object managed by the resource. The identity
+// doesn't include the version of the object, as the updates of the object create its new versions.
 type AzKeyVaultObjectIdentityModel struct {
-	VaultName     string `tfsdk:"vault_name"`
-	ObjectType    string `tfsdk:"object_type"`
-	ObjectName    string `tfsdk:"object_name"`
-	ObjectVersion string `tfsdk:"object_version"`
+	VaultName      string `tfsdk:"vault_name"`
+	ObjectType     string `tfsdk:"object_type"`
+	ObjectName     string `tfsdk:"object_name"`
+	CiphertextUuid string `tfsdk:"ciphertext_uuid"`
 }
 
 func AzKeyVaultObjectIdentityModelSchema() identityschema.Schema {
 	return identityschema.Schema{
 		Attributes: map[string]identityschema.Attribute{
-			"vault_name":     schema.StringAttribute{},
-			"object_type":    schema.StringAttribute{},
-			"object_name":    schema.StringAttribute{},
-			"object_version": schema.StringAttribute{},
+			"vault_name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Name of the vault where the object is placed",
+			},
+			"object_type": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Type of the object: secrets, keys, or certificates",
+			},
+			"object_name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Name of the object in the vault",
+			},
+			resources.CiphertextUuidIdentityAttribute: identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description:       "Uuid of the ciphertext the object was created from",
+			},
 		},
 	}
 }
 
-func (a *AzKeyVaultObjectIdentityModel) AsCoordinate() core.AzKeyVaultObjectVersionedCoordinate {
-	return core.AzKeyVaultObjectVersionedCoordinate{
-		AzKeyVaultObjectCoordinate: core.AzKeyVaultObjectCoordinate{
-			VaultName: a.VaultName,
-			Name:      a.ObjectName,
-			Type:      a.ObjectType,
-		},
-		Version: a.ObjectVersion,
+// AzKeyVaultObjectIdentityOf the identity of the Key Vault object given the identifier of the object.
+func AzKeyVaultObjectIdentityOf(mdl *resources.ConfidentialMaterialModel, ciphertextUuid string) (AzKeyVaultObjectIdentityModel, error) {
+	coord, err := mdl.GetDestinationCoordinateFromId()
+	if err != nil {
+		return AzKeyVaultObjectIdentityModel{}, err
+	}
+
+	return AzKeyVaultObjectIdentityModel{
+		VaultName:      coord.VaultName,
+		ObjectType:     coord.Type,
+		ObjectName:     coord.Name,
+		CiphertextUuid: ciphertextUuid,
+	}, nil
+}
+
+func (a *AzKeyVaultObjectIdentityModel) AsCoordinate() core.AzKeyVaultObjectCoordinate {
+	return core.AzKeyVaultObjectCoordinate{
+		VaultName: a.VaultName,
+		Name:      a.ObjectName,
+		Type:      a.ObjectType,
 	}
 }
@@ -78,7 +78,7 @@ func (cm *KeyModel) ConvertToImportKeyParam(ctx context.Context) azkeys.ImportKe
 
 	params := azkeys.ImportKeyParameters{
 		KeyAttributes: &keyAttributes,
-		Tags:          cm.TagsAsPtr(),
+		Tags:          cm.AzTagsAsPtr(),
 		HSM:           cm.HSM.ValueBoolPointer(),
 		Key: &azkeys.JSONWebKey{
 			KeyOps: cm.GetKeyOperations(ctx),
@@ -97,7 +97,7 @@ func (cm *KeyModel) ConvertToUpdateKeyParam(ctx context.Context) azkeys.UpdateKe
 
 	params := azkeys.UpdateKeyParameters{
 		KeyAttributes: &keyAttributes,
-		Tags:          cm.TagsAsPtr(),
+		Tags:          cm.AzTagsAsPtr(),
 		KeyOps:        cm.GetKeyOperations(ctx),
 	}
 
@@ -140,7 +140,7 @@ func (cm *KeyModel) Accept(key azkeys.KeyBundle, diagnostics *diag.Diagnostics)
 		diagnostics.AddError("Conversion request for key having nil key identifier", "Every key must have a valid Key ID when converting")
 	}
 
-	cm.ConvertAzMap(key.Tags, &cm.Tags)
+	cm.AcceptAzTags(key.Tags)
 
 	if key.Attributes != nil {
 		cm.NotBefore = core.FormatTime(key.Attributes.NotBefore)
@@ -272,6 +272,36 @@ func (a *AzKeyVaultKeyResourceSpecializer) CheckPlacement(ctx context.Context, p
 	return rv
 }
 
+func (a *AzKeyVaultKeyResourceSpecializer) GetDestinationLabel(tfModel *KeyModel) string {
+	destKeyCoordinate := a.factory.GetDestinationVaultObjectCoordinate(tfModel.DestinationKey, "keys")
+	return destKeyCoordinate.GetLabel()
+}
+
+func (a *AzKeyVaultKeyResourceSpecializer) IdentityOf(tfModel *KeyModel, ciphertextUuid string) (AzKeyVaultObjectIdentityModel, error) {
+	return AzKeyVaultObjectIdentityOf(&tfModel.ConfidentialMaterialModel, ciphertextUuid)
+}
+
+func (a *AzKeyVaultKeyResourceSpecializer) GetDestinationProvenanceTags(ctx context.Context, tfModel *KeyModel) (map[string]string, error) {
+	destKeyCoordinate := a.factory.GetDestinationVaultObjectCoordinate(tfModel.DestinationKey, "keys")
+
+	keyClient, err := a.factory.GetKeysClient(destKeyCoordinate.VaultName)
+	if err != nil {
+		return nil, err
+	} else if keyClient == nil {
+		return nil, errors.New("keys client returned is nil")
+	}
+
+	keyState, err := keyClient.GetKey(ctx, destKeyCoordinate.Name, "", nil)
+	if err != nil {
+		if core.IsResourceNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return core.ProvenanceTagsOf(keyState.Tags), nil
+}
+
 func (a *AzKeyVaultKeyResourceSpecializer) DoRead(ctx context.Context, data *KeyModel) (azkeys.KeyBundle, resources.ResourceExistenceCheck, diag.Diagnostics) {
 	rv := diag.Diagnostics{}
 
@@ -527,6 +557,7 @@ func NewKeyResource() resource.Resource {
 	}
 
 	resourceSchema := schema.Schema{
+		Version:             1,
 		Description:         "Creates a key in Azure KeyVault without revealing its value in state",
 		MarkdownDescription: confidentialKeyResourceMarkdownDescription,
 
@@ -535,11 +566,16 @@ func NewKeyResource() resource.Resource {
 
 	kvKeySpecializer := &AzKeyVaultKeyResourceSpecializer{}
 
-	return &resources.ConfidentialGenericResource[KeyModel, int, jwk.Key, azkeys.KeyBundle]{
+	return &resources.ConfidentialGenericResource[KeyModel, AzKeyVaultObjectIdentityModel, jwk.Key, azkeys.KeyBundle]{
 		Specializer:    kvKeySpecializer,
 		ImmutableRU:    kvKeySpecializer,
 		ResourceName:   "keyvault_key",
 		ResourceSchema: resourceSchema,
+
+		ResourceIdentitySchema: AzKeyVaultObjectIdentityModelSchema(),
+		StateUpgraders: map[int64]resource.StateUpgrader{
+			0: resources.NewCarryOverStateUpgrader(keySchemaV0()),
+		},
 	}
 }
 
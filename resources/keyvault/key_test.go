@@ -956,3 +956,37 @@ func exerciseCreateKeyEncryptedMessageCycle(t *testing.T, key []byte, password s
 		hdr.PlacementConstraints[0],
 	)
 }
+
+func Test_CAzKVKey_IdentityOf(t *testing.T) {
+	mdl := KeyModel{}
+	mdl.Id = types.StringValue("https://cfg-vault.vault.azure.net/keys/keyName/keyVersion")
+
+	r := AzKeyVaultKeyResourceSpecializer{}
+	identity, err := r.IdentityOf(&mdl, "ciphertext-uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, AzKeyVaultObjectIdentityModel{
+		VaultName:      "cfg-vault",
+		ObjectType:     "keys",
+		ObjectName:     "keyName",
+		CiphertextUuid: "ciphertext-uuid",
+	}, identity)
+}
+
+func Test_CAzKVKey_UpgradeStateFromVersion0(t *testing.T) {
+	state, dg := upgradeStateFromJson(t, NewKeyResource(), 0, `{
+		"id": "https://cfg-vault.vault.azure.net/keys/keyName/keyVersion",
+		"content": "unit-test-ciphertext",
+		"hsm": false,
+		"key_opts": ["encrypt", "decrypt"],
+		"destination_key": {"vault_name": "cfg-vault", "name": "keyName"},
+		"key_version": "keyVersion"
+	}`)
+	assert.False(t, dg.HasError())
+
+	mdl := KeyModel{}
+	assert.False(t, state.Get(context.Background(), &mdl).HasError())
+	assert.Equal(t, "unit-test-ciphertext", mdl.EncryptedSecret.ValueString())
+	assert.Equal(t, "keyVersion", mdl.KeyVersion.ValueString())
+	assert.Equal(t, 2, len(mdl.KeyOperations.Elements()))
+	assert.True(t, mdl.PublicKeyPem.IsNull())
+}
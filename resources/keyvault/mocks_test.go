@@ -9,7 +9,13 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
 )
 
 type AZClientsFactoryMock struct {
@@ -49,6 +55,48 @@ func (m *AZClientsFactoryMock) GivenIsObjectTrackingEnabled(enableOpt bool) {
 	m.On("IsObjectTrackingEnabled").Return(enableOpt)
 }
 
+func (m *AZClientsFactoryMock) GetExpiryWarningWindow() time.Duration {
+	rv := m.Mock.Called()
+	return rv.Get(0).(time.Duration)
+}
+
+func (m *AZClientsFactoryMock) GivenExpiryWarningWindow(d time.Duration) {
+	m.On("GetExpiryWarningWindow").Return(d).Maybe()
+}
+
+func (m *AZClientsFactoryMock) IsProvenanceTaggingEnabled() bool {
+	rv := m.Mock.Called()
+	return rv.Bool(0)
+}
+
+func (m *AZClientsFactoryMock) GetProvenanceTags(header core.ConfidentialDataJsonHeader, ciphertext string) map[string]string {
+	rv := m.Mock.Called(header, ciphertext)
+	return rv.Get(0).(map[string]string)
+}
+
+func (m *AZClientsFactoryMock) GetFingerprintKey() []byte {
+	rv := m.Mock.Called()
+	return rv.Get(0).([]byte)
+}
+
+func (m *AZClientsFactoryMock) RevokeCiphertext(ctx context.Context, uuid string, reason string) (*core.CiphertextRevocation, error) {
+	rv := m.Mock.Called(ctx, uuid, reason)
+	return rv.Get(0).(*core.CiphertextRevocation), rv.Error(1)
+}
+
+func (m *AZClientsFactoryMock) GetCiphertextRevocation(ctx context.Context, uuid string) (*core.CiphertextRevocation, error) {
+	rv := m.Mock.Called(ctx, uuid)
+	return rv.Get(0).(*core.CiphertextRevocation), rv.Error(1)
+}
+
+func (m *AZClientsFactoryMock) GivenCiphertextIsNotRevoked() {
+	m.On("GetCiphertextRevocation", mock.Anything, mock.Anything).Return((*core.CiphertextRevocation)(nil), nil).Maybe()
+}
+
+func (m *AZClientsFactoryMock) GivenCiphertextIsRevoked(reason string) {
+	m.On("GetCiphertextRevocation", mock.Anything, mock.Anything).Return(&core.CiphertextRevocation{Reason: reason}, nil)
+}
+
 func (m *AZClientsFactoryMock) GivenGetSecretClientWillReturnNilClient(vaultAddr string) {
 	m.Mock.
 		On("GetSecretsClient", vaultAddr).
@@ -124,9 +172,9 @@ func (m *AZClientsFactoryMock) GetCertificateClient(vaultName string) (core.AzCe
 	return rvCl, rv.Error(1)
 }
 
-func (m *AZClientsFactoryMock) GetMergedWrappingKeyCoordinate(ctx context.Context, param *core.WrappingKeyCoordinateModel, diag *diag.Diagnostics) core.WrappingKeyCoordinate {
-	rv := m.Mock.Called(ctx, param, diag)
-	return rv.Get(0).(core.WrappingKeyCoordinate)
+func (m *AZClientsFactoryMock) GetMergedWrappingKeyCoordinate(ctx context.Context, param *core.WrappingKeyCoordinateModel) (core.WrappingKeyCoordinate, error) {
+	rv := m.Called(ctx, param)
+	return rv.Get(0).(core.WrappingKeyCoordinate), rv.Error(1)
 }
 
 func (m *AZClientsFactoryMock) EnsureCanPlaceLabelledObjectAt(ctx context.Context, pc []core.ProviderConstraint, pl []core.PlacementConstraint, tfResourceType string, targetCoord core.LabelledObject, diagnostics *diag.Diagnostics) {
@@ -143,11 +191,16 @@ func (m *AZClientsFactoryMock) IsObjectIdTracked(ctx context.Context, id string)
 	return rv.Get(0).(bool), rv.Error(1)
 }
 
-func (m *AZClientsFactoryMock) TrackObjectId(ctx context.Context, id string) error {
-	rv := m.Mock.Called(ctx, id)
+func (m *AZClientsFactoryMock) TrackObjectId(ctx context.Context, id string, destination string) error {
+	rv := m.Mock.Called(ctx, id, destination)
 	return rv.Error(0)
 }
 
+func (m *AZClientsFactoryMock) ListTrackedObjects(ctx context.Context, uuids []string) ([]core.TrackedObject, error) {
+	rv := m.Mock.Called(ctx, uuids)
+	return rv.Get(0).([]core.TrackedObject), rv.Error(1)
+}
+
 func (m *AZClientsFactoryMock) GetTackedObjectUses(ctx context.Context, id string) (int, error) {
 	rv := m.Mock.Called(ctx, id)
 	return rv.Get(0).(int), rv.Error(1)
@@ -158,6 +211,10 @@ func (m *AZClientsFactoryMock) GetDecrypterFor(ctx context.Context, coord *core.
 	return rv.Get(0).(core.RSADecrypter)
 }
 
+func (m *AZClientsFactoryMock) RecordAuditEvent(ctx context.Context, event core.AuditEvent, coord *core.WrappingKeyCoordinateModel, diagnostics *diag.Diagnostics) {
+	m.Mock.Called(ctx, event, coord, diagnostics)
+}
+
 func (m *AZClientsFactoryMock) IsObjectTrackingEnabled() bool {
 	rv := m.Mock.Called()
 	return rv.Get(0).(bool)
@@ -323,6 +380,11 @@ func (k *KeysClientMock) ImportKey(ctx context.Context, name string, parameters
 	return args.Get(0).(azkeys.ImportKeyResponse), args.Error(1)
 }
 
+func (k *KeysClientMock) CreateKey(ctx context.Context, name string, parameters azkeys.CreateKeyParameters, options *azkeys.CreateKeyOptions) (azkeys.CreateKeyResponse, error) {
+	args := k.Called(ctx, name, parameters, options)
+	return args.Get(0).(azkeys.CreateKeyResponse), args.Error(1)
+}
+
 func (k *KeysClientMock) Decrypt(ctx context.Context, name string, version string, parameters azkeys.KeyOperationParameters, options *azkeys.DecryptOptions) (azkeys.DecryptResponse, error) {
 	args := k.Called(ctx, name, version, parameters, options)
 	return args.Get(0).(azkeys.DecryptResponse), args.Error(1)
@@ -398,3 +460,29 @@ func (c *CertificateClientMock) UpdateCertificate(ctx context.Context, name stri
 	args := c.Called(ctx, name, version, parameters, options)
 	return args.Get(0).(azcertificates.UpdateCertificateResponse), args.Error(1)
 }
+
+// upgradeStateFromJson upgrades the state of the resource as Terraform would have stored it with the prior version
+// of the resource schema.
+func upgradeStateFromJson(t *testing.T, r resource.Resource, priorVersion int64, stateJson string) (tfsdk.State, diag.Diagnostics) {
+	ctx := context.Background()
+
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	upgrader, ok := r.(resource.ResourceWithUpgradeState).UpgradeState(ctx)[priorVersion]
+	assert.True(t, ok, "resource does not upgrade state of version %d", priorVersion)
+
+	// The state is read strictly, so that an attribute of the prior state missing in the prior schema fails the test
+	priorValue, err := tftypes.ValueFromJSON([]byte(stateJson), upgrader.PriorSchema.Type().TerraformType(ctx))
+	assert.Nil(t, err)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: priorValue},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)},
+	}
+
+	upgrader.StateUpgrader(ctx, req, &resp)
+	return resp.State, resp.Diagnostics
+}
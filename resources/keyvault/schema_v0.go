@@ -0,0 +1,59 @@
+package keyvault
+
+import (
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The schemas of the Key Vault resources as these were before the schemas declared their version. These are
+// frozen: the state written by the earlier releases of the provider is read with these schemas before it is
+// upgraded to the current version.
+
+func destinationObjectSchemaV0() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Required: true,
+		Attributes: map[string]schema.Attribute{
+			"vault_name": schema.StringAttribute{Optional: true},
+			"name":       schema.StringAttribute{Required: true},
+		},
+	}
+}
+
+func secretSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: resources.WrappedAzKeyVaultObjectConfidentialMaterialModelSchemaV0(map[string]schema.Attribute{
+			"content_type":       schema.StringAttribute{Optional: true, Computed: true},
+			"secret_version":     schema.StringAttribute{Computed: true},
+			"destination_secret": destinationObjectSchemaV0(),
+		}),
+	}
+}
+
+func keySchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: resources.WrappedAzKeyVaultObjectConfidentialMaterialModelSchemaV0(map[string]schema.Attribute{
+			"hsm":                schema.BoolAttribute{Optional: true},
+			"key_opts":           schema.SetAttribute{Required: true, ElementType: types.StringType},
+			"key_version":        schema.StringAttribute{Computed: true},
+			"public_key_openssh": schema.StringAttribute{Computed: true},
+			"public_key_pem":     schema.StringAttribute{Computed: true},
+			"destination_key":    destinationObjectSchemaV0(),
+		}),
+	}
+}
+
+func certificateSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: resources.WrappedAzKeyVaultObjectConfidentialMaterialModelSchemaV0(map[string]schema.Attribute{
+			"certificate_data":        schema.StringAttribute{Computed: true},
+			"certificate_data_base64": schema.StringAttribute{Computed: true},
+			"secret_id":               schema.StringAttribute{Computed: true},
+			"thumbprint":              schema.StringAttribute{Computed: true},
+			"version":                 schema.StringAttribute{Computed: true},
+			"versionless_id":          schema.StringAttribute{Computed: true},
+			"versionless_secret_id":   schema.StringAttribute{Computed: true},
+			"destination_certificate": destinationObjectSchemaV0(),
+		}),
+	}
+}
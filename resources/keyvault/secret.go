@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/rsa"
 	_ "embed"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
@@ -40,7 +42,7 @@ func (cm *SecretModel) ConvertToSetSecretParam(data *SecretModel) azsecrets.SetS
 
 	params := azsecrets.SetSecretParameters{
 		ContentType:      data.ContentTypeAsPtr(),
-		Tags:             data.TagsAsPtr(),
+		Tags:             data.AzTagsAsPtr(),
 		SecretAttributes: &secretAttributes,
 	}
 
@@ -56,7 +58,7 @@ func (cm *SecretModel) ConvertToUpdateSecretPropertiesParam(data *SecretModel) a
 
 	params := azsecrets.UpdateSecretPropertiesParameters{
 		ContentType:      data.ContentTypeAsPtr(),
-		Tags:             data.TagsAsPtr(),
+		Tags:             data.AzTagsAsPtr(),
 		SecretAttributes: &secretAttributes,
 	}
 
@@ -85,7 +87,7 @@ func (cm *SecretModel) Accept(secret azsecrets.Secret) {
 	cm.Id = types.StringValue(string(*secret.ID))
 
 	cm.ConvertAzString(secret.ContentType, &cm.ContentType)
-	cm.ConvertAzMap(secret.Tags, &cm.Tags)
+	cm.AcceptAzTags(secret.Tags)
 
 	if secret.Attributes != nil {
 		cm.NotBefore = core.FormatTime(secret.Attributes.NotBefore)
@@ -131,6 +133,36 @@ func (a *AzKeyVaultSecretResourceSpecializer) CheckPlacement(ctx context.Context
 	return rv
 }
 
+func (a *AzKeyVaultSecretResourceSpecializer) GetDestinationLabel(tfModel *SecretModel) string {
+	destSecretCoordinate := a.factory.GetDestinationVaultObjectCoordinate(tfModel.DestinationSecret, "secrets")
+	return destSecretCoordinate.GetLabel()
+}
+
+func (a *AzKeyVaultSecretResourceSpecializer) IdentityOf(tfModel *SecretModel, ciphertextUuid string) (AzKeyVaultObjectIdentityModel, error) {
+	return AzKeyVaultObjectIdentityOf(&tfModel.ConfidentialMaterialModel, ciphertextUuid)
+}
+
+func (a *AzKeyVaultSecretResourceSpecializer) GetDestinationProvenanceTags(ctx context.Context, tfModel *SecretModel) (map[string]string, error) {
+	destSecretCoordinate := a.factory.GetDestinationVaultObjectCoordinate(tfModel.DestinationSecret, "secrets")
+
+	secretClient, err := a.factory.GetSecretsClient(destSecretCoordinate.VaultName)
+	if err != nil {
+		return nil, err
+	} else if secretClient == nil {
+		return nil, errors.New("secrets client returned is nil")
+	}
+
+	secretState, err := secretClient.GetSecret(ctx, destSecretCoordinate.Name, "", nil)
+	if err != nil {
+		if core.IsResourceNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return core.ProvenanceTagsOf(secretState.Tags), nil
+}
+
 func (a *AzKeyVaultSecretResourceSpecializer) DoRead(ctx context.Context, data *SecretModel) (azsecrets.Secret, resources.ResourceExistenceCheck, diag.Diagnostics) {
 	rv := diag.Diagnostics{}
 	// The secret version was never created; nothing needs to be read here.
@@ -182,6 +214,38 @@ func (a *AzKeyVaultSecretResourceSpecializer) DoRead(ctx context.Context, data *
 	return secretState.Secret, resources.ResourceExists, rv
 }
 
+// VerifyMovedObjectDestination verifies that the secret moved into the resource is in the vault, and has the name,
+// that the configuration gives; the vault defaults to the vault of the wrapping key.
+func (a *AzKeyVaultSecretResourceSpecializer) VerifyMovedObjectDestination(tfModel *SecretModel) error {
+	movedCoordinate, err := tfModel.GetDestinationCoordinateFromId()
+	if err != nil {
+		return err
+	}
+
+	destSecretCoordinate := a.factory.GetDestinationVaultObjectCoordinate(tfModel.DestinationSecret, "secrets")
+	if !strings.EqualFold(movedCoordinate.VaultName, destSecretCoordinate.VaultName) || !strings.EqualFold(movedCoordinate.Name, destSecretCoordinate.Name) {
+		return fmt.Errorf("secret %s of vault %s was moved, whereas the configuration places secret %s in vault %s",
+			movedCoordinate.Name, movedCoordinate.VaultName, destSecretCoordinate.Name, destSecretCoordinate.VaultName)
+	}
+
+	return nil
+}
+
+// ConfidentialValueOf returns the value of the secret
+func (a *AzKeyVaultSecretResourceSpecializer) ConfidentialValueOf(plainData core.ConfidentialStringData) []byte {
+	return []byte(plainData.GetStingData())
+}
+
+// DoReadConfidentialValue reads the secret version together with its value
+func (a *AzKeyVaultSecretResourceSpecializer) DoReadConfidentialValue(ctx context.Context, data *SecretModel) (azsecrets.Secret, []byte, resources.ResourceExistenceCheck, diag.Diagnostics) {
+	secret, check, rv := a.DoRead(ctx, data)
+	if check != resources.ResourceExists || secret.Value == nil {
+		return secret, nil, check, rv
+	}
+
+	return secret, []byte(*secret.Value), check, rv
+}
+
 func (a *AzKeyVaultSecretResourceSpecializer) DoCreate(ctx context.Context, data *SecretModel, unwrappedData core.ConfidentialStringData) (azsecrets.Secret, diag.Diagnostics) {
 	rv := diag.Diagnostics{}
 	destSecretCoordinate := a.factory.GetDestinationVaultObjectCoordinate(data.DestinationSecret, "secrets")
@@ -324,8 +388,10 @@ func NewSecretResource() resource.Resource {
 					Optional:    true,
 					Description: "Vault where the secret needs to be stored. If omitted, defaults to the vault containing the wrapping key",
 
+					// The secret moved from azurerm records the vault where it is; the adoption checks that
+					// the vault given in the configuration (or the default vault) is the same.
 					PlanModifiers: []planmodifier.String{
-						stringplanmodifier.RequiresReplace(),
+						resources.StringRequiresReplaceUnlessMoved(),
 					},
 				},
 				"name": schema.StringAttribute{
@@ -348,6 +414,7 @@ func NewSecretResource() resource.Resource {
 	}
 
 	resourceSchema := schema.Schema{
+		Version:             1,
 		Description:         "Creates a secret in Azure KeyVault without revealing its value in state",
 		MarkdownDescription: secretResourceMarkdownDescription,
 
@@ -356,11 +423,19 @@ func NewSecretResource() resource.Resource {
 
 	kvSecretSpecializer := &AzKeyVaultSecretResourceSpecializer{}
 
-	return &resources.ConfidentialGenericResource[SecretModel, int, core.ConfidentialStringData, azsecrets.Secret]{
+	return &resources.ConfidentialGenericResource[SecretModel, AzKeyVaultObjectIdentityModel, core.ConfidentialStringData, azsecrets.Secret]{
 		Specializer:    kvSecretSpecializer,
 		ImmutableRU:    kvSecretSpecializer,
 		ResourceName:   "keyvault_secret",
 		ResourceSchema: resourceSchema,
+
+		ResourceIdentitySchema: AzKeyVaultObjectIdentityModelSchema(),
+		StateUpgraders: map[int64]resource.StateUpgrader{
+			0: resources.NewCarryOverStateUpgrader(secretSchemaV0()),
+		},
+		StateMovers: []resource.StateMover{
+			resources.NewStateMover(resources.AzureRMProviderAddress, AzureRMKeyVaultSecretTypeName, MoveAzureRMKeyVaultSecret),
+		},
 	}
 }
 
@@ -0,0 +1,71 @@
+package keyvault
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// AzureRMKeyVaultSecretTypeName the type of the azurerm resource managing the Key Vault secret
+const AzureRMKeyVaultSecretTypeName = "azurerm_key_vault_secret"
+
+// AzureRMKeyVaultSecretState the attributes of the azurerm_key_vault_secret state moved into the secret resource.
+// The value of the secret is deliberately not read.
+type AzureRMKeyVaultSecretState struct {
+	Id             string            `json:"id"`
+	Name           string            `json:"name"`
+	Version        string            `json:"version"`
+	ContentType    *string           `json:"content_type"`
+	Tags           map[string]string `json:"tags"`
+	NotBeforeDate  *string           `json:"not_before_date"`
+	ExpirationDate *string           `json:"expiration_date"`
+}
+
+// MoveAzureRMKeyVaultSecret moves the state of azurerm_key_vault_secret into the secret model. The moved secret
+// keeps the version azurerm has created.
+func MoveAzureRMKeyVaultSecret(ctx context.Context, src AzureRMKeyVaultSecretState, mdl *SecretModel) diag.Diagnostics {
+	rv := diag.Diagnostics{}
+
+	coord := core.AzKeyVaultObjectVersionedCoordinate{}
+	if err := coord.FromId(src.Id); err != nil {
+		rv.AddError("Secret identifier does not conform to the expected format", err.Error())
+		return rv
+	}
+
+	mdl.Id = types.StringValue(src.Id)
+	mdl.DestinationSecret = core.AzKeyVaultObjectCoordinateModel{
+		VaultName: types.StringValue(coord.VaultName),
+		Name:      types.StringValue(coord.Name),
+	}
+	mdl.SecretVersion = types.StringValue(coord.Version)
+	mdl.ContentType = types.StringPointerValue(src.ContentType)
+	mdl.NotBefore = formatAzureRMTime(src.NotBeforeDate, &rv)
+	mdl.NotAfter = formatAzureRMTime(src.ExpirationDate, &rv)
+
+	if len(src.Tags) > 0 {
+		tags, tagsDg := types.MapValueFrom(ctx, types.StringType, src.Tags)
+		rv.Append(tagsDg...)
+		mdl.Tags = tags
+	}
+
+	return rv
+}
+
+// formatAzureRMTime converts the RFC 3339 time of the azurerm state to the time format of this provider
+func formatAzureRMTime(v *string, dg *diag.Diagnostics) types.String {
+	if v == nil || len(*v) == 0 {
+		return types.StringNull()
+	}
+
+	t, err := time.Parse(time.RFC3339, *v)
+	if err != nil {
+		dg.AddError("Date does not conform to the expected format", fmt.Sprintf("Date %s is not an RFC 3339 date: %s", *v, err.Error()))
+		return types.StringNull()
+	}
+
+	return core.FormatTime(&t)
+}
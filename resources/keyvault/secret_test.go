@@ -77,6 +77,29 @@ func Test_CSM_Accept(t *testing.T) {
 	assert.Equal(t, 1, len(receivedTags))
 }
 
+func Test_CSM_ProvenanceTags(t *testing.T) {
+	mdl := SecretModel{}
+	mdl.Tags = resources.ConvertStringPtrMapToTerraform(map[string]*string{"a": to.Ptr("b")})
+	mdl.ProvenanceTags = types.MapUnknown(types.StringType)
+
+	assert.True(t, mdl.IsProvenanceTagsUnknown())
+	mdl.SetProvenanceTags(map[string]string{core.ProvenanceUuidTag: "unit-test-uuid"})
+
+	params := mdl.ConvertToSetSecretParam(&mdl)
+	assert.Equal(t, 2, len(params.Tags))
+	assert.Equal(t, "unit-test-uuid", *params.Tags[core.ProvenanceUuidTag])
+
+	accepted := SecretModel{}
+	accepted.Accept(azsecrets.Secret{
+		ID:   to.Ptr(azsecrets.ID("https://myvaultname.vault.azure.net/secrets/secret1053998307/b86c2e6ad9054f4abf69cc185b99aa60")),
+		Tags: params.Tags,
+	})
+
+	assert.Equal(t, map[string]string{"a": "b"}, accepted.TagsAsStr())
+	assert.Equal(t, 1, len(accepted.ProvenanceTags.Elements()))
+	assert.Equal(t, types.StringValue("unit-test-uuid"), accepted.ProvenanceTags.Elements()[core.ProvenanceUuidTag])
+}
+
 func Test_CAzVSR_Metadata(t *testing.T) {
 	r := NewSecretResource()
 	req := resource.MetadataRequest{
@@ -640,3 +663,166 @@ func Test_CreateSecretEncryptedMessage_EncryptedMessage(t *testing.T) {
 		hdr.PlacementConstraints[0],
 	)
 }
+
+func Test_CAzVSR_IdentityOf(t *testing.T) {
+	mdl := SecretModel{}
+	mdl.Id = types.StringValue("https://cfg-vault.vault.azure.net/secrets/secretName/secretVersion")
+
+	r := AzKeyVaultSecretResourceSpecializer{}
+	identity, err := r.IdentityOf(&mdl, "ciphertext-uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, "cfg-vault", identity.VaultName)
+	assert.Equal(t, "secrets", identity.ObjectType)
+	assert.Equal(t, "secretName", identity.ObjectName)
+	assert.Equal(t, "ciphertext-uuid", identity.CiphertextUuid)
+}
+
+func Test_CAzVSR_IdentityOf_IfIdIsMalformed(t *testing.T) {
+	mdl := SecretModel{}
+	mdl.Id = types.StringValue("https://cfg-vault.vault.azure.net/secrets")
+
+	r := AzKeyVaultSecretResourceSpecializer{}
+	_, err := r.IdentityOf(&mdl, "ciphertext-uuid")
+	assert.NotNil(t, err)
+}
+
+func Test_CAzVSR_IdentitySchema(t *testing.T) {
+	r := NewSecretResource().(resource.ResourceWithIdentity)
+	resp := resource.IdentitySchemaResponse{}
+
+	r.IdentitySchema(context.Background(), resource.IdentitySchemaRequest{}, &resp)
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.Contains(t, resp.IdentitySchema.Attributes, "vault_name")
+	assert.Contains(t, resp.IdentitySchema.Attributes, "object_name")
+	assert.Contains(t, resp.IdentitySchema.Attributes, resources.CiphertextUuidIdentityAttribute)
+}
+
+func Test_CAzVSR_UpgradeStateFromVersion0(t *testing.T) {
+	// The attributes of the secret resource as the provider wrote these before the schema declared its version
+	state, dg := upgradeStateFromJson(t, NewSecretResource(), 0, `{
+		"content": "unit-test-ciphertext",
+		"content_type": "text/plain",
+		"destination_secret": {"name": "secretName", "vault_name": "cfg-vault"},
+		"enabled": true,
+		"id": "https://cfg-vault.vault.azure.net/secrets/secretName/secretVersion",
+		"not_after_date": null,
+		"not_before_date": "2026-01-31T10:00:00Z",
+		"secret_version": "secretVersion",
+		"tags": {"env": "unit-test"},
+		"wrapping_key": null
+	}`)
+	assert.False(t, dg.HasError())
+
+	mdl := SecretModel{}
+	assert.False(t, state.Get(context.Background(), &mdl).HasError())
+	assert.Equal(t, "https://cfg-vault.vault.azure.net/secrets/secretName/secretVersion", mdl.Id.ValueString())
+	assert.Equal(t, "unit-test-ciphertext", mdl.EncryptedSecret.ValueString())
+	assert.Equal(t, "text/plain", mdl.ContentType.ValueString())
+	assert.True(t, mdl.Enabled.ValueBool())
+	assert.Equal(t, "cfg-vault", mdl.DestinationSecret.VaultName.ValueString())
+	assert.Equal(t, "secretVersion", mdl.SecretVersion.ValueString())
+	assert.Equal(t, 1, len(mdl.Tags.Elements()))
+	assert.Equal(t, "2026-01-31T10:00:00Z", mdl.NotBefore.ValueString())
+	assert.True(t, mdl.NotAfter.IsNull())
+	assert.True(t, mdl.ProvenanceTags.IsNull())
+	assert.True(t, mdl.Timeouts.IsNull())
+	assert.Nil(t, mdl.WrappingKeyCoordinate)
+}
+
+func Test_CAzVSR_DoReadConfidentialValue(t *testing.T) {
+	secretClient := SecretClientMock{}
+	secretClient.GivenGetSecret("secretName", "secretVersion", "unit-test-value")
+
+	factoryMock := AZClientsFactoryMock{}
+	factoryMock.GivenGetSecretClientWillReturn("unit-test-vault", &secretClient)
+
+	c := AzKeyVaultSecretResourceSpecializer{}
+	c.factory = &factoryMock
+
+	mdl := GivenTypicalConfidentialSecretModel()
+
+	_, value, resourceExistsCheck, dg := c.DoReadConfidentialValue(context.Background(), &mdl)
+	assert.False(t, dg.HasError())
+	assert.Equal(t, resources.ResourceExists, resourceExistsCheck)
+	assert.Equal(t, []byte("unit-test-value"), value)
+	assert.Equal(t, value, c.ConfidentialValueOf(givenVersionedStringConfidentialDataFromString("unit-test-value", SecretObjectType)))
+
+	secretClient.AssertExpectations(t)
+	factoryMock.AssertExpectations(t)
+}
+
+func Test_CAzVSR_MoveAzureRMKeyVaultSecret(t *testing.T) {
+	mdl := SecretModel{}
+	dg := MoveAzureRMKeyVaultSecret(context.Background(), AzureRMKeyVaultSecretState{
+		Id:             "https://cfg-vault.vault.azure.net/secrets/secretName/secretVersion",
+		Name:           "secretName",
+		Version:        "secretVersion",
+		ContentType:    to.Ptr("text/plain"),
+		Tags:           map[string]string{"env": "unit-test"},
+		NotBeforeDate:  to.Ptr("2026-01-31T10:00:00Z"),
+		ExpirationDate: to.Ptr("2027-01-31T10:00:00+01:00"),
+	}, &mdl)
+
+	assert.False(t, dg.HasError())
+	assert.Equal(t, "https://cfg-vault.vault.azure.net/secrets/secretName/secretVersion", mdl.Id.ValueString())
+	assert.Equal(t, "cfg-vault", mdl.DestinationSecret.VaultName.ValueString())
+	assert.Equal(t, "secretName", mdl.DestinationSecret.Name.ValueString())
+	assert.Equal(t, "secretVersion", mdl.SecretVersion.ValueString())
+	assert.Equal(t, "text/plain", mdl.ContentType.ValueString())
+	assert.Equal(t, "2026-01-31T10:00:00Z", mdl.NotBefore.ValueString())
+	assert.Equal(t, "2027-01-31T09:00:00Z", mdl.NotAfter.ValueString())
+	assert.Equal(t, 1, len(mdl.Tags.Elements()))
+}
+
+func Test_CAzVSR_MoveAzureRMKeyVaultSecret_MalformedId(t *testing.T) {
+	mdl := SecretModel{}
+	dg := MoveAzureRMKeyVaultSecret(context.Background(), AzureRMKeyVaultSecretState{
+		Id: "https://cfg-vault.vault.azure.net/secrets/secretName",
+	}, &mdl)
+
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Secret identifier does not conform to the expected format", dg[0].Summary())
+}
+
+func Test_CAzVSR_VerifyMovedObjectDestination(t *testing.T) {
+	mdl := SecretModel{}
+	dg := MoveAzureRMKeyVaultSecret(context.Background(), AzureRMKeyVaultSecretState{
+		Id:      "https://moved-vault.vault.azure.net/secrets/secretName/secretVersion",
+		Name:    "secretName",
+		Version: "secretVersion",
+	}, &mdl)
+	assert.False(t, dg.HasError())
+
+	factory := AZClientsFactoryMock{}
+	factory.GivenGetDestinationVaultObjectCoordinate("moved-vault", "secrets", "secretName")
+
+	r := AzKeyVaultSecretResourceSpecializer{}
+	r.factory = &factory
+
+	assert.Nil(t, r.VerifyMovedObjectDestination(&mdl))
+	factory.AssertExpectations(t)
+}
+
+func Test_CAzVSR_VerifyMovedObjectDestination_FromOtherVault(t *testing.T) {
+	mdl := SecretModel{}
+	dg := MoveAzureRMKeyVaultSecret(context.Background(), AzureRMKeyVaultSecretState{
+		Id:      "https://moved-vault.vault.azure.net/secrets/secretName/secretVersion",
+		Name:    "secretName",
+		Version: "secretVersion",
+	}, &mdl)
+	assert.False(t, dg.HasError())
+
+	// The configuration omits the vault; the default vault differs from the vault of the moved secret
+	mdl.DestinationSecret.VaultName = types.StringNull()
+
+	factory := AZClientsFactoryMock{}
+	factory.GivenGetDestinationVaultObjectCoordinate("cfg-vault", "secrets", "secretName")
+
+	r := AzKeyVaultSecretResourceSpecializer{}
+	r.factory = &factory
+
+	err := r.VerifyMovedObjectDestination(&mdl)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "secret secretName of vault moved-vault was moved")
+	factory.AssertExpectations(t)
+}
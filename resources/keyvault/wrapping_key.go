@@ -0,0 +1,480 @@
+package keyvault
+
+import (
+	"context"
+	"crypto/rsa"
+	_ "embed"
+	"fmt"
+	"math/big"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// WrappingKeyOperations the only operations the wrapping key may permit: the wrapping key must not encrypt
+// nor sign anything on behalf of the callers of Key Vault.
+var WrappingKeyOperations = []string{
+	string(azkeys.KeyOperationDecrypt),
+	string(azkeys.KeyOperationUnwrapKey),
+}
+
+// WrappingKeyModel the model of the wrapping key (KEK) managed in Key Vault
+type WrappingKeyModel struct {
+	Id                types.String `tfsdk:"id"`
+	VaultName         types.String `tfsdk:"vault_name"`
+	Name              types.String `tfsdk:"name"`
+	KeySize           types.Int64  `tfsdk:"key_size"`
+	KeyOperations     types.Set    `tfsdk:"key_operations"`
+	Tags              types.Map    `tfsdk:"tags"`
+	RotateWhenChanged types.Map    `tfsdk:"rotate_when_changed"`
+
+	Version      types.String `tfsdk:"version"`
+	KeyId        types.String `tfsdk:"key_id"`
+	PublicKeyPem types.String `tfsdk:"public_key_pem"`
+	Thumbprint   types.String `tfsdk:"thumbprint"`
+}
+
+func (m *WrappingKeyModel) GetKeyOperations(ctx context.Context) []*azkeys.KeyOperation {
+	var ops []string
+	if !m.KeyOperations.IsNull() && !m.KeyOperations.IsUnknown() {
+		m.KeyOperations.ElementsAs(ctx, &ops, false)
+	}
+
+	rv := make([]*azkeys.KeyOperation, len(ops))
+	for i, op := range ops {
+		rv[i] = to.Ptr(azkeys.KeyOperation(op))
+	}
+	return rv
+}
+
+func (m *WrappingKeyModel) TagsAsPtr() map[string]*string {
+	if m.Tags.IsNull() || m.Tags.IsUnknown() || len(m.Tags.Elements()) == 0 {
+		return nil
+	}
+
+	rv := map[string]*string{}
+	for k, v := range m.Tags.Elements() {
+		if strAttr, ok := v.(types.String); ok {
+			rv[k] = strAttr.ValueStringPointer()
+		}
+	}
+	return rv
+}
+
+// CreateKeyParameters the parameters creating the HSM-backed RSA key that cannot be exported and permits
+// only the operations of the configuration.
+func (m *WrappingKeyModel) CreateKeyParameters(ctx context.Context) azkeys.CreateKeyParameters {
+	return azkeys.CreateKeyParameters{
+		Kty:     to.Ptr(azkeys.KeyTypeRSAHSM),
+		KeySize: to.Ptr(int32(m.KeySize.ValueInt64())),
+		KeyOps:  m.GetKeyOperations(ctx),
+		KeyAttributes: &azkeys.KeyAttributes{
+			Enabled:    to.Ptr(true),
+			Exportable: to.Ptr(false),
+		},
+		Tags: m.TagsAsPtr(),
+	}
+}
+
+// Accept reads the key version from Key Vault into the model
+func (m *WrappingKeyModel) Accept(ctx context.Context, key azkeys.KeyBundle, dg *diag.Diagnostics) {
+	if key.Key == nil || key.Key.KID == nil {
+		dg.AddError("Incomplete key", "Key Vault returned the key without its identifier. This is a provider bug. Please report this")
+		return
+	}
+
+	coord := core.AzKeyVaultObjectVersionedCoordinate{}
+	if err := coord.FromId(string(*key.Key.KID)); err != nil {
+		dg.AddError("Key identifier does not conform to the expected format", err.Error())
+		return
+	}
+
+	if key.Key.Kty == nil || (*key.Key.Kty != azkeys.KeyTypeRSA && *key.Key.Kty != azkeys.KeyTypeRSAHSM) {
+		dg.AddError("Wrapping key is not an RSA key", fmt.Sprintf("Key %s cannot be used to wrap the content encryption keys", *key.Key.KID))
+		return
+	}
+
+	publicKey := &rsa.PublicKey{
+		N: big.NewInt(0).SetBytes(key.Key.N),
+		E: int(big.NewInt(0).SetBytes(key.Key.E).Uint64()),
+	}
+
+	pemData, pemErr := core.PublicKeyToPEM(publicKey)
+	if pemErr != nil {
+		dg.AddError("Public key cannot be encoded", pemErr.Error())
+		return
+	}
+	thumbprint, tpErr := core.PublicKeyThumbprint(publicKey)
+	if tpErr != nil {
+		dg.AddError("Public key thumbprint cannot be computed", tpErr.Error())
+		return
+	}
+
+	var keyOps []attr.Value
+	for _, op := range key.Key.KeyOps {
+		if op != nil {
+			keyOps = append(keyOps, types.StringValue(string(*op)))
+		}
+	}
+
+	m.Id = types.StringValue(coord.VersionlessId())
+	m.VaultName = types.StringValue(coord.VaultName)
+	m.Name = types.StringValue(coord.Name)
+	m.Version = types.StringValue(coord.Version)
+	m.KeyId = types.StringValue(string(*key.Key.KID))
+	m.KeySize = types.Int64Value(int64(publicKey.N.BitLen()))
+	m.KeyOperations = types.SetValueMust(types.StringType, keyOps)
+	m.PublicKeyPem = types.StringValue(string(pemData))
+	m.Thumbprint = types.StringValue(thumbprint)
+
+	if len(key.Tags) > 0 || (!m.Tags.IsNull() && !m.Tags.IsUnknown()) {
+		m.Tags = resources.ConvertStringPtrMapToTerraform(key.Tags)
+	} else {
+		m.Tags = types.MapNull(types.StringType)
+	}
+
+	if key.Attributes != nil && key.Attributes.Exportable != nil && *key.Attributes.Exportable {
+		dg.AddWarning("Wrapping key is exportable", fmt.Sprintf("Key %s can be exported from Key Vault; the ciphertexts it wraps are only as safe as the exported copies of the key", *key.Key.KID))
+	}
+}
+
+// requiresRotation whether the plan requires a new version of the key
+func (m *WrappingKeyModel) requiresRotation(state *WrappingKeyModel) bool {
+	return !m.KeySize.Equal(state.KeySize) || !m.RotateWhenChanged.Equal(state.RotateWhenChanged)
+}
+
+type WrappingKeyResource struct {
+	resources.ConfidentialResourceBase
+}
+
+func (r *WrappingKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wrapping_key"
+}
+
+//go:embed wrapping_key.md
+var wrappingKeyResourceMarkdownDescription string
+
+func (r *WrappingKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	defaultKeyOps := make([]attr.Value, len(WrappingKeyOperations))
+	for i, op := range WrappingKeyOperations {
+		defaultKeyOps[i] = types.StringValue(op)
+	}
+
+	resp.Schema = schema.Schema{
+		Description:         "Creates and rotates the wrapping key (KEK) in Key Vault",
+		MarkdownDescription: wrappingKeyResourceMarkdownDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Versionless identifier of the wrapping key",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"vault_name": schema.StringAttribute{
+				Description: "Vault where the wrapping key is created. The vault must support HSM-backed keys",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the wrapping key",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key_size": schema.Int64Attribute{
+				Description:         "Size of the RSA key: 3072 or 4096 bits. Defaults to 4096. Changing the size rotates the key",
+				MarkdownDescription: "Size of the RSA key: `3072` or `4096` bits. Defaults to `4096`. Changing the size rotates the key.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(4096),
+				Validators: []validator.Int64{
+					int64validator.OneOf(3072, 4096),
+				},
+			},
+			"key_operations": schema.SetAttribute{
+				Description:         "Operations the wrapping key permits: decrypt and/or unwrapKey. Defaults to both",
+				MarkdownDescription: "Operations the wrapping key permits: `decrypt` and/or `unwrapKey`. Defaults to both.",
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, defaultKeyOps)),
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.OneOf(WrappingKeyOperations...)),
+				},
+			},
+			"tags": schema.MapAttribute{
+				Description: "Tags of the wrapping key",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"rotate_when_changed": schema.MapAttribute{
+				Description: "Arbitrary values; changing these creates the new version of the wrapping key",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"version": schema.StringAttribute{
+				Description: "Current version of the wrapping key",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_id": schema.StringAttribute{
+				Description: "Key Vault identifier of the current version of the wrapping key",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"public_key_pem": schema.StringAttribute{
+				Description:         "Public key of the current version in PEM format, as tfgen reads it with the -pubkey option",
+				MarkdownDescription: "Public key of the current version in PEM format, as `tfgen` reads it with the `-pubkey` option",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"thumbprint": schema.StringAttribute{
+				Description: "SHA-256 thumbprint (RFC 7638) of the public key of the current version, encoded as base64url",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan marks the attributes of the current version unknown where the plan rotates the key
+func (r *WrappingKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state WrappingKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || !plan.requiresRotation(&state) {
+		return
+	}
+
+	plan.Version = types.StringUnknown()
+	plan.KeyId = types.StringUnknown()
+	plan.PublicKeyPem = types.StringUnknown()
+	plan.Thumbprint = types.StringUnknown()
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *WrappingKeyResource) keysClient(vaultName string, dg *diag.Diagnostics) core.AzKeyClientAbstraction {
+	keysClient, err := r.Factory.GetKeysClient(vaultName)
+	if err != nil {
+		dg.AddError("Cannot acquire keys client", fmt.Sprintf("Cannot acquire keys client to vault %s: %s", vaultName, err.Error()))
+		return nil
+	} else if keysClient == nil {
+		dg.AddError("Cannot acquire keys client", "Keys client returned is nil. This is a provider error. Please report this issue")
+		return nil
+	}
+	return keysClient
+}
+
+// CreateWrappingKey creates the wrapping key. The key that already exists is not adopted: its new version would
+// silently rotate the key that the existing ciphertexts depend upon.
+func (r *WrappingKeyResource) CreateWrappingKey(ctx context.Context, data *WrappingKeyModel, dg *diag.Diagnostics) {
+	keysClient := r.keysClient(data.VaultName.ValueString(), dg)
+	if keysClient == nil {
+		return
+	}
+
+	if _, getErr := keysClient.GetKey(ctx, data.Name.ValueString(), "", nil); getErr == nil {
+		dg.AddError(
+			"Wrapping key already exists",
+			fmt.Sprintf("Key %s already exists in vault %s; choose another name for the new wrapping key", data.Name.ValueString(), data.VaultName.ValueString()),
+		)
+		return
+	} else if !core.IsResourceNotFoundError(getErr) {
+		dg.AddError("Cannot check whether the wrapping key exists", getErr.Error())
+		return
+	}
+
+	r.createVersion(ctx, keysClient, data, dg)
+}
+
+// RotateWrappingKey creates the new version of the wrapping key. The previous versions remain enabled so that the
+// ciphertexts these wrap can be decrypted.
+func (r *WrappingKeyResource) RotateWrappingKey(ctx context.Context, data *WrappingKeyModel, dg *diag.Diagnostics) {
+	keysClient := r.keysClient(data.VaultName.ValueString(), dg)
+	if keysClient == nil {
+		return
+	}
+
+	r.createVersion(ctx, keysClient, data, dg)
+}
+
+func (r *WrappingKeyResource) createVersion(ctx context.Context, keysClient core.AzKeyClientAbstraction, data *WrappingKeyModel, dg *diag.Diagnostics) {
+	keyResp, err := keysClient.CreateKey(ctx, data.Name.ValueString(), data.CreateKeyParameters(ctx), nil)
+	if err != nil {
+		dg.AddError("Cannot create wrapping key", fmt.Sprintf("Request to create key %s in vault %s failed: %s", data.Name.ValueString(), data.VaultName.ValueString(), err.Error()))
+		return
+	}
+
+	data.Accept(ctx, keyResp.KeyBundle, dg)
+}
+
+// UpdateWrappingKey updates the permitted operations and the tags of the current version of the wrapping key
+func (r *WrappingKeyResource) UpdateWrappingKey(ctx context.Context, data *WrappingKeyModel, dg *diag.Diagnostics) {
+	keysClient := r.keysClient(data.VaultName.ValueString(), dg)
+	if keysClient == nil {
+		return
+	}
+
+	tags := data.TagsAsPtr()
+	if tags == nil {
+		tags = map[string]*string{}
+	}
+
+	keyResp, err := keysClient.UpdateKey(ctx, data.Name.ValueString(), data.Version.ValueString(), azkeys.UpdateKeyParameters{
+		KeyOps: data.GetKeyOperations(ctx),
+		Tags:   tags,
+	}, nil)
+	if err != nil {
+		dg.AddError("Cannot update wrapping key", fmt.Sprintf("Request to update key %s in vault %s failed: %s", data.Name.ValueString(), data.VaultName.ValueString(), err.Error()))
+		return
+	}
+
+	data.Accept(ctx, keyResp.KeyBundle, dg)
+}
+
+func (r *WrappingKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data WrappingKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.CreateWrappingKey(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WrappingKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data WrappingKeyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keysClient := r.keysClient(data.VaultName.ValueString(), &resp.Diagnostics)
+	if keysClient == nil {
+		return
+	}
+
+	keyResp, err := keysClient.GetKey(ctx, data.Name.ValueString(), data.Version.ValueString(), nil)
+	if err != nil {
+		if core.IsResourceNotFoundError(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Wrapping key %s is not found in vault %s; removing it from the state", data.Name.ValueString(), data.VaultName.ValueString()))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError("Cannot read wrapping key", err.Error())
+		return
+	}
+
+	data.Accept(ctx, keyResp.KeyBundle, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WrappingKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state WrappingKeyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.requiresRotation(&state) {
+		r.RotateWrappingKey(ctx, &plan, &resp.Diagnostics)
+	} else {
+		r.UpdateWrappingKey(ctx, &plan, &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// DisableWrappingKey disables the current version of the wrapping key. The previous versions created by the rotation
+// remain enabled: the ciphertexts pinned to these versions are still decrypted until these versions are disabled
+// in Key Vault.
+func (r *WrappingKeyResource) DisableWrappingKey(ctx context.Context, data *WrappingKeyModel, dg *diag.Diagnostics) {
+	keysClient := r.keysClient(data.VaultName.ValueString(), dg)
+	if keysClient == nil {
+		return
+	}
+
+	_, err := keysClient.UpdateKey(ctx, data.Name.ValueString(), data.Version.ValueString(), azkeys.UpdateKeyParameters{
+		KeyAttributes: &azkeys.KeyAttributes{
+			Enabled: to.Ptr(false),
+		},
+	}, nil)
+	if err != nil && !core.IsResourceNotFoundError(err) {
+		dg.AddError("Cannot disable wrapping key version", fmt.Sprintf("Request to disable key's %s version %s in vault %s failed: %s",
+			data.Name.ValueString(),
+			data.Version.ValueString(),
+			data.VaultName.ValueString(),
+			err.Error(),
+		))
+	}
+}
+
+// Delete disables the current version of the wrapping key. The key is not deleted: the Azure objects created from
+// the ciphertexts it wraps remain in place, and the key can be re-enabled in Key Vault.
+func (r *WrappingKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data WrappingKeyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.DisableWrappingKey(ctx, &data, &resp.Diagnostics)
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &WrappingKeyResource{}
+var _ resource.ResourceWithConfigure = &WrappingKeyResource{}
+var _ resource.ResourceWithModifyPlan = &WrappingKeyResource{}
+
+func NewWrappingKeyResource() resource.Resource {
+	return &WrappingKeyResource{}
+}
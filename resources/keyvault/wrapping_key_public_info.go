@@ -0,0 +1,240 @@
+package keyvault
+
+import (
+	"context"
+	"crypto/rsa"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+)
+
+// WrappingKeyPublicInfoModel Model of the data source reporting the public key of the wrapping key
+type WrappingKeyPublicInfoModel struct {
+	WrappingKeyCoordinate *core.WrappingKeyCoordinateModel `tfsdk:"wrapping_key"`
+
+	VaultName     types.String `tfsdk:"vault_name"`
+	Name          types.String `tfsdk:"name"`
+	Version       types.String `tfsdk:"version"`
+	Algorithm     types.String `tfsdk:"algorithm"`
+	KeyId         types.String `tfsdk:"key_id"`
+	PublicKeyPem  types.String `tfsdk:"public_key_pem"`
+	PublicKeyJwk  types.String `tfsdk:"public_key_jwk"`
+	Thumbprint    types.String `tfsdk:"thumbprint"`
+	KeyOperations types.Set    `tfsdk:"key_operations"`
+}
+
+// Accept sets the public information of the wrapping key read from Key Vault. Only RSA keys can wrap the
+// content encryption keys.
+func (m *WrappingKeyPublicInfoModel) Accept(ctx context.Context, coord core.WrappingKeyCoordinate, key azkeys.KeyBundle, dg *diag.Diagnostics) {
+	if key.Key == nil || key.Key.Kty == nil || (*key.Key.Kty != azkeys.KeyTypeRSA && *key.Key.Kty != azkeys.KeyTypeRSAHSM) {
+		dg.AddError(
+			"Wrapping key is not an RSA key",
+			fmt.Sprintf("Key %s in vault %s cannot be used to wrap the content encryption keys", coord.KeyName, coord.VaultName),
+		)
+		return
+	}
+
+	publicKey := &rsa.PublicKey{
+		N: big.NewInt(0).SetBytes(key.Key.N),
+		E: int(big.NewInt(0).SetBytes(key.Key.E).Uint64()),
+	}
+
+	pemData, pemErr := core.PublicKeyToPEM(publicKey)
+	if pemErr != nil {
+		dg.AddError("Public key cannot be encoded", pemErr.Error())
+		return
+	}
+
+	thumbprint, tpErr := core.PublicKeyThumbprint(publicKey)
+	if tpErr != nil {
+		dg.AddError("Public key thumbprint cannot be computed", tpErr.Error())
+		return
+	}
+
+	jwkData, jwkErr := publicKeyJwk(publicKey, key.Key.KID)
+	if jwkErr != nil {
+		dg.AddError("Public key cannot be converted to JSON Web Key", jwkErr.Error())
+		return
+	}
+
+	var keyOps []string
+	for _, op := range key.Key.KeyOps {
+		if op != nil {
+			keyOps = append(keyOps, string(*op))
+		}
+	}
+	keyOpsSet, setDg := types.SetValueFrom(ctx, types.StringType, keyOps)
+	dg.Append(setDg...)
+
+	m.VaultName = types.StringValue(coord.VaultName)
+	m.Name = types.StringValue(coord.KeyName)
+	m.Version = types.StringValue(coord.KeyVersion)
+	m.Algorithm = types.StringValue(coord.GetAlgorithm())
+	m.KeyId = types.StringNull()
+	if key.Key.KID != nil {
+		m.KeyId = types.StringValue(string(*key.Key.KID))
+	}
+	m.PublicKeyPem = types.StringValue(string(pemData))
+	m.PublicKeyJwk = types.StringValue(string(jwkData))
+	m.Thumbprint = types.StringValue(thumbprint)
+	m.KeyOperations = keyOpsSet
+}
+
+// publicKeyJwk the public key as the JSON Web Key, identified with the Key Vault key id
+func publicKeyJwk(publicKey *rsa.PublicKey, kid *azkeys.ID) ([]byte, error) {
+	jwkKey, err := jwk.Import(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if kid != nil {
+		if setErr := jwkKey.Set(jwk.KeyIDKey, string(*kid)); setErr != nil {
+			return nil, setErr
+		}
+	}
+
+	return json.Marshal(jwkKey)
+}
+
+type WrappingKeyPublicInfoDataSource struct {
+	resources.ConfidentialDatasourceBase
+}
+
+func (d *WrappingKeyPublicInfoDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wrapping_key_public_info"
+}
+
+//go:embed wrapping_key_public_info.md
+var wrappingKeyPublicInfoDataSourceMarkdownDescription string
+
+func (d *WrappingKeyPublicInfoDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Datasource reporting the public key of the wrapping key the provider uses",
+		MarkdownDescription: wrappingKeyPublicInfoDataSourceMarkdownDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"wrapping_key": schema.SingleNestedAttribute{
+				Optional:            true,
+				Description:         "Wrapping key to report. Where not specified, the default wrapping key of the provider is reported",
+				MarkdownDescription: "Wrapping key to report. Where not specified, the `default_wrapping_key` of the provider is reported",
+
+				Attributes: map[string]schema.Attribute{
+					"vault_name": schema.StringAttribute{
+						Optional:    true,
+						Description: "Vault name containing the wrapping key",
+					},
+					"name": schema.StringAttribute{
+						Optional:    true,
+						Description: "Name of the wrapping key",
+					},
+					"version": schema.StringAttribute{
+						Optional:    true,
+						Description: "Version of the wrapping key. Where not specified, the latest version is reported",
+					},
+					"algorithm": schema.StringAttribute{
+						Optional:    true,
+						Description: "Algorithm to unwrap the content encryption key material",
+					},
+				},
+			},
+			"vault_name": schema.StringAttribute{
+				Description: "Vault containing the wrapping key",
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the wrapping key",
+				Computed:    true,
+			},
+			"version": schema.StringAttribute{
+				Description: "Version of the wrapping key",
+				Computed:    true,
+			},
+			"algorithm": schema.StringAttribute{
+				Description: "Algorithm unwrapping the content encryption keys",
+				Computed:    true,
+			},
+			"key_id": schema.StringAttribute{
+				Description: "Key Vault identifier of the wrapping key version",
+				Computed:    true,
+			},
+			"public_key_pem": schema.StringAttribute{
+				Description:         "Public key in PEM format, as tfgen reads it with the -pubkey option",
+				MarkdownDescription: "Public key in PEM format, as `tfgen` reads it with the `-pubkey` option",
+				Computed:            true,
+			},
+			"public_key_jwk": schema.StringAttribute{
+				Description: "Public key as JSON Web Key",
+				Computed:    true,
+			},
+			"thumbprint": schema.StringAttribute{
+				Description: "SHA-256 thumbprint (RFC 7638) of the public key, encoded as base64url",
+				Computed:    true,
+			},
+			"key_operations": schema.SetAttribute{
+				Description: "Operations the wrapping key permits",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// ReadPublicInfo reads the public key of the wrapping key. The coordinate is merged with the provider defaults.
+func (d *WrappingKeyPublicInfoDataSource) ReadPublicInfo(ctx context.Context, data *WrappingKeyPublicInfoModel, dg *diag.Diagnostics) {
+	coord, coordErr := d.Factory.GetMergedWrappingKeyCoordinate(ctx, data.WrappingKeyCoordinate)
+	if coordErr != nil {
+		dg.AddError("Wrapping key cannot be resolved", coordErr.Error())
+		return
+	}
+
+	keyClient, clientErr := d.Factory.GetKeysClient(coord.VaultName)
+	if clientErr != nil {
+		dg.AddError("Error acquiring keys client", clientErr.Error())
+		return
+	} else if keyClient == nil {
+		dg.AddError("Az keys client cannot be retrieved", "Nil client returned while no error was raised. This is a provider bug. Please report this")
+		return
+	}
+
+	keyResp, getErr := keyClient.GetKey(ctx, coord.KeyName, coord.KeyVersion, nil)
+	if getErr != nil {
+		dg.AddError("Wrapping key cannot be read", getErr.Error())
+		return
+	}
+
+	data.Accept(ctx, coord, keyResp.KeyBundle, dg)
+}
+
+func (d *WrappingKeyPublicInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data WrappingKeyPublicInfoModel
+
+	dg := &resp.Diagnostics
+	dg.Append(req.Config.Get(ctx, &data)...)
+	if dg.HasError() {
+		return
+	}
+
+	d.ReadPublicInfo(ctx, &data, dg)
+	if dg.HasError() {
+		return
+	}
+
+	dg.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &WrappingKeyPublicInfoDataSource{}
+
+func NewWrappingKeyPublicInfoDataSource() datasource.DataSource {
+	return &WrappingKeyPublicInfoDataSource{}
+}
@@ -0,0 +1,121 @@
+package keyvault
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const wrappingKeyId = "https://unit-test-vault.vault.azure.net/keys/wrapping-key/0123456789"
+
+func givenWrappingKeyBundle(t *testing.T) azkeys.KeyBundle {
+	pubKey, err := core.LoadPublicKeyFromData(testkeymaterial.EphemeralRsaPublicKey)
+	assert.NoError(t, err)
+
+	return azkeys.KeyBundle{
+		Key: &azkeys.JSONWebKey{
+			KID:    to.Ptr(azkeys.ID(wrappingKeyId)),
+			Kty:    to.Ptr(azkeys.KeyTypeRSAHSM),
+			N:      pubKey.N.Bytes(),
+			E:      []byte{1, 0, 1},
+			KeyOps: []*azkeys.KeyOperation{to.Ptr(azkeys.KeyOperationDecrypt), to.Ptr(azkeys.KeyOperationUnwrapKey)},
+		},
+	}
+}
+
+func givenWrappingKeyCoordinate() core.WrappingKeyCoordinate {
+	return core.WrappingKeyCoordinate{
+		VaultName:  "unit-test-vault",
+		KeyName:    "wrapping-key",
+		KeyVersion: "0123456789",
+	}
+}
+
+func Test_WrappingKeyPublicInfo_AcceptRsaKey(t *testing.T) {
+	mdl := WrappingKeyPublicInfoModel{}
+	dg := diag.Diagnostics{}
+
+	mdl.Accept(context.Background(), givenWrappingKeyCoordinate(), givenWrappingKeyBundle(t), &dg)
+	assert.False(t, dg.HasError())
+
+	pubKey, err := core.LoadPublicKeyFromData([]byte(mdl.PublicKeyPem.ValueString()))
+	assert.NoError(t, err)
+	expectedKey, _ := core.LoadPublicKeyFromData(testkeymaterial.EphemeralRsaPublicKey)
+	assert.True(t, expectedKey.Equal(pubKey))
+
+	expectedThumbprint, _ := core.PublicKeyThumbprint(expectedKey)
+	assert.Equal(t, expectedThumbprint, mdl.Thumbprint.ValueString())
+
+	assert.Equal(t, wrappingKeyId, mdl.KeyId.ValueString())
+	assert.Equal(t, "0123456789", mdl.Version.ValueString())
+	assert.Equal(t, "RSA-OAEP-256", mdl.Algorithm.ValueString())
+	assert.Contains(t, mdl.PublicKeyJwk.ValueString(), `"kid":"`+wrappingKeyId+`"`)
+	assert.Contains(t, mdl.PublicKeyJwk.ValueString(), `"kty":"RSA"`)
+	assert.Equal(t, 2, len(mdl.KeyOperations.Elements()))
+}
+
+func Test_WrappingKeyPublicInfo_RejectsEcKey(t *testing.T) {
+	mdl := WrappingKeyPublicInfoModel{}
+	dg := diag.Diagnostics{}
+
+	mdl.Accept(context.Background(), givenWrappingKeyCoordinate(), azkeys.KeyBundle{
+		Key: &azkeys.JSONWebKey{Kty: to.Ptr(azkeys.KeyTypeEC)},
+	}, &dg)
+
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Wrapping key is not an RSA key", dg[0].Summary())
+	assert.True(t, mdl.PublicKeyPem.IsNull())
+}
+
+func Test_WrappingKeyPublicInfo_ReadsDefaultWrappingKey(t *testing.T) {
+	factory := &AZClientsFactoryMock{}
+	keysClient := &KeysClientMock{}
+
+	factory.On("GetMergedWrappingKeyCoordinate", mock.Anything, (*core.WrappingKeyCoordinateModel)(nil)).
+		Return(givenWrappingKeyCoordinate(), nil)
+	factory.GivenGetKeysClientWillReturn("unit-test-vault", keysClient)
+
+	var options *azkeys.GetKeyOptions = nil
+	keysClient.On("GetKey", mock.Anything, "wrapping-key", "0123456789", options).
+		Return(azkeys.GetKeyResponse{KeyBundle: givenWrappingKeyBundle(t)}, nil)
+
+	ds := WrappingKeyPublicInfoDataSource{}
+	ds.Factory = factory
+
+	mdl := WrappingKeyPublicInfoModel{}
+	dg := diag.Diagnostics{}
+	ds.ReadPublicInfo(context.Background(), &mdl, &dg)
+
+	assert.False(t, dg.HasError())
+	assert.Equal(t, "unit-test-vault", mdl.VaultName.ValueString())
+	assert.Equal(t, "wrapping-key", mdl.Name.ValueString())
+	assert.False(t, mdl.PublicKeyPem.IsNull())
+
+	factory.AssertExpectations(t)
+	keysClient.AssertExpectations(t)
+}
+
+func Test_WrappingKeyPublicInfo_IfCoordinateCannotBeResolved(t *testing.T) {
+	factory := &AZClientsFactoryMock{}
+	factory.On("GetMergedWrappingKeyCoordinate", mock.Anything, (*core.WrappingKeyCoordinateModel)(nil)).
+		Return(core.WrappingKeyCoordinate{}, errors.New("incomplete coordinate of a wrapping key"))
+
+	ds := WrappingKeyPublicInfoDataSource{}
+	ds.Factory = factory
+
+	mdl := WrappingKeyPublicInfoModel{}
+	dg := diag.Diagnostics{}
+	ds.ReadPublicInfo(context.Background(), &mdl, &dg)
+
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Wrapping key cannot be resolved", dg[0].Summary())
+	factory.AssertExpectations(t)
+}
@@ -0,0 +1,164 @@
+package keyvault
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func givenWrappingKeyModel() WrappingKeyModel {
+	return WrappingKeyModel{
+		VaultName: types.StringValue("unit-test-vault"),
+		Name:      types.StringValue("wrapping-key"),
+		KeySize:   types.Int64Value(4096),
+		KeyOperations: types.SetValueMust(types.StringType, []attr.Value{
+			types.StringValue("decrypt"),
+			types.StringValue("unwrapKey"),
+		}),
+		Tags:              types.MapNull(types.StringType),
+		RotateWhenChanged: types.MapNull(types.StringType),
+	}
+}
+
+func Test_WrappingKeyModel_CreateKeyParameters(t *testing.T) {
+	mdl := givenWrappingKeyModel()
+	mdl.Tags = types.MapValueMust(types.StringType, map[string]attr.Value{"a": types.StringValue("b")})
+
+	params := mdl.CreateKeyParameters(context.Background())
+	assert.Equal(t, azkeys.KeyTypeRSAHSM, *params.Kty)
+	assert.Equal(t, int32(4096), *params.KeySize)
+	assert.True(t, *params.KeyAttributes.Enabled)
+	assert.False(t, *params.KeyAttributes.Exportable)
+	assert.Nil(t, params.ReleasePolicy)
+	assert.ElementsMatch(t, []*azkeys.KeyOperation{
+		to.Ptr(azkeys.KeyOperationDecrypt),
+		to.Ptr(azkeys.KeyOperationUnwrapKey),
+	}, params.KeyOps)
+	assert.Equal(t, "b", *params.Tags["a"])
+}
+
+func Test_WrappingKeyModel_RequiresRotation(t *testing.T) {
+	state := givenWrappingKeyModel()
+
+	plan := givenWrappingKeyModel()
+	assert.False(t, plan.requiresRotation(&state))
+
+	plan.KeySize = types.Int64Value(3072)
+	assert.True(t, plan.requiresRotation(&state))
+
+	plan = givenWrappingKeyModel()
+	plan.RotateWhenChanged = types.MapValueMust(types.StringType, map[string]attr.Value{"r": types.StringValue("1")})
+	assert.True(t, plan.requiresRotation(&state))
+}
+
+func Test_WrappingKey_CreateOutputsPublicKeyPem(t *testing.T) {
+	factory := &AZClientsFactoryMock{}
+	keysClient := &KeysClientMock{}
+	factory.GivenGetKeysClientWillReturn("unit-test-vault", keysClient)
+
+	var getOptions *azkeys.GetKeyOptions = nil
+	keysClient.On("GetKey", mock.Anything, "wrapping-key", "", getOptions).
+		Return(azkeys.GetKeyResponse{}, errors.New("GET https://unit-test-vault.vault.azure.net/keys/wrapping-key\nRESPONSE 404: 404 Not Found"))
+
+	var createOptions *azkeys.CreateKeyOptions = nil
+	keysClient.On("CreateKey", mock.Anything, "wrapping-key", mock.MatchedBy(func(p azkeys.CreateKeyParameters) bool {
+		return *p.Kty == azkeys.KeyTypeRSAHSM && !*p.KeyAttributes.Exportable && len(p.KeyOps) == 2
+	}), createOptions).
+		Return(azkeys.CreateKeyResponse{KeyBundle: givenWrappingKeyBundle(t)}, nil)
+
+	r := WrappingKeyResource{}
+	r.Factory = factory
+
+	mdl := givenWrappingKeyModel()
+	dg := diag.Diagnostics{}
+	r.CreateWrappingKey(context.Background(), &mdl, &dg)
+
+	assert.False(t, dg.HasError())
+	assert.Equal(t, "https://unit-test-vault.vault.azure.net/keys/wrapping-key", mdl.Id.ValueString())
+	assert.Equal(t, "0123456789", mdl.Version.ValueString())
+	assert.Equal(t, wrappingKeyId, mdl.KeyId.ValueString())
+
+	pubKey, err := core.LoadPublicKeyFromData([]byte(mdl.PublicKeyPem.ValueString()))
+	assert.NoError(t, err)
+	expectedKey, _ := core.LoadPublicKeyFromData(testkeymaterial.EphemeralRsaPublicKey)
+	assert.True(t, expectedKey.Equal(pubKey))
+	assert.Equal(t, int64(expectedKey.N.BitLen()), mdl.KeySize.ValueInt64())
+
+	factory.AssertExpectations(t)
+	keysClient.AssertExpectations(t)
+}
+
+func Test_WrappingKey_CreateRejectsExistingKey(t *testing.T) {
+	factory := &AZClientsFactoryMock{}
+	keysClient := &KeysClientMock{}
+	factory.GivenGetKeysClientWillReturn("unit-test-vault", keysClient)
+
+	var getOptions *azkeys.GetKeyOptions = nil
+	keysClient.On("GetKey", mock.Anything, "wrapping-key", "", getOptions).
+		Return(azkeys.GetKeyResponse{KeyBundle: givenWrappingKeyBundle(t)}, nil)
+
+	r := WrappingKeyResource{}
+	r.Factory = factory
+
+	mdl := givenWrappingKeyModel()
+	dg := diag.Diagnostics{}
+	r.CreateWrappingKey(context.Background(), &mdl, &dg)
+
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Wrapping key already exists", dg[0].Summary())
+	keysClient.AssertNotCalled(t, "CreateKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_WrappingKey_DisablesOnlyCurrentVersion(t *testing.T) {
+	factory := &AZClientsFactoryMock{}
+	keysClient := &KeysClientMock{}
+	factory.GivenGetKeysClientWillReturn("unit-test-vault", keysClient)
+
+	var updateOptions *azkeys.UpdateKeyOptions = nil
+	keysClient.On("UpdateKey", mock.Anything, "wrapping-key", "0123456789", mock.MatchedBy(func(p azkeys.UpdateKeyParameters) bool {
+		return p.KeyAttributes != nil && p.KeyAttributes.Enabled != nil && !*p.KeyAttributes.Enabled
+	}), updateOptions).
+		Once().
+		Return(azkeys.UpdateKeyResponse{}, nil)
+
+	r := WrappingKeyResource{}
+	r.Factory = factory
+
+	mdl := givenWrappingKeyModel()
+	mdl.Version = types.StringValue("0123456789")
+	dg := diag.Diagnostics{}
+	r.DisableWrappingKey(context.Background(), &mdl, &dg)
+
+	assert.False(t, dg.HasError())
+	factory.AssertExpectations(t)
+	keysClient.AssertExpectations(t)
+	keysClient.AssertNumberOfCalls(t, "UpdateKey", 1)
+}
+
+func Test_WrappingKey_DisableToleratesRemovedKey(t *testing.T) {
+	factory := &AZClientsFactoryMock{}
+	keysClient := &KeysClientMock{}
+	factory.GivenGetKeysClientWillReturn("unit-test-vault", keysClient)
+	keysClient.GivenUpdateKeyReturnsError("wrapping-key", "0123456789", "PATCH https://unit-test-vault.vault.azure.net/keys/wrapping-key/0123456789\nRESPONSE 404: 404 Not Found")
+
+	r := WrappingKeyResource{}
+	r.Factory = factory
+
+	mdl := givenWrappingKeyModel()
+	mdl.Version = types.StringValue("0123456789")
+	dg := diag.Diagnostics{}
+	r.DisableWrappingKey(context.Background(), &mdl, &dg)
+
+	assert.False(t, dg.HasError())
+	keysClient.AssertExpectations(t)
+}
@@ -1,15 +1,22 @@
 package resources
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -56,6 +63,9 @@ type CommonConfidentialResourceSpecialization[TMdl any, TConfData any, AZAPIObje
 	GetConfidentialMaterialFrom(mdl TMdl) ConfidentialMaterialModel
 	Decrypt(ctx context.Context, em core.EncryptedMessage, decr core.RSADecrypter) (core.ConfidentialDataJsonHeader, TConfData, error)
 	CheckPlacement(ctx context.Context, providerConstraints []core.ProviderConstraint, placementConstraints []core.PlacementConstraint, tfModel *TMdl) diag.Diagnostics
+	// GetDestinationLabel returns the label of the destination where the object is placed, as recorded in the
+	// audit events.
+	GetDestinationLabel(tfModel *TMdl) string
 
 	DoCreate(ctx context.Context, planData *TMdl, plainData TConfData) (AZAPIObject, diag.Diagnostics)
 	DoDelete(ctx context.Context, planData *TMdl) diag.Diagnostics
@@ -74,13 +84,67 @@ type MutableConfidentialResourceRU[TMdl any, TConfData any, AZAPIObject any] int
 	SetDriftToConfidentialData(ctx context.Context, planData *TMdl)
 }
 
+// ConfidentialValueReader is implemented by the resources that can read the confidential value of the Azure object.
+// The value of the mutable resources is compared with the fingerprint kept in the private state of the resource, which
+// spares decrypting the ciphertext on every read. The value of the object moved into the resource from another
+// resource is compared with the plaintext of the ciphertext adopting it.
+type ConfidentialValueReader[TMdl any, TConfData any, AZAPIObject any] interface {
+	// ConfidentialValueOf returns the value placed on the Azure object from the plaintext
+	ConfidentialValueOf(plainData TConfData) []byte
+	// DoReadConfidentialValue reads the Azure object together with its confidential value
+	DoReadConfidentialValue(ctx context.Context, planData *TMdl) (AZAPIObject, []byte, ResourceExistenceCheck, diag.Diagnostics)
+}
+
+// IdentitySpecialization is implemented by the specializers of the resources declaring the resource identity.
+type IdentitySpecialization[TMdl, TIdentity any] interface {
+	// IdentityOf returns the identity of the Azure object the resource manages. The identity records the uuid
+	// of the ciphertext the object was created from.
+	IdentityOf(tfModel *TMdl, ciphertextUuid string) (TIdentity, error)
+}
+
+// CiphertextUuidIdentityAttribute the attribute of the resource identity recording the uuid of the ciphertext
+// the Azure object was created from
+const CiphertextUuidIdentityAttribute = "ciphertext_uuid"
+
+// FingerprintPrivateStateKey the key of the private state keeping the fingerprint of the confidential value
+const FingerprintPrivateStateKey = "fingerprint"
+
+// Default timeouts of the resource operations where the resource configuration does not specify these.
+const (
+	DefaultCreateTimeout = 30 * time.Minute
+	DefaultReadTimeout   = 5 * time.Minute
+	DefaultUpdateTimeout = 30 * time.Minute
+	DefaultDeleteTimeout = 30 * time.Minute
+)
+
+// TimeoutSelector selects the timeout of the operation from the timeouts of the resource
+type TimeoutSelector func(t timeouts.Value, ctx context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics)
+
+// WithOperationTimeout limits the context of the operation with the timeout configured on the resource. The
+// context is not limited where the model of the resource does not accept the timeouts.
+func WithOperationTimeout[TMdl any](ctx context.Context, data *TMdl, selector TimeoutSelector, defaultTimeout time.Duration, dg *diag.Diagnostics) (context.Context, context.CancelFunc) {
+	tm, ok := any(data).(TimeoutsModel)
+	if !ok {
+		return ctx, func() {}
+	}
+
+	timeout, timeoutDiags := selector(tm.GetTimeouts(), ctx, defaultTimeout)
+	dg.Append(timeoutDiags...)
+
+	return context.WithTimeout(ctx, timeout)
+}
+
 type RequestAbstraction struct {
-	Get      func(ctx context.Context, val interface{}) diag.Diagnostics
-	HasError func() bool
+	Get                  func(ctx context.Context, val interface{}) diag.Diagnostics
+	GetPrivate           func(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	GetIdentityAttribute func(ctx context.Context, p path.Path, target interface{}) diag.Diagnostics
+	HasError             func() bool
 }
 
 type ResponseAbstraction struct {
 	Set            func(ctx context.Context, val interface{}) diag.Diagnostics
+	SetPrivate     func(ctx context.Context, key string, value []byte) diag.Diagnostics
+	SetIdentity    func(ctx context.Context, val interface{}) diag.Diagnostics
 	RemoveResource func(context.Context)
 	Diagnostics    *diag.Diagnostics
 }
@@ -91,8 +155,17 @@ type ConfidentialGenericResource[TMdl, TIdentity any, TConfData any, AZAPIObject
 	ImmutableRU ImmutableConfidentialResourceRU[TMdl, TConfData, AZAPIObject]
 	MutableRU   MutableConfidentialResourceRU[TMdl, TConfData, AZAPIObject]
 
-	ResourceName   string
-	ResourceSchema schema.Schema
+	ResourceName           string
+	ResourceSchema         schema.Schema
+	ResourceIdentitySchema identityschema.Schema
+
+	// StateUpgraders the upgraders of the state written with the prior versions of the resource schema, keyed
+	// by the prior version.
+	StateUpgraders map[int64]resource.StateUpgrader
+
+	// StateMovers the movers of the state of the resources of other providers, e.g. azurerm, managing the same
+	// Azure objects.
+	StateMovers []resource.StateMover
 }
 
 func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -112,16 +185,36 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) S
 	resp.Schema = d.ResourceSchema
 }
 
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = d.ResourceIdentitySchema
+}
+
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return d.StateUpgraders
+}
+
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) MoveState(_ context.Context) []resource.StateMover {
+	return d.StateMovers
+}
+
 func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	reqAbs := RequestAbstraction{
-		Get: req.State.Get,
+		Get:        req.State.Get,
+		GetPrivate: req.Private.GetKey,
+	}
+	if req.Identity != nil {
+		reqAbs.GetIdentityAttribute = req.Identity.GetAttribute
 	}
 
 	resAbs := ResponseAbstraction{
 		Set:            resp.State.Set,
+		SetPrivate:     resp.Private.SetKey,
 		RemoveResource: resp.State.RemoveResource,
 		Diagnostics:    &resp.Diagnostics,
 	}
+	if resp.Identity != nil {
+		resAbs.SetIdentity = resp.Identity.Set
+	}
 
 	d.ReadT(ctx, reqAbs, resAbs)
 }
@@ -135,8 +228,12 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) R
 		return
 	}
 
+	ctx, cancel := WithOperationTimeout(ctx, &data, timeouts.Value.Read, DefaultReadTimeout, resp.Diagnostics)
+	defer cancel()
+
 	var azObj AZAPIObject
 	var resourceExistenceCheck = ResourceCheckNotAttempted
+	var ciphertextUuid string
 	dg := diag.Diagnostics{}
 
 	if d.ImmutableRU != nil {
@@ -152,23 +249,42 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) R
 			return
 		}
 
-		em := core.EncryptedMessage{}
-		if emImportErr := em.FromBase64PEM(confMdl.EncryptedSecret.ValueString()); emImportErr != nil {
-			dg.AddError(
-				"Confidential content does not conform to the expected format",
-				fmt.Sprintf("Received this error while trying to parse the confidential message: %s. Confidential content shoud be produced either by tfgen tool or vai appropriate function", emImportErr.Error()),
-			)
-			return
+		// Where the fingerprint of the value placed from this ciphertext is known, the header it keeps is used
+		// instead of decrypting the ciphertext.
+		fingerprint := d.readFingerprint(ctx, req, confMdl.EncryptedSecret.ValueString())
+
+		var header core.ConfidentialDataJsonHeader
+		var confData TConfData
+
+		if fingerprint != nil {
+			header = fingerprint.Header
+		} else {
+			em := core.EncryptedMessage{}
+			if emImportErr := em.FromBase64PEM(confMdl.EncryptedSecret.ValueString()); emImportErr != nil {
+				resp.Diagnostics.AddError(
+					"Confidential content does not conform to the expected format",
+					fmt.Sprintf("Received this error while trying to parse the confidential message: %s. Confidential content shoud be produced either by tfgen tool or vai appropriate function", emImportErr.Error()),
+				)
+				return
+			}
+
+			rsaDecrypter := d.decrypterFor(ctx, confMdl, &data)
+
+			var err error
+			header, confData, err = d.Specializer.Decrypt(ctx, em, rsaDecrypter)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Cannot decrypt ciphertext",
+					fmt.Sprintf("Received this error while trying to decrypt the confidential message: %s. Confidential content shoud be produced either by tfgen tool or vai appropriate function and encrypted with the public key that this provider is using.", err.Error()),
+				)
+				return
+			}
 		}
 
-		rsaDecrypter := d.Factory.GetDecrypterFor(ctx, confMdl.WrappingKeyCoordinate)
+		ciphertextUuid = header.Uuid
 
-		header, confData, err := d.Specializer.Decrypt(ctx, em, rsaDecrypter)
-		if err != nil {
-			dg.AddError(
-				"Cannot decrypt ciphertext",
-				fmt.Sprintf("Received this error while trying to decrypt the confidential message: %s. Confidential content shoud be produced either by tfgen tool or vai appropriate function and encrypted with the public key that this provider is using.", err.Error()),
-			)
+		d.CheckCiphertextRevocation(ctx, header, resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
 			return
 		}
 
@@ -184,15 +300,21 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) R
 			return
 		}
 
-		// Object tracking lookup is not performed at this point. In mutable read/update, the practitioner
-		// may have replaced the ciphertext with a new value or the state of the object may have drifted
-		// from what the ciphertext declares. Using identity schema may offer a place to store this information;
-		// however, it is not currently implemented due to Terraform version compatibility considerations.
-		//
-		// A recommended solution to use strict target labeling to counter the risk of copying an update.
-		//
-		// The update operation will track the object to make sure that new creations would not be possible.
-		azObj, resourceExistenceCheck, dg = d.MutableRU.DoRead(ctx, &data, confData)
+		// The practitioner may have copied the ciphertext from another resource; the tracker records the
+		// destinations where the ciphertext was placed, which reveals the copy.
+		d.CheckCiphertextDestination(ctx, header, d.Specializer.GetDestinationLabel(&data), resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if fingerprint != nil {
+			azObj, resourceExistenceCheck, dg = d.readAgainstFingerprint(ctx, &data, *fingerprint)
+		} else {
+			azObj, resourceExistenceCheck, dg = d.MutableRU.DoRead(ctx, &data, confData)
+			if resourceExistenceCheck == ResourceExists {
+				d.storeFingerprint(ctx, resp.SetPrivate, confMdl.EncryptedSecret.ValueString(), header, confData, resp.Diagnostics)
+			}
+		}
 	} else {
 		resp.Diagnostics.AddError("Incomplete resource configuration", "This resource does not define read/update methods")
 		return
@@ -225,6 +347,7 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) R
 		}
 
 		resp.Diagnostics.Append(resp.Set(ctx, &data)...)
+		d.setIdentity(ctx, req.GetIdentityAttribute, resp.SetIdentity, &data, ciphertextUuid, resp.Diagnostics)
 	} else if resourceExistenceCheck == ResourceCheckError {
 		tflog.Error(ctx, "Failed to check the existence of resource during read; consult diagnostic messages")
 		if !resp.Diagnostics.HasError() {
@@ -242,9 +365,13 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) C
 
 	resAbs := ResponseAbstraction{
 		Set:            resp.State.Set,
+		SetPrivate:     resp.Private.SetKey,
 		RemoveResource: resp.State.RemoveResource,
 		Diagnostics:    &resp.Diagnostics,
 	}
+	if resp.Identity != nil {
+		resAbs.SetIdentity = resp.Identity.Set
+	}
 
 	d.CreateT(ctx, reqAbs, resAbs)
 }
@@ -258,7 +385,18 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) C
 		return
 	}
 
+	ctx, cancel := WithOperationTimeout(ctx, &data, timeouts.Value.Create, DefaultCreateTimeout, resp.Diagnostics)
+	defer cancel()
+
 	confMdl := d.Specializer.GetConfidentialMaterialFrom(data)
+
+	// The failure to parse or to decrypt the ciphertext is audited with the header the ciphertext declares;
+	// the decrypted header replaces it once the ciphertext is decrypted.
+	auditHeader := UnverifiedHeaderOf(confMdl)
+	defer func() {
+		d.recordAuditEvent(ctx, core.AuditOperationCreate, auditHeader, &data, confMdl.WrappingKeyCoordinate, resp.Diagnostics)
+	}()
+
 	em := core.EncryptedMessage{}
 	if emImportErr := em.FromBase64PEM(confMdl.EncryptedSecret.ValueString()); emImportErr != nil {
 		resp.Diagnostics.AddError(
@@ -268,7 +406,7 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) C
 		return
 	}
 
-	rsaDecrypter := d.Factory.GetDecrypterFor(ctx, confMdl.WrappingKeyCoordinate)
+	rsaDecrypter := d.decrypterFor(ctx, confMdl, &data)
 
 	header, confData, err := d.Specializer.Decrypt(ctx, em, rsaDecrypter)
 	if err != nil {
@@ -278,6 +416,12 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) C
 		)
 		return
 	}
+	auditHeader = header
+
+	d.CheckCiphertextRevocation(ctx, header, resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	d.CheckCiphertextExpiry(ctx, header, resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -289,6 +433,11 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) C
 		return
 	}
 
+	d.CheckCiphertextNotBefore(ctx, header, resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	placementDiags := d.Specializer.CheckPlacement(ctx, header.ProviderConstraints, header.PlacementConstraints, &data)
 	resp.Diagnostics.Append(placementDiags...)
 	if resp.Diagnostics.HasError() {
@@ -296,30 +445,12 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) C
 		return
 	}
 
-	if header.NumUses > 0 && !d.Factory.IsObjectTrackingEnabled() {
-		resp.Diagnostics.AddError(
-			"Insecure provider configuration",
-			"The ciphertext of this resource requires tracking the number of times this object is created, while this provider is not configured to do so. Please configure the provider to track objects",
-		)
+	d.CheckCiphertextUsageLimits(ctx, header, &data, resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if header.NumUses > 0 {
-		// In case a ciphertext may be used several times, the usage is allowed to this limit
-		if numTracked, ntErr := d.Factory.GetTackedObjectUses(ctx, header.Uuid); ntErr != nil {
-			resp.Diagnostics.AddError(
-				"Cannot assert the number of times this ciphertext was used",
-				fmt.Sprintf("Attempt to check how many times the ciphertext was previously used to create a resource erred: %s", ntErr.Error()),
-			)
-			return
-		} else if numTracked >= header.NumUses {
-			resp.Diagnostics.AddError(
-				"Ciphertext has been used all time it was allowed to do so",
-				fmt.Sprintf("The use of this ciphertext to create Azure objects has been exhaused. Re-encrypt and replace the ciphertext to continue."),
-			)
-			return
-		}
-	}
+	d.stampProvenanceTags(header, confMdl, &data)
 
 	azObj, dg := d.Specializer.DoCreate(ctx, &data, confData)
 	resp.Diagnostics.Append(dg...)
@@ -333,9 +464,11 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) C
 	}
 
 	resp.Diagnostics.Append(resp.Set(ctx, &data)...)
+	d.setIdentity(ctx, nil, resp.SetIdentity, &data, header.Uuid, resp.Diagnostics)
+	d.storeFingerprint(ctx, resp.SetPrivate, confMdl.EncryptedSecret.ValueString(), header, confData, resp.Diagnostics)
 
-	if header.NumUses > 0 {
-		if trackErr := d.Factory.TrackObjectId(ctx, header.Uuid); trackErr != nil {
+	if header.NumUses > 0 && d.Factory.IsObjectTrackingEnabled() {
+		if trackErr := d.Factory.TrackObjectId(ctx, header.Uuid, d.Specializer.GetDestinationLabel(&data)); trackErr != nil {
 			errMsg := fmt.Sprintf("could not track the object entered into the state: %s", trackErr.Error())
 			tflog.Error(ctx, errMsg)
 			resp.Diagnostics.AddError("Incomplete object tracking", errMsg)
@@ -356,6 +489,290 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) C
 	}
 }
 
+// CheckCiphertextUsageLimits checks that the ciphertext can be used to create yet another object.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) CheckCiphertextUsageLimits(ctx context.Context, header core.ConfidentialDataJsonHeader, data *TMdl, dg *diag.Diagnostics) {
+	if header.NumUses <= 0 {
+		return
+	}
+
+	if !d.Factory.IsObjectTrackingEnabled() {
+		// The provenance tags record only the last ciphertext used at the destination; these can tell the repeated
+		// use of a single-use ciphertext at the same destination, but cannot count the uses.
+		if reader, ok := d.Specializer.(ProvenanceTagReader[TMdl]); ok && d.Factory.IsProvenanceTaggingEnabled() && header.NumUses == 1 {
+			d.checkCiphertextUsageByProvenanceTags(ctx, reader, header, data, dg)
+			return
+		}
+
+		dg.AddError(
+			"Insecure provider configuration",
+			"The ciphertext of this resource requires tracking the number of times this object is created, while this provider is not configured to do so. Please configure the provider to track objects",
+		)
+		return
+	}
+
+	// In case a ciphertext may be used several times, the usage is allowed to this limit
+	if numTracked, ntErr := d.Factory.GetTackedObjectUses(ctx, header.Uuid); ntErr != nil {
+		dg.AddError(
+			"Cannot assert the number of times this ciphertext was used",
+			fmt.Sprintf("Attempt to check how many times the ciphertext was previously used to create a resource erred: %s", ntErr.Error()),
+		)
+	} else if numTracked >= header.NumUses {
+		dg.AddError(
+			"Ciphertext has been used all time it was allowed to do so",
+			"The use of this ciphertext to create Azure objects has been exhaused. Re-encrypt and replace the ciphertext to continue.",
+		)
+	}
+}
+
+// checkCiphertextUsageByProvenanceTags checks the use of the single-use ciphertext where the provider doesn't track
+// objects, but stamps the provenance tags. The provenance tags of the object at the destination record the last
+// ciphertext used there. This reveals the repeated use of the ciphertext at the same destination only; the uses at
+// other destinations require the object tracking, which the warning tells the user.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) checkCiphertextUsageByProvenanceTags(ctx context.Context, reader ProvenanceTagReader[TMdl], header core.ConfidentialDataJsonHeader, data *TMdl, dg *diag.Diagnostics) {
+	tags, err := reader.GetDestinationProvenanceTags(ctx, data)
+	if err != nil {
+		dg.AddError(
+			"Cannot assert the number of times this ciphertext was used",
+			fmt.Sprintf("Attempt to read the provenance tags of the object at the destination erred: %s", err.Error()),
+		)
+		return
+	}
+
+	if tags[core.ProvenanceUuidTag] == header.Uuid {
+		dg.AddError(
+			"Ciphertext has been used all time it was allowed to do so",
+			"The provenance tags of the object at the destination show that this ciphertext was already used to create it. Re-encrypt and replace the ciphertext to continue.",
+		)
+		return
+	}
+
+	dg.AddWarning(
+		"Ciphertext use limit is not enforced across destinations",
+		"This provider does not track objects; the single use of this ciphertext is checked only against the provenance tags of the object at this destination. "+
+			"The ciphertext can still be used to create objects at other destinations. Configure the provider to track objects to enforce the limit.",
+	)
+}
+
+// stampProvenanceTags sets the provenance tags on the model of the resource whose Azure object carries these.
+// The tags are stamped only where the plan doesn't carry these from the state.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) stampProvenanceTags(header core.ConfidentialDataJsonHeader, confMdl ConfidentialMaterialModel, data *TMdl) {
+	taggedModel, ok := any(data).(ProvenanceTaggedModel)
+	if !ok || !taggedModel.IsProvenanceTagsUnknown() {
+		return
+	}
+
+	if tags := d.Factory.GetProvenanceTags(header, confMdl.EncryptedSecret.ValueString()); tags != nil {
+		taggedModel.SetProvenanceTags(tags)
+	}
+}
+
+// readFingerprint returns the fingerprint kept in the private state where it was computed for the ciphertext of
+// the resource. Returns nil where the ciphertext needs to be decrypted: the provider doesn't fingerprint the values,
+// the resource cannot read its confidential value, or the ciphertext has changed.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) readFingerprint(ctx context.Context, req RequestAbstraction, ciphertext string) *core.ConfidentialValueFingerprint {
+	if _, ok := d.MutableRU.(ConfidentialValueReader[TMdl, TConfData, AZAPIObject]); !ok || req.GetPrivate == nil {
+		return nil
+	}
+
+	key := d.Factory.GetFingerprintKey()
+	if len(key) == 0 {
+		return nil
+	}
+
+	fpJson, dg := req.GetPrivate(ctx, FingerprintPrivateStateKey)
+	if dg.HasError() || len(fpJson) == 0 {
+		return nil
+	}
+
+	fingerprint := core.ConfidentialValueFingerprint{}
+	if err := json.Unmarshal(fpJson, &fingerprint); err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Fingerprint kept in the private state cannot be read; the ciphertext will be decrypted: %s", err.Error()))
+		return nil
+	}
+
+	if !fingerprint.IsOf(key, ciphertext) {
+		tflog.Info(ctx, "Fingerprint kept in the private state was computed for another ciphertext; the ciphertext will be decrypted")
+		return nil
+	}
+
+	return &fingerprint
+}
+
+// readAgainstFingerprint reads the Azure object and compares its confidential value with the fingerprint.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) readAgainstFingerprint(ctx context.Context, data *TMdl, fingerprint core.ConfidentialValueFingerprint) (AZAPIObject, ResourceExistenceCheck, diag.Diagnostics) {
+	reader := d.MutableRU.(ConfidentialValueReader[TMdl, TConfData, AZAPIObject])
+
+	azObj, value, check, dg := reader.DoReadConfidentialValue(ctx, data)
+	if check == ResourceExists && !fingerprint.Matches(d.Factory.GetFingerprintKey(), value) {
+		tflog.Warn(ctx, "Confidential value of the Azure object does not match the fingerprint of the value placed from the ciphertext")
+		check = ResourceConfidentialDataDrift
+	}
+
+	return azObj, check, dg
+}
+
+// storeFingerprint keeps the fingerprint of the value placed from the ciphertext in the private state of the
+// resource, where the provider fingerprints the values and the resource can read its confidential value.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) storeFingerprint(ctx context.Context, setPrivate func(context.Context, string, []byte) diag.Diagnostics, ciphertext string, header core.ConfidentialDataJsonHeader, plainData TConfData, dg *diag.Diagnostics) {
+	reader, ok := d.MutableRU.(ConfidentialValueReader[TMdl, TConfData, AZAPIObject])
+	if !ok || setPrivate == nil {
+		return
+	}
+
+	key := d.Factory.GetFingerprintKey()
+	if len(key) == 0 {
+		return
+	}
+
+	fingerprint := core.NewConfidentialValueFingerprint(key, ciphertext, header, reader.ConfidentialValueOf(plainData))
+	fpJson, err := json.Marshal(fingerprint)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Fingerprint of the confidential value cannot be marshalled: %s", err.Error()))
+		return
+	}
+
+	dg.Append(setPrivate(ctx, FingerprintPrivateStateKey, fpJson)...)
+}
+
+// ValidateConfig performs the inexpensive check that the ciphertext is well-formed.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var content types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("content"), &content)...)
+	if resp.Diagnostics.HasError() || content.IsNull() || content.IsUnknown() {
+		return
+	}
+
+	if IsDriftMessage(content.ValueString()) {
+		return
+	}
+
+	em := core.EncryptedMessage{}
+	if emImportErr := em.FromBase64PEM(content.ValueString()); emImportErr != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Confidential content does not conform to the expected format",
+			fmt.Sprintf("Received this error while trying to parse the confidential message: %s. Confidential content shoud be produced either by tfgen tool or vai appropriate function", emImportErr.Error()),
+		)
+	}
+}
+
+// ModifyPlan verifies during the planning that the ciphertext can be used to create the resource (or to
+// update the resource where the ciphertext changes). This fails the plan rather than the apply, where
+// other resources could have been already changed.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || d.Factory == nil {
+		// The resource is destroyed, or the provider is not yet configured.
+		return
+	}
+
+	isCreate := req.State.Raw.IsNull() || len(resp.RequiresReplace) > 0
+
+	if !isCreate {
+		if d.MutableRU == nil && !IsMovedObject(ctx, req.Private) {
+			// In-place updates of immutable resources do not use the ciphertext, save for the adoption of the
+			// object moved from another resource
+			return
+		}
+
+		var planContent, stateContent types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("content"), &planContent)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("content"), &stateContent)...)
+		if resp.Diagnostics.HasError() || planContent.Equal(stateContent) {
+			return
+		}
+
+		// The changed ciphertext is stamped anew on the Azure object
+		if _, hasProvenanceTags := d.ResourceSchema.Attributes["provenance_tags"]; hasProvenanceTags && d.Factory.IsProvenanceTaggingEnabled() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("provenance_tags"), types.MapUnknown(types.StringType))...)
+		}
+	}
+
+	reqAbs := RequestAbstraction{
+		Get: req.Plan.Get,
+	}
+
+	resAbs := ResponseAbstraction{
+		Diagnostics: &resp.Diagnostics,
+	}
+
+	d.ModifyPlanT(ctx, reqAbs, isCreate, req.Config.Raw.IsFullyKnown(), resAbs)
+}
+
+// ModifyPlanT runs the checks of the ciphertext of the planned resource. The creation checks are run only
+// where the resource will be created; the placement is checked only where the configuration is fully known.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) ModifyPlanT(ctx context.Context, req RequestAbstraction, isCreate bool, configKnown bool, resp ResponseAbstraction) {
+	data := d.Specializer.NewTerraformModel()
+
+	resp.Diagnostics.Append(req.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	confMdl := d.Specializer.GetConfidentialMaterialFrom(data)
+	if confMdl.EncryptedSecret.IsUnknown() || confMdl.EncryptedSecret.IsNull() || !confMdl.WrappingKeyCoordinate.IsKnown() {
+		tflog.Info(ctx, "Ciphertext or wrapping key is not known during planning; checks are deferred to apply")
+		return
+	}
+	if IsDriftMessage(confMdl.EncryptedSecret.ValueString()) {
+		return
+	}
+
+	em := core.EncryptedMessage{}
+	if emImportErr := em.FromBase64PEM(confMdl.EncryptedSecret.ValueString()); emImportErr != nil {
+		resp.Diagnostics.AddError(
+			"Confidential content does not conform to the expected format",
+			fmt.Sprintf("Received this error while trying to parse the confidential message: %s. Confidential content shoud be produced either by tfgen tool or vai appropriate function", emImportErr.Error()),
+		)
+		return
+	}
+
+	rsaDecrypter := d.decrypterFor(ctx, confMdl, &data)
+
+	header, _, err := d.Specializer.Decrypt(ctx, em, rsaDecrypter)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Cannot decrypt ciphertext",
+			fmt.Sprintf("Received this error while trying to decrypt the confidential message: %s. Confidential content shoud be produced either by tfgen tool or vai appropriate function and encrypted with the public key that this provider is using.", err.Error()),
+		)
+		return
+	}
+
+	d.CheckCiphertextRevocation(ctx, header, resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.CheckCiphertextExpiry(ctx, header, resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if isCreate {
+		d.CheckCiphertextCreateExpiry(ctx, header, resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	d.CheckCiphertextNotBefore(ctx, header, resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if configKnown {
+		resp.Diagnostics.Append(d.Specializer.CheckPlacement(ctx, header.ProviderConstraints, header.PlacementConstraints, &data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		tflog.Info(ctx, "Configuration is not fully known during planning; placement check is deferred to apply")
+	}
+
+	if isCreate {
+		d.CheckCiphertextUsageLimits(ctx, header, &data, resp.Diagnostics)
+	}
+}
+
 func CreateDriftMessage(tkn string) string {
 	return fmt.Sprintf("---- DRIFT IN %s CONFIDENTIAL DATA ----", strings.ToUpper(tkn))
 }
@@ -374,18 +791,53 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) U
 		return
 	}
 
+	ctx, cancel := WithOperationTimeout(ctx, &data, timeouts.Value.Update, DefaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	var azObj AZAPIObject
+	var ciphertextUuid string
 	var dg diag.Diagnostics
 
+	// The object moved from another resource is adopted only where its confidential value is the plaintext of the
+	// ciphertext of the configuration
+	moved := IsMovedObject(ctx, req.Private)
+
 	if d.ImmutableRU != nil {
+		confMdl := d.Specializer.GetConfidentialMaterialFrom(data)
+
+		auditHeader := UnverifiedHeaderOf(confMdl)
+		defer func() {
+			d.recordAuditEvent(ctx, core.AuditOperationUpdate, auditHeader, &data, confMdl.WrappingKeyCoordinate, &resp.Diagnostics)
+		}()
+
+		if moved {
+			header := d.adoptMovedObject(ctx, &data, &resp.Diagnostics)
+			if len(header.Uuid) > 0 {
+				auditHeader = header
+			}
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			ciphertextUuid = header.Uuid
+		}
+
 		// Immutable read/update does not require decryption
 		azObj, dg = d.ImmutableRU.DoUpdate(ctx, &data)
+		if moved && !dg.HasError() {
+			d.trackCiphertextUse(ctx, ciphertextUuid, d.Specializer.GetDestinationLabel(&data), &resp.Diagnostics)
+		}
 	} else if d.MutableRU != nil {
 		// Mutable read/update requires decryption. This process is simplified compared to create because
 		// read operation should have done all the necessary checks.
 
 		confMdl := d.Specializer.GetConfidentialMaterialFrom(data)
 
+		auditHeader := UnverifiedHeaderOf(confMdl)
+		defer func() {
+			d.recordAuditEvent(ctx, core.AuditOperationUpdate, auditHeader, &data, confMdl.WrappingKeyCoordinate, &resp.Diagnostics)
+		}()
+
 		em := core.EncryptedMessage{}
 		if emImportErr := em.FromBase64PEM(confMdl.EncryptedSecret.ValueString()); emImportErr != nil {
 			resp.Diagnostics.AddError(
@@ -395,7 +847,7 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) U
 			return
 		}
 
-		rsaDecrypter := d.Factory.GetDecrypterFor(ctx, confMdl.WrappingKeyCoordinate)
+		rsaDecrypter := d.decrypterFor(ctx, confMdl, &data)
 
 		header, confData, err := d.Specializer.Decrypt(ctx, em, rsaDecrypter)
 		if err != nil {
@@ -405,28 +857,40 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) U
 			)
 			return
 		}
+		auditHeader = header
+		ciphertextUuid = header.Uuid
 
-		azObj, dg = d.MutableRU.DoUpdate(ctx, &data, confData)
+		d.CheckCiphertextRevocation(ctx, header, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 
-		// Track the object use
-		if d.Factory.IsObjectTrackingEnabled() {
-			objTracked, objTrackErr := d.Factory.IsObjectIdTracked(ctx, header.Uuid)
-			if objTrackErr != nil {
-				resp.Diagnostics.AddError(
-					"Could not verify object tracking status after update",
-					objTrackErr.Error(),
-				)
-			}
-			if !objTracked {
-				if trackErr := d.Factory.TrackObjectId(ctx, header.Uuid); trackErr != nil {
-					resp.Diagnostics.AddError(
-						"Could not track the ciphertext use at update",
-						trackErr.Error(),
-					)
-				}
+		d.CheckCiphertextNotBefore(ctx, header, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		destination := d.Specializer.GetDestinationLabel(&data)
+		d.CheckCiphertextDestination(ctx, header, destination, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if moved {
+			d.verifyMovedObjectValue(ctx, &data, confData, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
 			}
 		}
 
+		d.stampProvenanceTags(header, confMdl, &data)
+
+		azObj, dg = d.MutableRU.DoUpdate(ctx, &data, confData)
+		if !dg.HasError() {
+			d.storeFingerprint(ctx, resp.Private.SetKey, confMdl.EncryptedSecret.ValueString(), header, confData, &resp.Diagnostics)
+		}
+
+		d.trackCiphertextUse(ctx, header.Uuid, destination, &resp.Diagnostics)
 	} else {
 		resp.Diagnostics.AddError("Incomplete resource configuration", "This resource does not define read/update methods")
 		return
@@ -442,6 +906,191 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) U
 		resp.Diagnostics.Append(convertDiagnostics...)
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if moved {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, MovedObjectPrivateStateKey, nil)...)
+	}
+
+	var getPriorIdentity func(context.Context, path.Path, interface{}) diag.Diagnostics
+	if req.Identity != nil {
+		getPriorIdentity = req.Identity.GetAttribute
+	}
+	var setIdentity func(context.Context, interface{}) diag.Diagnostics
+	if resp.Identity != nil {
+		setIdentity = resp.Identity.Set
+	}
+	d.setIdentity(ctx, getPriorIdentity, setIdentity, &data, ciphertextUuid, &resp.Diagnostics)
+}
+
+// trackCiphertextUse tracks the use of the ciphertext at the destination, where the provider tracks the objects and
+// the use is not yet tracked.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) trackCiphertextUse(ctx context.Context, uuid string, destination string, dg *diag.Diagnostics) {
+	if !d.Factory.IsObjectTrackingEnabled() {
+		return
+	}
+
+	objTracked, objTrackErr := d.Factory.IsObjectIdTracked(ctx, uuid)
+	if objTrackErr != nil {
+		dg.AddError(
+			"Could not verify object tracking status after update",
+			objTrackErr.Error(),
+		)
+	}
+	if !objTracked {
+		if trackErr := d.Factory.TrackObjectId(ctx, uuid, destination); trackErr != nil {
+			dg.AddError(
+				"Could not track the ciphertext use at update",
+				trackErr.Error(),
+			)
+		}
+	}
+}
+
+// adoptMovedObject verifies that the immutable resource can adopt the Azure object moved from another resource
+// with the ciphertext of the configuration. The in-place update of the immutable resource doesn't otherwise decrypt
+// the ciphertext; the adoption runs the checks of the ciphertext that the create would have run, save for the
+// usage limits. Returns the header of the decrypted ciphertext.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) adoptMovedObject(ctx context.Context, data *TMdl, dg *diag.Diagnostics) core.ConfidentialDataJsonHeader {
+	confMdl := d.Specializer.GetConfidentialMaterialFrom(*data)
+
+	em := core.EncryptedMessage{}
+	if emImportErr := em.FromBase64PEM(confMdl.EncryptedSecret.ValueString()); emImportErr != nil {
+		dg.AddError(
+			"Confidential content does not conform to the expected format",
+			fmt.Sprintf("Received this error while trying to parse the confidential message: %s. Confidential content shoud be produced either by tfgen tool or vai appropriate function", emImportErr.Error()),
+		)
+		return core.ConfidentialDataJsonHeader{}
+	}
+
+	rsaDecrypter := d.decrypterFor(ctx, confMdl, data)
+
+	header, confData, err := d.Specializer.Decrypt(ctx, em, rsaDecrypter)
+	if err != nil {
+		dg.AddError(
+			"Cannot decrypt ciphertext",
+			fmt.Sprintf("Received this error while trying to decrypt the confidential message: %s. Confidential content shoud be produced either by tfgen tool or vai appropriate function and encrypted with the public key that this provider is using.", err.Error()),
+		)
+		return core.ConfidentialDataJsonHeader{}
+	}
+
+	d.CheckCiphertextRevocation(ctx, header, dg)
+	if dg.HasError() {
+		return header
+	}
+
+	d.CheckCiphertextExpiry(ctx, header, dg)
+	if dg.HasError() {
+		return header
+	}
+
+	d.CheckCiphertextNotBefore(ctx, header, dg)
+	if dg.HasError() {
+		return header
+	}
+
+	dg.Append(d.Specializer.CheckPlacement(ctx, header.ProviderConstraints, header.PlacementConstraints, data)...)
+	if dg.HasError() {
+		return header
+	}
+
+	d.CheckCiphertextDestination(ctx, header, d.Specializer.GetDestinationLabel(data), dg)
+	if dg.HasError() {
+		return header
+	}
+
+	d.verifyMovedObjectValue(ctx, data, confData, dg)
+	if dg.HasError() {
+		return header
+	}
+
+	d.stampProvenanceTags(header, confMdl, data)
+	return header
+}
+
+// verifyMovedObjectValue verifies that the Azure object moved into the resource from another resource is at the
+// configured destination, and that its confidential value is the plaintext of the ciphertext adopting it.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) verifyMovedObjectValue(ctx context.Context, data *TMdl, confData TConfData, dg *diag.Diagnostics) {
+	if verifier, ok := d.Specializer.(MovedObjectDestinationVerifier[TMdl]); ok {
+		if err := verifier.VerifyMovedObjectDestination(data); err != nil {
+			dg.AddError(
+				"Moved object is not at the configured destination",
+				fmt.Sprintf("The Azure object moved into this resource from another resource cannot be adopted: %s. Correct the destination in the configuration, or remove the object from the state instead.", err.Error()),
+			)
+			return
+		}
+	}
+
+	var reader ConfidentialValueReader[TMdl, TConfData, AZAPIObject]
+	var ok bool
+	if d.MutableRU != nil {
+		reader, ok = d.MutableRU.(ConfidentialValueReader[TMdl, TConfData, AZAPIObject])
+	} else {
+		reader, ok = d.ImmutableRU.(ConfidentialValueReader[TMdl, TConfData, AZAPIObject])
+	}
+
+	if !ok {
+		dg.AddError(
+			"Moved object cannot be adopted",
+			"This resource cannot read the confidential value of the Azure object moved into it from another resource. Remove the object from the state instead.",
+		)
+		return
+	}
+
+	_, value, check, readDg := reader.DoReadConfidentialValue(ctx, data)
+	dg.Append(readDg...)
+	if dg.HasError() {
+		return
+	}
+
+	if check != ResourceExists {
+		dg.AddError(
+			"Moved object does not exist",
+			"The Azure object moved into this resource from another resource no longer exists. Remove the object from the state instead.",
+		)
+		return
+	}
+
+	if !bytes.Equal(value, reader.ConfidentialValueOf(confData)) {
+		dg.AddError(
+			"Moved object does not match the ciphertext",
+			"The confidential value of the Azure object moved into this resource from another resource differs from the plaintext of the ciphertext. Supply the ciphertext of the current value of the object.",
+		)
+	}
+}
+
+// setIdentity sets the identity of the resource where the specializer declares it. The identity must not change
+// during the lifecycle of the Azure object; the ciphertext uuid recorded in the prior identity is therefore kept
+// after the ciphertext of the resource is replaced. Where neither the prior identity nor the decrypted ciphertext
+// gives the uuid (e.g. reading the immutable resource created before the identity was declared), the uuid the
+// ciphertext declares in its unencrypted headers is recorded.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) setIdentity(ctx context.Context, getPrior func(context.Context, path.Path, interface{}) diag.Diagnostics, setIdentity func(context.Context, interface{}) diag.Diagnostics, data *TMdl, ciphertextUuid string, dg *diag.Diagnostics) {
+	specializer, ok := d.Specializer.(IdentitySpecialization[TMdl, TIdentity])
+	if !ok || setIdentity == nil {
+		return
+	}
+
+	if getPrior != nil {
+		var priorUuid types.String
+		if priorDg := getPrior(ctx, path.Root(CiphertextUuidIdentityAttribute), &priorUuid); !priorDg.HasError() && len(priorUuid.ValueString()) > 0 {
+			ciphertextUuid = priorUuid.ValueString()
+		}
+	}
+	if len(ciphertextUuid) == 0 {
+		ciphertextUuid = UnverifiedHeaderOf(d.Specializer.GetConfidentialMaterialFrom(*data)).Uuid
+	}
+	if len(ciphertextUuid) == 0 {
+		// E.g. the object moved from another resource is yet to be adopted with a ciphertext; the identity
+		// is set once it is.
+		tflog.Debug(ctx, "Ciphertext uuid is not known; the identity of the resource is not set")
+		return
+	}
+
+	identity, err := specializer.IdentityOf(data, ciphertextUuid)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Identity of the resource cannot be determined: %s", err.Error()))
+		return
+	}
+
+	dg.Append(setIdentity(ctx, &identity)...)
 }
 
 func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -453,9 +1102,67 @@ func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) D
 		return
 	}
 
+	ctx, cancel := WithOperationTimeout(ctx, &data, timeouts.Value.Delete, DefaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+
 	dg := d.Specializer.DoDelete(ctx, &data)
 	resp.Diagnostics.Append(dg...)
+
+	// The delete operation does not decrypt the ciphertext; the audit event records the unverified
+	// uuid and type declared in the ciphertext headers.
+	confMdl := d.Specializer.GetConfidentialMaterialFrom(data)
+	d.recordAuditEvent(ctx, core.AuditOperationDelete, UnverifiedHeaderOf(confMdl), &data, confMdl.WrappingKeyCoordinate, &resp.Diagnostics)
+}
+
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) recordAuditEvent(ctx context.Context, op core.AuditOperation, header core.ConfidentialDataJsonHeader, data *TMdl, coord *core.WrappingKeyCoordinateModel, diagnostics *diag.Diagnostics) {
+	event := core.AuditEvent{
+		Operation:      op,
+		CiphertextUuid: header.Uuid,
+		ObjectType:     header.Type,
+		Destination:    d.Specializer.GetDestinationLabel(data),
+		Outcome:        core.AuditOutcomeSuccess,
+	}
+
+	if diagnostics.HasError() {
+		event.Outcome = core.AuditOutcomeFailure
+		for _, dg := range diagnostics.Errors() {
+			event.Message = dg.Summary()
+			break
+		}
+	}
+
+	d.Factory.RecordAuditEvent(ctx, event, coord, diagnostics)
+}
+
+// decrypterFor returns the decrypter of the ciphertext of the resource. The decrypt audit events are attributed
+// to the uuid and the type which the ciphertext declares and to the destination of the resource.
+func (d *ConfidentialGenericResource[TMdl, TIdentity, TConfData, AZAPIObject]) decrypterFor(ctx context.Context, confMdl ConfidentialMaterialModel, data *TMdl) core.RSADecrypter {
+	header := UnverifiedHeaderOf(confMdl)
+	ctx = core.WithAuditSubject(ctx, core.AuditSubject{
+		CiphertextUuid: header.Uuid,
+		ObjectType:     header.Type,
+		Destination:    d.Specializer.GetDestinationLabel(data),
+	})
+
+	return d.Factory.GetDecrypterFor(ctx, confMdl.WrappingKeyCoordinate)
+}
+
+// UnverifiedHeaderOf returns the header which the ciphertext declares
+// in its unencrypted headers. These values are not authenticated and must only be used where decryption of
+// the ciphertext is not possible or not desirable, e.g. for the audit of delete operations.
+func UnverifiedHeaderOf(confMdl ConfidentialMaterialModel) core.ConfidentialDataJsonHeader {
+	em := core.EncryptedMessage{}
+	if err := em.FromBase64PEM(confMdl.EncryptedSecret.ValueString()); err != nil {
+		return core.ConfidentialDataJsonHeader{}
+	}
+
+	return core.UnverifiedHeaderOf(em)
 }
 
 // Ensure compilation of the resources
 var _ resource.Resource = &ConfidentialGenericResource[string, int, int, string]{}
+var _ resource.ResourceWithModifyPlan = &ConfidentialGenericResource[string, int, int, string]{}
+var _ resource.ResourceWithValidateConfig = &ConfidentialGenericResource[string, int, int, string]{}
+var _ resource.ResourceWithIdentity = &ConfidentialGenericResource[string, int, int, string]{}
+var _ resource.ResourceWithUpgradeState = &ConfidentialGenericResource[string, int, int, string]{}
+var _ resource.ResourceWithMoveState = &ConfidentialGenericResource[string, int, int, string]{}
@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"crypto/rsa"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -36,9 +38,9 @@ type AZClientsFactoryMock struct {
 	core.AZClientsFactory
 }
 
-func (azm *AZClientsFactoryMock) GetMergedWrappingKeyCoordinate(ctx context.Context, param *core.WrappingKeyCoordinateModel, diag *diag.Diagnostics) core.WrappingKeyCoordinate {
-	args := azm.Called(ctx, param, diag)
-	return args.Get(0).(core.WrappingKeyCoordinate)
+func (azm *AZClientsFactoryMock) GetMergedWrappingKeyCoordinate(ctx context.Context, param *core.WrappingKeyCoordinateModel) (core.WrappingKeyCoordinate, error) {
+	rv := azm.Called(ctx, param)
+	return rv.Get(0).(core.WrappingKeyCoordinate), rv.Error(1)
 }
 
 func (azm *AZClientsFactoryMock) GetDecrypterFor(ctx context.Context, coord *core.WrappingKeyCoordinateModel) core.RSADecrypter {
@@ -46,6 +48,24 @@ func (azm *AZClientsFactoryMock) GetDecrypterFor(ctx context.Context, coord *cor
 	return args.Get(0).(core.RSADecrypter)
 }
 
+func (azm *AZClientsFactoryMock) RecordAuditEvent(ctx context.Context, event core.AuditEvent, coord *core.WrappingKeyCoordinateModel, diagnostics *diag.Diagnostics) {
+	azm.Called(ctx, event, coord, diagnostics)
+}
+
+func (azm *AZClientsFactoryMock) GivenAuditEventsAreRecorded() {
+	azm.On("RecordAuditEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+}
+
+func (azm *AZClientsFactoryMock) AssertAuditEventRecorded(t *testing.T, op core.AuditOperation, outcome core.AuditOutcome) {
+	azm.AssertCalled(t, "RecordAuditEvent",
+		mock.Anything,
+		mock.MatchedBy(func(e core.AuditEvent) bool {
+			return e.Operation == op && e.Outcome == outcome && e.Destination == "unit-test-destination" && len(e.CiphertextUuid) > 0
+		}),
+		mock.Anything,
+		mock.Anything)
+}
+
 func (azm *AZClientsFactoryMock) IsObjectTrackingEnabled() bool {
 	args := azm.Called()
 	return args.Get(0).(bool)
@@ -56,15 +76,108 @@ func (azm *AZClientsFactoryMock) GetTackedObjectUses(ctx context.Context, uuid s
 	return args.Int(0), args.Error(1)
 }
 
-func (azm *AZClientsFactoryMock) TrackObjectId(ctx context.Context, uuid string) error {
-	args := azm.Called(ctx, uuid)
+func (azm *AZClientsFactoryMock) TrackObjectId(ctx context.Context, uuid string, destination string) error {
+	args := azm.Called(ctx, uuid, destination)
 	return args.Error(0)
 }
 
+func (azm *AZClientsFactoryMock) GetExpiryWarningWindow() time.Duration {
+	args := azm.Called()
+	return args.Get(0).(time.Duration)
+}
+
+func (azm *AZClientsFactoryMock) GivenExpiryWarningWindow(d time.Duration) {
+	azm.On("GetExpiryWarningWindow").Return(d).Maybe()
+}
+
+func (azm *AZClientsFactoryMock) IsProvenanceTaggingEnabled() bool {
+	args := azm.Called()
+	return args.Bool(0)
+}
+
+func (azm *AZClientsFactoryMock) GetProvenanceTags(header core.ConfidentialDataJsonHeader, ciphertext string) map[string]string {
+	args := azm.Called(header, ciphertext)
+	return args.Get(0).(map[string]string)
+}
+
+func (azm *AZClientsFactoryMock) GivenProvenanceTaggingIsNotConfigured() {
+	azm.On("IsProvenanceTaggingEnabled").Return(false).Maybe()
+	azm.On("GetProvenanceTags", mock.Anything, mock.Anything).Return(map[string]string(nil)).Maybe()
+}
+
+func (azm *AZClientsFactoryMock) GivenProvenanceTagging() {
+	azm.withoutExpectedCalls("IsProvenanceTaggingEnabled")
+	azm.withoutExpectedCalls("GetProvenanceTags")
+
+	azm.On("IsProvenanceTaggingEnabled").Return(true)
+	azm.On("GetProvenanceTags", mock.Anything, mock.Anything).Return(map[string]string{
+		core.ProvenanceUuidTag: "unit-test-uuid",
+	}).Maybe()
+}
+
+func (azm *AZClientsFactoryMock) GetFingerprintKey() []byte {
+	args := azm.Called()
+	return args.Get(0).([]byte)
+}
+
+func (azm *AZClientsFactoryMock) GivenFingerprintingIsNotConfigured() {
+	azm.On("GetFingerprintKey").Return([]byte(nil)).Maybe()
+}
+
+func (azm *AZClientsFactoryMock) GivenFingerprintKey(key []byte) {
+	azm.withoutExpectedCalls("GetFingerprintKey")
+	azm.On("GetFingerprintKey").Return(key)
+}
+
+func (azm *AZClientsFactoryMock) GetCiphertextRevocation(ctx context.Context, uuid string) (*core.CiphertextRevocation, error) {
+	rv := azm.Called(ctx, uuid)
+	return rv.Get(0).(*core.CiphertextRevocation), rv.Error(1)
+}
+
+func (azm *AZClientsFactoryMock) GivenCiphertextIsNotRevoked() {
+	azm.On("GetCiphertextRevocation", mock.Anything, mock.Anything).Return((*core.CiphertextRevocation)(nil), nil).Maybe()
+}
+
+// withoutExpectedCalls removes the expectations of the method, e.g. the defaults registered by the test setup
+func (azm *AZClientsFactoryMock) withoutExpectedCalls(method string) {
+	var calls []*mock.Call
+	for _, c := range azm.ExpectedCalls {
+		if c.Method != method {
+			calls = append(calls, c)
+		}
+	}
+	azm.ExpectedCalls = calls
+}
+
+func (azm *AZClientsFactoryMock) GivenCiphertextIsRevoked(reason string) {
+	azm.withoutExpectedCalls("GetCiphertextRevocation")
+	azm.On("GetCiphertextRevocation", mock.Anything, mock.Anything).Return(&core.CiphertextRevocation{Reason: reason}, nil)
+}
+
 func (azm *AZClientsFactoryMock) GivenObjectTrackingConfigured(how bool) {
+	azm.withoutExpectedCalls("IsObjectTrackingEnabled")
 	azm.On("IsObjectTrackingEnabled").Return(how)
 }
 
+func (azm *AZClientsFactoryMock) GivenObjectTrackingIsNotConfigured() {
+	azm.On("IsObjectTrackingEnabled").Return(false).Maybe()
+}
+
+func (azm *AZClientsFactoryMock) ListTrackedObjects(ctx context.Context, uuids []string) ([]core.TrackedObject, error) {
+	args := azm.Called(ctx, uuids)
+	return args.Get(0).([]core.TrackedObject), args.Error(1)
+}
+
+func (azm *AZClientsFactoryMock) GivenCiphertextTrackedAt(destinations ...string) {
+	azm.On("ListTrackedObjects", mock.Anything, mock.Anything).Return([]core.TrackedObject{
+		{
+			NumUses:      len(destinations),
+			Destination:  destinations[0],
+			Destinations: destinations,
+		},
+	}, nil)
+}
+
 func (azm *AZClientsFactoryMock) GivenGetTackedObjectUsesErrs(errMsg string) {
 	azm.On("GetTackedObjectUses", mock.Anything, mock.Anything).Return(0, errors.New(errMsg))
 }
@@ -78,11 +191,11 @@ func (azm *AZClientsFactoryMock) GivenGetTackedObjectUses(n int) {
 }
 
 func (azm *AZClientsFactoryMock) GivenTrackObjectIdErrs() {
-	azm.On("TrackObjectId", mock.Anything, mock.Anything).Return(errors.New("unit-test-tracking-error"))
+	azm.On("TrackObjectId", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("unit-test-tracking-error"))
 }
 
 func (azm *AZClientsFactoryMock) GivenTrackObject() {
-	azm.On("TrackObjectId", mock.Anything, mock.Anything).Return(nil)
+	azm.On("TrackObjectId", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 }
 
 type TerraformRequestMock struct {
@@ -127,15 +240,68 @@ func (s *TerraformRequestMock) GivenGetDiagnosesError(expValue interface{}, msg
 		Return(rv)
 }
 
+func (s *TerraformRequestMock) GetPrivate(ctx context.Context, key string) ([]byte, diag.Diagnostics) {
+	args := s.Mock.Called(ctx, key)
+	return args.Get(0).([]byte), args.Get(1).(diag.Diagnostics)
+}
+
+func (s *TerraformRequestMock) GivenPrivateState(key string, value []byte) {
+	s.On("GetPrivate", mock.Anything, key).Return(value, diag.Diagnostics{})
+}
+
+func (s *TerraformRequestMock) SetPrivate(ctx context.Context, key string, value []byte) diag.Diagnostics {
+	args := s.Mock.Called(ctx, key, value)
+	return args.Get(0).(diag.Diagnostics)
+}
+
+func (s *TerraformRequestMock) ThenPrivateStateIsSet(key string) {
+	s.On("SetPrivate", mock.Anything, key, mock.Anything).
+		Once().
+		Return(diag.Diagnostics{})
+}
+
+func (s *TerraformRequestMock) GetIdentityAttribute(ctx context.Context, p path.Path, target interface{}) diag.Diagnostics {
+	args := s.Mock.Called(ctx, p, target)
+	return args.Get(0).(diag.Diagnostics)
+}
+
+func (s *TerraformRequestMock) GivenPriorIdentityCiphertextUuid(uuid string) {
+	s.On("GetIdentityAttribute", mock.Anything, path.Root(CiphertextUuidIdentityAttribute), mock.Anything).
+		Run(func(args mock.Arguments) {
+			*(args.Get(2).(*types.String)) = types.StringValue(uuid)
+		}).
+		Return(diag.Diagnostics{})
+}
+
+func (s *TerraformRequestMock) GivenNoPriorIdentity() {
+	s.On("GetIdentityAttribute", mock.Anything, path.Root(CiphertextUuidIdentityAttribute), mock.Anything).
+		Return(diag.Diagnostics{})
+}
+
+func (s *TerraformRequestMock) SetIdentity(ctx context.Context, val interface{}) diag.Diagnostics {
+	args := s.Mock.Called(ctx, val)
+	return args.Get(0).(diag.Diagnostics)
+}
+
+func (s *TerraformRequestMock) ThenIdentityIsSet(matcher func(identity *UnitTestIdentityModel) bool) {
+	s.On("SetIdentity", mock.Anything, mock.MatchedBy(matcher)).
+		Once().
+		Return(diag.Diagnostics{})
+}
+
 func (s *TerraformRequestMock) AsRequestAbstraction() RequestAbstraction {
 	return RequestAbstraction{
-		Get: s.Get,
+		Get:                  s.Get,
+		GetPrivate:           s.GetPrivate,
+		GetIdentityAttribute: s.GetIdentityAttribute,
 	}
 }
 
 func (s *TerraformRequestMock) AsResponseAbstraction() ResponseAbstraction {
 	return ResponseAbstraction{
 		Set:            s.Set,
+		SetPrivate:     s.SetPrivate,
+		SetIdentity:    s.SetIdentity,
 		RemoveResource: s.RemoveResource,
 		Diagnostics:    s.Diagnostic,
 	}
@@ -158,7 +324,16 @@ type SpecializerMock[TMdl any, TConfData any, AZAPIObject any] struct {
 
 func (sm *SpecializerMock[TMdl, TConfData, AZAPIObject]) Decrypt(ctx context.Context, em core.EncryptedMessage, decr core.RSADecrypter) (core.ConfidentialDataJsonHeader, TConfData, error) {
 	args := sm.Mock.Called(ctx, em, decr)
-	return args.Get(0).(core.ConfidentialDataJsonHeader), args.Get(1).(TConfData), nil
+	return args.Get(0).(core.ConfidentialDataJsonHeader), args.Get(1).(TConfData), args.Error(2)
+}
+
+func (sm *SpecializerMock[TMdl, TConfData, AZAPIObject]) GetDestinationProvenanceTags(ctx context.Context, tfModel *TMdl) (map[string]string, error) {
+	args := sm.Called(ctx, tfModel)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+func (sm *SpecializerMock[TMdl, TConfData, AZAPIObject]) GivenDestinationProvenanceTags(tags map[string]string) {
+	sm.On("GetDestinationProvenanceTags", mock.Anything, mock.Anything).Return(tags, nil)
 }
 
 func (sm *SpecializerMock[TMdl, TConfData, AZAPIObject]) GivenDecryptErrs(defaultValue TConfData, msg string) {
@@ -229,6 +404,11 @@ func (sm *SpecializerMock[TMdl, TConfData, AzAPIObject]) DoDelete(ctx context.Co
 	return args.Get(0).(diag.Diagnostics)
 }
 
+func (sm *SpecializerMock[TMdl, TConfData, AzAPIObject]) GetDestinationLabel(tfModel *TMdl) string {
+	args := sm.Called(tfModel)
+	return args.String(0)
+}
+
 func (sm *SpecializerMock[TMdl, TConfData, AzAPIObject]) CheckPlacement(ctx context.Context, providerConstraints []core.ProviderConstraint, placementConstraints []core.PlacementConstraint, tfModel *TMdl) diag.Diagnostics {
 	args := sm.Called(ctx, providerConstraints, placementConstraints, tfModel)
 	return args.Get(0).(diag.Diagnostics)
@@ -240,6 +420,30 @@ func (s *SpecializerMock[TMdl, TConfData, AzAPIObject]) ThenAzValueIsConvertedTo
 		Return(diag.Diagnostics{})
 }
 
+// UnitTestIdentityModel the identity of the resource under the test
+type UnitTestIdentityModel struct {
+	Destination    string `tfsdk:"destination"`
+	CiphertextUuid string `tfsdk:"ciphertext_uuid"`
+}
+
+// IdentifyingSpecializerMock the specializer of the resource declaring the resource identity
+type IdentifyingSpecializerMock struct {
+	*SpecializerMock[string, core.ConfidentialStringData, string]
+}
+
+func (sm *IdentifyingSpecializerMock) IdentityOf(tfModel *string, ciphertextUuid string) (UnitTestIdentityModel, error) {
+	return UnitTestIdentityModel{Destination: *tfModel, CiphertextUuid: ciphertextUuid}, nil
+}
+
+type DestinationVerifyingSpecializerMock struct {
+	*SpecializerMock[string, core.ConfidentialStringData, string]
+}
+
+func (sm *DestinationVerifyingSpecializerMock) VerifyMovedObjectDestination(tfModel *string) error {
+	args := sm.Called(tfModel)
+	return args.Error(0)
+}
+
 type ImmutableRUMock[TMdl, AZAPIObject any] struct {
 	mock.Mock
 }
@@ -277,6 +481,21 @@ func (s *MutableRUMock[TMdl, TConfData, AZAPIObject]) ThenDriftWillBeSet(modelVa
 		Once()
 }
 
+// ValueReadingMutableRUMock the mutable read/update that reads the confidential value of the Azure object
+type ValueReadingMutableRUMock[TMdl, TConfData, AZAPIObject any] struct {
+	MutableRUMock[TMdl, TConfData, AZAPIObject]
+}
+
+func (mm *ValueReadingMutableRUMock[TMdl, TConfData, AZAPIObject]) ConfidentialValueOf(plainData TConfData) []byte {
+	args := mm.Called(plainData)
+	return args.Get(0).([]byte)
+}
+
+func (mm *ValueReadingMutableRUMock[TMdl, TConfData, AZAPIObject]) DoReadConfidentialValue(ctx context.Context, planData *TMdl) (AZAPIObject, []byte, ResourceExistenceCheck, diag.Diagnostics) {
+	args := mm.Called(ctx, planData)
+	return args[0].(AZAPIObject), args[1].([]byte), args[2].(ResourceExistenceCheck), diag.Diagnostics{}
+}
+
 type GenericResourceTestContext struct {
 	FactoryMock  *AZClientsFactoryMock
 	RequestMock  *TerraformRequestMock
@@ -286,7 +505,7 @@ type GenericResourceTestContext struct {
 	MutableRU   *MutableRUMock[string, core.ConfidentialStringData, string]
 
 	SpecializerMock   *SpecializerMock[string, core.ConfidentialStringData, string]
-	ResourceUnderTest *ConfidentialGenericResource[string, int, core.ConfidentialStringData, string]
+	ResourceUnderTest *ConfidentialGenericResource[string, UnitTestIdentityModel, core.ConfidentialStringData, string]
 }
 
 func (grtc *GenericResourceTestContext) GivenImmutableRUReturnsError(v string, errMsg string) {
@@ -397,6 +616,24 @@ func (grtc *GenericResourceTestContext) GivenCiphertextExpiringIn3Months(t *test
 	grtc.ResourceUnderTest.MutableRU = grtc.MutableRU
 }
 
+func (grtc *GenericResourceTestContext) GivenNotYetActiveCiphertext(t *testing.T, mdl string) {
+	md := core.SecondaryProtectionParameters{
+		ProviderConstraints:  nil,
+		PlacementConstraints: nil,
+		CreateLimit:          0,
+		NotBefore:            time.Now().Unix() + 1000,
+		Expiry:               time.Now().Unix() + int64(time.Hour*24*31*3/time.Second),
+		NumUses:              0,
+	}
+
+	grtc.givenCiphertextOperations(t, mdl, md)
+
+	if grtc.MutableRU == nil {
+		grtc.MutableRU = &MutableRUMock[string, core.ConfidentialStringData, string]{}
+	}
+	grtc.ResourceUnderTest.MutableRU = grtc.MutableRU
+}
+
 func (grtc *GenericResourceTestContext) GivenUseLimitedCiphertext(t *testing.T, mdl string, nUses int) {
 	md := core.SecondaryProtectionParameters{
 		ProviderConstraints:  nil,
@@ -442,6 +679,25 @@ func (grtc *GenericResourceTestContext) givenCiphertextOperations(t *testing.T,
 	grtc.FactoryMock.On("GetDecrypterFor", mock.Anything, mock.Anything).Return(rsaDecrypter).Maybe()
 }
 
+func (grtc *GenericResourceTestContext) GivenUndecryptableCiphertext(t *testing.T, mdl string) core.ConfidentialDataJsonHeader {
+	helper := core.NewVersionedStringConfidentialDataHelper(UnitTestObjectType)
+	_ = helper.CreateConfidentialStringData("this is a secret message", core.SecondaryProtectionParameters{})
+
+	rsaKey, err := core.LoadPublicKeyFromData(testkeymaterial.EphemeralRsaPublicKey)
+	assert.Nil(t, err, "Failed to load public key")
+
+	em, err := helper.ToEncryptedMessage(rsaKey)
+	assert.Nil(t, err, "Failed to encrypted message")
+
+	grtc.SpecializerMock.On("GetConfidentialMaterialFrom", mdl).Return(ConfidentialMaterialModel{
+		EncryptedSecret: types.StringValue(em.ToBase64PEM()),
+	})
+	grtc.SpecializerMock.GivenDecryptErrs(helper.KnowValue, "wrapping key is not available")
+	grtc.FactoryMock.On("GetDecrypterFor", mock.Anything, mock.Anything).Return(core.RSADecrypter(nil)).Maybe()
+
+	return helper.Header
+}
+
 func (grtc *GenericResourceTestContext) GivenImmutableRUReturns(v string, state ResourceExistenceCheck) {
 
 	dg := diag.Diagnostics{}
@@ -480,6 +736,10 @@ func (grtc *GenericResourceTestContext) GivenMutableRUReturns(v string, state Re
 	grtc.ResourceUnderTest.MutableRU = iru
 }
 
+func (grtc *GenericResourceTestContext) GivenResourceDeclaresIdentity() {
+	grtc.ResourceUnderTest.Specializer = &IdentifyingSpecializerMock{grtc.SpecializerMock}
+}
+
 func (grtc *GenericResourceTestContext) AssertResponseHasError(t *testing.T, errSummary string) {
 	assert.True(t, grtc.ResponseMock.Diagnostic.HasError())
 
@@ -546,8 +806,15 @@ func givenSetup() GenericResourceTestContext {
 	}
 
 	sMock.On("NewTerraformModel").Return("InitialModelValue").Once()
-
-	cgr := ConfidentialGenericResource[string, int, core.ConfidentialStringData, string]{
+	sMock.On("GetDestinationLabel", mock.Anything).Return("unit-test-destination").Maybe()
+	factoryMock.GivenAuditEventsAreRecorded()
+	factoryMock.GivenExpiryWarningWindow(core.ExpiryWarningWindowOf(core.DefaultExpiryWarningDays))
+	factoryMock.GivenCiphertextIsNotRevoked()
+	factoryMock.GivenObjectTrackingIsNotConfigured()
+	factoryMock.GivenProvenanceTaggingIsNotConfigured()
+	factoryMock.GivenFingerprintingIsNotConfigured()
+
+	cgr := ConfidentialGenericResource[string, UnitTestIdentityModel, core.ConfidentialStringData, string]{
 		ConfidentialResourceBase: ConfidentialResourceBase{
 			CommonConfidentialResource{
 				Factory: factoryMock,
@@ -740,6 +1007,46 @@ func Test_Template_ReadMURU_IfCiphertextCannotBePlaced(t *testing.T) {
 	testCtx.AssertResponseHasError(t, "NonPlaceableObject")
 }
 
+func Test_Template_ReadMURU_IfCiphertextCopiedFromAnotherDestination(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenCiphertextExpiringIn3Months(t, "InitialModelValue")
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.FactoryMock.GivenObjectTrackingConfigured(true)
+	testCtx.FactoryMock.GivenCiphertextTrackedAt("other-destination")
+
+	testCtx.ResourceUnderTest.ReadT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Ciphertext is bound to another destination")
+}
+
+func Test_Template_ReadMURU_IfCiphertextTrackedAtThisDestination(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenCiphertextExpiringIn3Months(t, "InitialModelValue")
+	testCtx.GivenMutableRUReturns("InitialModelValue", ResourceExists)
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.FactoryMock.GivenObjectTrackingConfigured(true)
+	testCtx.FactoryMock.GivenCiphertextTrackedAt("other-destination", "unit-test-destination")
+
+	testCtx.SpecializerMock.ThenAzValueIsConvertedToTerraform("OkayModel", "InitialModelValue", StringComparator)
+	testCtx.ResponseMock.ThenTerraformModelIsSet("InitialModelValue")
+
+	testCtx.ResourceUnderTest.ReadT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	assert.Equal(t, 0, len(*testCtx.ResponseMock.Diagnostic))
+}
+
 func Test_Template_ReadMURU_IfResourceIsNotFound(t *testing.T) {
 	testCtx := givenSetup()
 
@@ -818,6 +1125,37 @@ func Test_Template_Create_IfCiphertextExpired(t *testing.T) {
 	testCtx.AssertResponseHasError(t, "Ciphertext has expired")
 }
 
+func Test_Template_Create_IfCiphertextRevoked(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.FactoryMock.GivenCiphertextIsRevoked("leaked")
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenCiphertextExpiringIn3Months(t, "InitialModelValue")
+
+	testCtx.ResourceUnderTest.CreateT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Ciphertext has been revoked")
+}
+
+func Test_Template_Create_IfCiphertextNotYetActive(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenNotYetActiveCiphertext(t, "InitialModelValue")
+
+	testCtx.ResourceUnderTest.CreateT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Ciphertext is not yet active")
+}
+
 func Test_Template_Create_IfCiphertextCannotBePlaced(t *testing.T) {
 	testCtx := givenSetup()
 
@@ -834,6 +1172,52 @@ func Test_Template_Create_IfCiphertextCannotBePlaced(t *testing.T) {
 	testCtx.AssertResponseHasError(t, "NonPlaceableObject")
 }
 
+func Test_Template_CheckUsageLimits_ByProvenanceTags(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.FactoryMock.GivenProvenanceTagging()
+	testCtx.SpecializerMock.GivenDestinationProvenanceTags(map[string]string{
+		core.ProvenanceUuidTag: "unit-test-uuid",
+	})
+
+	mdl := "InitialModelValue"
+
+	dg := diag.Diagnostics{}
+	testCtx.ResourceUnderTest.CheckCiphertextUsageLimits(context.Background(), core.ConfidentialDataJsonHeader{Uuid: "unit-test-uuid", NumUses: 1}, &mdl, &dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Ciphertext has been used all time it was allowed to do so", dg.Errors()[0].Summary())
+
+}
+
+func Test_Template_CheckUsageLimits_ByProvenanceTags_AtOtherDestination(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.FactoryMock.GivenProvenanceTagging()
+	// The ciphertext was used at another destination; the object at this destination came from other ciphertext
+	testCtx.SpecializerMock.GivenDestinationProvenanceTags(map[string]string{
+		core.ProvenanceUuidTag: "other-uuid",
+	})
+
+	mdl := "InitialModelValue"
+
+	dg := diag.Diagnostics{}
+	testCtx.ResourceUnderTest.CheckCiphertextUsageLimits(context.Background(), core.ConfidentialDataJsonHeader{Uuid: "unit-test-uuid", NumUses: 1}, &mdl, &dg)
+	assert.False(t, dg.HasError())
+	assert.Equal(t, 1, dg.WarningsCount())
+	assert.Equal(t, "Ciphertext use limit is not enforced across destinations", dg.Warnings()[0].Summary())
+}
+
+func Test_Template_CheckUsageLimits_ByProvenanceTags_RequiresTrackerForMultipleUses(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.FactoryMock.GivenProvenanceTagging()
+
+	mdl := "InitialModelValue"
+
+	dg := diag.Diagnostics{}
+	testCtx.ResourceUnderTest.CheckCiphertextUsageLimits(context.Background(), core.ConfidentialDataJsonHeader{Uuid: "unit-test-uuid", NumUses: 2}, &mdl, &dg)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "Insecure provider configuration", dg.Errors()[0].Summary())
+	testCtx.SpecializerMock.AssertNotCalled(t, "GetDestinationProvenanceTags", mock.Anything, mock.Anything)
+}
+
 func Test_Template_Create_UseLimitedCiphertextDeployedOverNonTrackingProvider(t *testing.T) {
 	testCtx := givenSetup()
 
@@ -1035,3 +1419,512 @@ func Test_Template_Create_AzObjectCreatedWithWarningOnLastUse(t *testing.T) {
 	testCtx.AssertResponseHasNoError(t)
 	testCtx.AssertResponseHasWarning(t, "No more resource create are possible")
 }
+
+func Test_Template_Create_RecordsAuditOfSuccessfulCreate(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenUseLimitedCiphertext(t, "InitialModelValue", 0)
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.SpecializerMock.GivenCreate("InitialModelValue")
+
+	testCtx.SpecializerMock.ThenAzValueIsConvertedToTerraform("CreatedAzureObject", "InitialModelValue", StringComparator)
+	testCtx.ResponseMock.ThenTerraformModelIsSet("InitialModelValue")
+
+	testCtx.ResourceUnderTest.CreateT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+	testCtx.FactoryMock.AssertAuditEventRecorded(t, core.AuditOperationCreate, core.AuditOutcomeSuccess)
+}
+
+func Test_Template_Create_RecordsAuditOfRejectedPlacement(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenCiphertextExpiringIn3Months(t, "InitialModelValue")
+	testCtx.GivenObjectCannotBePlacedAsRequested("NonPlaceableObject")
+
+	testCtx.ResourceUnderTest.CreateT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "NonPlaceableObject")
+	testCtx.FactoryMock.AssertAuditEventRecorded(t, core.AuditOperationCreate, core.AuditOutcomeFailure)
+}
+
+func Test_Template_Create_RecordsAuditOfUndecryptableCiphertext(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	header := testCtx.GivenUndecryptableCiphertext(t, "InitialModelValue")
+
+	testCtx.ResourceUnderTest.CreateT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Cannot decrypt ciphertext")
+	testCtx.FactoryMock.AssertCalled(t, "RecordAuditEvent",
+		mock.Anything,
+		mock.MatchedBy(func(e core.AuditEvent) bool {
+			return e.Operation == core.AuditOperationCreate &&
+				e.Outcome == core.AuditOutcomeFailure &&
+				e.CiphertextUuid == header.Uuid &&
+				e.ObjectType == header.Type &&
+				e.Message == "Cannot decrypt ciphertext"
+		}),
+		mock.Anything,
+		mock.Anything)
+}
+
+func Test_Template_Create_RecordsAuditOfMalformedCiphertext(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.SpecializerMock.On("GetConfidentialMaterialFrom", "InitialModelValue").Return(ConfidentialMaterialModel{
+		EncryptedSecret: types.StringValue("not a ciphertext"),
+	})
+
+	testCtx.ResourceUnderTest.CreateT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Confidential content does not conform to the expected format")
+	testCtx.FactoryMock.AssertCalled(t, "RecordAuditEvent",
+		mock.Anything,
+		mock.MatchedBy(func(e core.AuditEvent) bool {
+			return e.Operation == core.AuditOperationCreate &&
+				e.Outcome == core.AuditOutcomeFailure &&
+				e.Destination == "unit-test-destination"
+		}),
+		mock.Anything,
+		mock.Anything)
+}
+
+func Test_Template_ModifyPlan_IfCiphertextExpired(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenExpiredCiphertext(t, "InitialModelValue")
+
+	testCtx.ResourceUnderTest.ModifyPlanT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		true,
+		true,
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Ciphertext has expired")
+}
+
+func Test_Template_ModifyPlan_IfCiphertextNotYetActive(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenNotYetActiveCiphertext(t, "InitialModelValue")
+
+	testCtx.ResourceUnderTest.ModifyPlanT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		false,
+		true,
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Ciphertext is not yet active")
+}
+
+func Test_Template_ModifyPlan_IfCiphertextCannotBePlaced(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenCiphertextExpiringIn3Months(t, "InitialModelValue")
+	testCtx.GivenObjectCannotBePlacedAsRequested("NonPlaceableObject")
+
+	testCtx.ResourceUnderTest.ModifyPlanT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		true,
+		true,
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "NonPlaceableObject")
+}
+
+func Test_Template_ModifyPlan_PlacementNotCheckedIfConfigIsUnknown(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenCiphertextExpiringIn3Months(t, "InitialModelValue")
+
+	testCtx.ResourceUnderTest.ModifyPlanT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		true,
+		false,
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+}
+
+func Test_Template_ModifyPlan_UseLimitedCiphertextIfCreatesOverused(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenUseLimitedCiphertext(t, "InitialModelValue", 10)
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.FactoryMock.GivenObjectTrackingConfigured(true)
+	testCtx.FactoryMock.GivenGetTackedObjectUses(10)
+
+	testCtx.ResourceUnderTest.ModifyPlanT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		true,
+		true,
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Ciphertext has been used all time it was allowed to do so")
+}
+
+func Test_Template_ModifyPlan_UsageNotCheckedOnUpdate(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenUseLimitedCiphertext(t, "InitialModelValue", 10)
+	testCtx.GivenObjectCanBePlacedAsRequested()
+
+	testCtx.ResourceUnderTest.ModifyPlanT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		false,
+		true,
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+}
+
+func Test_Template_ModifyPlan_ValidCiphertext(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenUseLimitedCiphertext(t, "InitialModelValue", 10)
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.FactoryMock.GivenObjectTrackingConfigured(true)
+	testCtx.FactoryMock.GivenGetTackedObjectUses(3)
+
+	testCtx.ResourceUnderTest.ModifyPlanT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		true,
+		true,
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+}
+
+var unitTestFingerprintKey = []byte("unit-test-fingerprint-key")
+
+// givenFingerprintedCiphertext sets up the ciphertext of the model whose fingerprint is kept in the private state.
+// The fingerprint is computed for the ciphertext, unless another ciphertext is given.
+func (grtc *GenericResourceTestContext) givenFingerprintedCiphertext(t *testing.T, mdl string, placedValue string, fingerprintedCiphertext string) (core.ConfidentialDataJsonHeader, core.ConfidentialStringData) {
+	helper := core.NewVersionedStringConfidentialDataHelper(UnitTestObjectType)
+	_ = helper.CreateConfidentialStringData("this is a secret message", core.SecondaryProtectionParameters{
+		Expiry: time.Now().Unix() + int64(time.Hour*24*31*3/time.Second),
+	})
+
+	rsaKey, err := core.LoadPublicKeyFromData(testkeymaterial.EphemeralRsaPublicKey)
+	assert.Nil(t, err, "Failed to load public key")
+
+	em, err := helper.ToEncryptedMessage(rsaKey)
+	assert.Nil(t, err, "Failed to encrypted message")
+
+	ciphertext := em.ToBase64PEM()
+	if len(fingerprintedCiphertext) == 0 {
+		fingerprintedCiphertext = ciphertext
+	}
+
+	grtc.SpecializerMock.On("GetConfidentialMaterialFrom", mdl).Return(ConfidentialMaterialModel{
+		EncryptedSecret: types.StringValue(ciphertext),
+	})
+
+	fp := core.NewConfidentialValueFingerprint(unitTestFingerprintKey, fingerprintedCiphertext, helper.Header, []byte(placedValue))
+	fpJson, err := json.Marshal(fp)
+	assert.Nil(t, err)
+
+	grtc.RequestMock.GivenPrivateState(FingerprintPrivateStateKey, fpJson)
+	grtc.FactoryMock.GivenFingerprintKey(unitTestFingerprintKey)
+
+	mru := &ValueReadingMutableRUMock[string, core.ConfidentialStringData, string]{}
+	grtc.MutableRU = &mru.MutableRUMock
+	grtc.ResourceUnderTest.MutableRU = mru
+
+	return helper.Header, helper.KnowValue
+}
+
+func Test_Template_ReadMURU_ComparesFingerprintWithoutDecryption(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.givenFingerprintedCiphertext(t, "InitialModelValue", "this is a secret message", "")
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.MutableRU.On("DoReadConfidentialValue", mock.Anything, mock.MatchedBy(StringPtrMatcher("InitialModelValue"))).
+		Once().
+		Return("OkayModel", []byte("this is a secret message"), ResourceExists)
+
+	testCtx.SpecializerMock.ThenAzValueIsConvertedToTerraform("OkayModel", "InitialModelValue", StringComparator)
+	testCtx.ResponseMock.ThenTerraformModelIsSet("InitialModelValue")
+
+	testCtx.ResourceUnderTest.ReadT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+	testCtx.SpecializerMock.AssertNotCalled(t, "Decrypt", mock.Anything, mock.Anything, mock.Anything)
+	testCtx.FactoryMock.AssertNotCalled(t, "GetDecrypterFor", mock.Anything, mock.Anything)
+}
+
+func Test_Template_ReadMURU_DetectsDriftFromFingerprint(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.givenFingerprintedCiphertext(t, "InitialModelValue", "this is a secret message", "")
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.MutableRU.On("DoReadConfidentialValue", mock.Anything, mock.MatchedBy(StringPtrMatcher("InitialModelValue"))).
+		Once().
+		Return("OkayModel", []byte("value changed in Azure"), ResourceExists)
+
+	testCtx.SpecializerMock.ThenAzValueIsConvertedToTerraform("OkayModel", "InitialModelValue", StringComparator)
+	testCtx.MutableRU.ThenDriftWillBeSet("InitialModelValue", StringComparator)
+	testCtx.ResponseMock.ThenTerraformModelIsSet("InitialModelValue")
+
+	testCtx.ResourceUnderTest.ReadT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+	testCtx.SpecializerMock.AssertNotCalled(t, "Decrypt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Template_ReadMURU_DecryptsChangedCiphertext(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	header, confData := testCtx.givenFingerprintedCiphertext(t, "InitialModelValue", "this is a secret message", "previous ciphertext")
+	testCtx.GivenObjectCanBePlacedAsRequested()
+
+	testCtx.FactoryMock.On("GetDecrypterFor", mock.Anything, mock.Anything).Return(core.RSADecrypter(nil)).Once()
+	testCtx.SpecializerMock.GivenDecrypt(header, confData)
+	testCtx.MutableRU.On("DoRead", mock.Anything, mock.MatchedBy(StringPtrMatcher("InitialModelValue")), mock.Anything).
+		Once().
+		Return("OkayModel", ResourceExists)
+	testCtx.MutableRU.On("ConfidentialValueOf", mock.Anything).Return([]byte("this is a secret message"))
+
+	testCtx.SpecializerMock.ThenAzValueIsConvertedToTerraform("OkayModel", "InitialModelValue", StringComparator)
+	testCtx.ResponseMock.ThenTerraformModelIsSet("InitialModelValue")
+	testCtx.ResponseMock.ThenPrivateStateIsSet(FingerprintPrivateStateKey)
+
+	testCtx.ResourceUnderTest.ReadT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+}
+
+func Test_Template_Create_SetsIdentity(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.GivenResourceDeclaresIdentity()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenUseLimitedCiphertext(t, "InitialModelValue", 0)
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.SpecializerMock.GivenCreate("InitialModelValue")
+
+	testCtx.SpecializerMock.ThenAzValueIsConvertedToTerraform("CreatedAzureObject", "InitialModelValue", StringComparator)
+	testCtx.ResponseMock.ThenTerraformModelIsSet("InitialModelValue")
+	testCtx.ResponseMock.ThenIdentityIsSet(func(identity *UnitTestIdentityModel) bool {
+		return identity.Destination == "InitialModelValue" && len(identity.CiphertextUuid) > 0
+	})
+
+	testCtx.ResourceUnderTest.CreateT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+}
+
+func Test_Template_Create_IdentityNotSetIfNotDeclared(t *testing.T) {
+	testCtx := givenSetup()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenUseLimitedCiphertext(t, "InitialModelValue", 0)
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.SpecializerMock.GivenCreate("InitialModelValue")
+
+	testCtx.SpecializerMock.ThenAzValueIsConvertedToTerraform("CreatedAzureObject", "InitialModelValue", StringComparator)
+	testCtx.ResponseMock.ThenTerraformModelIsSet("InitialModelValue")
+
+	testCtx.ResourceUnderTest.CreateT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.ResponseMock.AssertNotCalled(t, "SetIdentity", mock.Anything, mock.Anything)
+}
+
+func Test_Template_ReadIMRU_KeepsCiphertextUuidOfPriorIdentity(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.GivenResourceDeclaresIdentity()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.RequestMock.GivenPriorIdentityCiphertextUuid("creating-ciphertext-uuid")
+	testCtx.GivenImmutableRUReturns("InitialModelValue", ResourceExists)
+	testCtx.SpecializerMock.ThenAzValueIsConvertedToTerraform("OkayModel", "InitialModelValue", StringComparator)
+	testCtx.ResponseMock.ThenTerraformModelIsSet("InitialModelValue")
+	testCtx.ResponseMock.ThenIdentityIsSet(func(identity *UnitTestIdentityModel) bool {
+		return identity.CiphertextUuid == "creating-ciphertext-uuid"
+	})
+
+	testCtx.ResourceUnderTest.ReadT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+}
+
+func Test_Template_ReadMURU_SetsIdentityWithoutPriorIdentity(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.GivenResourceDeclaresIdentity()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.RequestMock.GivenNoPriorIdentity()
+	testCtx.GivenCiphertextExpiringIn3Months(t, "InitialModelValue")
+	testCtx.GivenObjectCanBePlacedAsRequested()
+	testCtx.GivenMutableRUReturns("InitialModelValue", ResourceExists)
+
+	testCtx.SpecializerMock.ThenAzValueIsConvertedToTerraform("OkayModel", "InitialModelValue", StringComparator)
+	testCtx.ResponseMock.ThenTerraformModelIsSet("InitialModelValue")
+	testCtx.ResponseMock.ThenIdentityIsSet(func(identity *UnitTestIdentityModel) bool {
+		return identity.Destination == "InitialModelValue" && len(identity.CiphertextUuid) > 0
+	})
+
+	testCtx.ResourceUnderTest.ReadT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+}
+
+func Test_Template_ReadIMRU_IdentityNotSetIfResourceNotFound(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.GivenResourceDeclaresIdentity()
+
+	testCtx.RequestMock.GivenGet()
+	testCtx.GivenImmutableRUReturns("InitialModelValue", ResourceNotFound)
+	testCtx.ResponseMock.ThenResourceIsRemoved()
+
+	testCtx.ResourceUnderTest.ReadT(
+		context.Background(),
+		testCtx.RequestMock.AsRequestAbstraction(),
+		testCtx.ResponseMock.AsResponseAbstraction())
+
+	testCtx.AssertExpectations(t)
+	testCtx.ResponseMock.AssertNotCalled(t, "SetIdentity", mock.Anything, mock.Anything)
+}
+
+func (grtc *GenericResourceTestContext) givenMovedObjectValue(value []byte, check ResourceExistenceCheck) {
+	mru := &ValueReadingMutableRUMock[string, core.ConfidentialStringData, string]{}
+	mru.On("DoReadConfidentialValue", mock.Anything, mock.MatchedBy(StringPtrMatcher("InitialModelValue"))).
+		Once().
+		Return("OkayModel", value, check)
+	mru.On("ConfidentialValueOf", mock.Anything).Return([]byte("this is a secret message")).Maybe()
+
+	grtc.MutableRU = &mru.MutableRUMock
+	grtc.ResourceUnderTest.MutableRU = mru
+}
+
+func Test_Template_VerifyMovedObjectValue_MatchesCiphertext(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.givenMovedObjectValue([]byte("this is a secret message"), ResourceExists)
+
+	mdl := "InitialModelValue"
+	testCtx.ResourceUnderTest.verifyMovedObjectValue(context.Background(), &mdl, nil, testCtx.ResponseMock.Diagnostic)
+
+	testCtx.MutableRU.AssertExpectations(t)
+	testCtx.AssertResponseHasNoError(t)
+}
+
+func Test_Template_VerifyMovedObjectValue_DiffersFromCiphertext(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.givenMovedObjectValue([]byte("another value"), ResourceExists)
+
+	mdl := "InitialModelValue"
+	testCtx.ResourceUnderTest.verifyMovedObjectValue(context.Background(), &mdl, nil, testCtx.ResponseMock.Diagnostic)
+
+	testCtx.MutableRU.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Moved object does not match the ciphertext")
+}
+
+func Test_Template_VerifyMovedObjectValue_ObjectNotFound(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.givenMovedObjectValue(nil, ResourceNotFound)
+
+	mdl := "InitialModelValue"
+	testCtx.ResourceUnderTest.verifyMovedObjectValue(context.Background(), &mdl, nil, testCtx.ResponseMock.Diagnostic)
+
+	testCtx.MutableRU.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Moved object does not exist")
+}
+
+func Test_Template_VerifyMovedObjectValue_ValueCannotBeRead(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.ResourceUnderTest.ImmutableRU = &ImmutableRUMock[string, string]{}
+
+	mdl := "InitialModelValue"
+	testCtx.ResourceUnderTest.verifyMovedObjectValue(context.Background(), &mdl, nil, testCtx.ResponseMock.Diagnostic)
+
+	testCtx.AssertResponseHasError(t, "Moved object cannot be adopted")
+}
+
+func Test_Template_VerifyMovedObjectValue_ObjectAtOtherDestination(t *testing.T) {
+	testCtx := givenSetup()
+	testCtx.ResourceUnderTest.MutableRU = &ValueReadingMutableRUMock[string, core.ConfidentialStringData, string]{}
+
+	verifier := &DestinationVerifyingSpecializerMock{&SpecializerMock[string, core.ConfidentialStringData, string]{}}
+	verifier.On("VerifyMovedObjectDestination", mock.MatchedBy(StringPtrMatcher("InitialModelValue"))).
+		Return(errors.New("object is in another vault"))
+	testCtx.ResourceUnderTest.Specializer = verifier
+
+	mdl := "InitialModelValue"
+	testCtx.ResourceUnderTest.verifyMovedObjectValue(context.Background(), &mdl, nil, testCtx.ResponseMock.Diagnostic)
+
+	verifier.AssertExpectations(t)
+	testCtx.AssertResponseHasError(t, "Moved object is not at the configured destination")
+}
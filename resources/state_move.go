@@ -0,0 +1,165 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// AzureRMProviderAddress the address of the azurerm provider, whose resources can be moved into the resources
+// of this provider
+const AzureRMProviderAddress = "registry.terraform.io/hashicorp/azurerm"
+
+// MovedObjectPrivateStateKey the key of the private state marking the Azure object moved into the resource from
+// another resource. The marker is removed once the object is adopted with the ciphertext of the configuration.
+const MovedObjectPrivateStateKey = "moved_from"
+
+// MovedObjectSource the source of the Azure object moved into the resource, as kept in the private state
+type MovedObjectSource struct {
+	ProviderAddress string `json:"provider"`
+	TypeName        string `json:"type"`
+}
+
+// StateMove moves the state of the source resource, read from its JSON, into the model of the resource.
+type StateMove[TSrcMdl, TMdl any] func(ctx context.Context, src TSrcMdl, mdl *TMdl) diag.Diagnostics
+
+// NewStateMover creates the mover of the state of the given resource type of the source provider. The moved state
+// doesn't carry the confidential value of the source resource: the content of the moved state is the drift marker,
+// which is replaced with the ciphertext of the configuration on the next apply. The update adopts the Azure object
+// only where its confidential value is the plaintext of the ciphertext.
+func NewStateMover[TSrcMdl, TMdl any](sourceProviderAddress, sourceTypeName string, move StateMove[TSrcMdl, TMdl]) resource.StateMover {
+	return resource.StateMover{
+		StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+			// Other movers may handle the request
+			if !strings.EqualFold(req.SourceProviderAddress, sourceProviderAddress) || req.SourceTypeName != sourceTypeName {
+				return
+			}
+
+			if req.SourceRawState == nil || len(req.SourceRawState.JSON) == 0 {
+				resp.Diagnostics.AddError(
+					"Source state cannot be moved",
+					fmt.Sprintf("The state of %s was not given as JSON; refresh the state with the source provider before moving it", sourceTypeName),
+				)
+				return
+			}
+
+			var src TSrcMdl
+			if err := json.Unmarshal(req.SourceRawState.JSON, &src); err != nil {
+				resp.Diagnostics.AddError(
+					"Source state cannot be moved",
+					fmt.Sprintf("The state of %s cannot be read: %s", sourceTypeName, err.Error()),
+				)
+				return
+			}
+
+			var mdl TMdl
+			resp.Diagnostics.Append(nullModelOf(ctx, resp.TargetState, &mdl)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.Diagnostics.Append(move(ctx, src, &mdl)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.Diagnostics.Append(resp.TargetState.Set(ctx, &mdl)...)
+			resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root("content"), CreateDriftMessage("moved object"))...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			marker, _ := json.Marshal(MovedObjectSource{ProviderAddress: req.SourceProviderAddress, TypeName: sourceTypeName})
+			resp.Diagnostics.Append(resp.TargetPrivate.SetKey(ctx, MovedObjectPrivateStateKey, marker)...)
+		},
+	}
+}
+
+// nullModelOf reads the model of the state whose attributes are all null. The model so read carries the null values
+// of the types the schema declares, which the zero values of the model don't.
+func nullModelOf(ctx context.Context, state tfsdk.State, mdl any) diag.Diagnostics {
+	rv := diag.Diagnostics{}
+
+	objType, ok := state.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		rv.AddError("Unexpected resource schema", "The schema of the resource does not describe an object")
+		return rv
+	}
+
+	nullAttrs := map[string]tftypes.Value{}
+	for name, attrType := range objType.AttributeTypes {
+		nullAttrs[name] = tftypes.NewValue(attrType, nil)
+	}
+
+	nullState := tfsdk.State{Schema: state.Schema, Raw: tftypes.NewValue(objType, nullAttrs)}
+
+	var obj types.Object
+	rv.Append(nullState.Get(ctx, &obj)...)
+	if rv.HasError() {
+		return rv
+	}
+
+	rv.Append(obj.As(ctx, mdl, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true})...)
+	return rv
+}
+
+// MovedObjectDestinationVerifier is implemented by the specializers of the resources whose configuration may
+// place the resource elsewhere than the Azure object moved into it is. The moved object is adopted only where it
+// is at the destination the configuration (or the provider defaults) gives.
+type MovedObjectDestinationVerifier[TMdl any] interface {
+	// VerifyMovedObjectDestination returns an error where the Azure object the identifier of the model points to
+	// is not at the destination of the configuration.
+	VerifyMovedObjectDestination(tfModel *TMdl) error
+}
+
+// PrivateStateReader reads the private state of the resource
+type PrivateStateReader interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// IsMovedObject whether the private state marks the Azure object moved into the resource that is yet to be adopted
+func IsMovedObject(ctx context.Context, private PrivateStateReader) bool {
+	if private == nil {
+		return false
+	}
+
+	marker, dg := private.GetKey(ctx, MovedObjectPrivateStateKey)
+	return !dg.HasError() && len(marker) > 0
+}
+
+const requiresReplaceUnlessMovedDescription = "Changing this value requires the replacement of the resource, unless the object was moved from another resource and is yet to be adopted"
+
+// StringRequiresReplaceUnlessMoved requires replacing the resource where the attribute changes, except where the
+// state was moved from another resource. The moved state is adopted by the in-place update.
+func StringRequiresReplaceUnlessMoved() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = !IsMovedObject(ctx, req.Private)
+		},
+		requiresReplaceUnlessMovedDescription,
+		requiresReplaceUnlessMovedDescription,
+	)
+}
+
+// ObjectRequiresReplaceUnlessMoved the object attribute counterpart of StringRequiresReplaceUnlessMoved
+func ObjectRequiresReplaceUnlessMoved() planmodifier.Object {
+	return objectplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = !IsMovedObject(ctx, req.Private)
+		},
+		requiresReplaceUnlessMovedDescription,
+		requiresReplaceUnlessMovedDescription,
+	)
+}
@@ -0,0 +1,25 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+)
+
+// privateStateMock the private state given as the map of its keys
+type privateStateMock map[string][]byte
+
+func (p privateStateMock) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func Test_IsMovedObject(t *testing.T) {
+	ctx := context.Background()
+
+	assert.False(t, IsMovedObject(ctx, nil))
+	assert.False(t, IsMovedObject(ctx, privateStateMock{}))
+	assert.False(t, IsMovedObject(ctx, privateStateMock{FingerprintPrivateStateKey: []byte(`{}`)}))
+	assert.True(t, IsMovedObject(ctx, privateStateMock{MovedObjectPrivateStateKey: []byte(`{"type":"azurerm_key_vault_secret"}`)}))
+}
@@ -0,0 +1,123 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// StateMigration migrates the state written with the prior version of the resource schema into the model of the
+// current version of the schema.
+type StateMigration[TPriorMdl, TMdl any] func(ctx context.Context, prior TPriorMdl, mdl *TMdl) diag.Diagnostics
+
+// NewStateUpgrader creates the upgrader of the state written with the prior schema. The prior schema must be kept
+// as it was at the prior version: the state is read with it into the prior model before it is migrated.
+func NewStateUpgrader[TPriorMdl, TMdl any](priorSchema schema.Schema, migrate StateMigration[TPriorMdl, TMdl]) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		PriorSchema: &priorSchema,
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			var prior TPriorMdl
+			resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			var mdl TMdl
+			resp.Diagnostics.Append(migrate(ctx, prior, &mdl)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &mdl)...)
+		},
+	}
+}
+
+// NewCarryOverStateUpgrader creates the upgrader of the state written with the prior schema whose attributes were
+// carried over unchanged into the current schema. The attributes added since the prior version are null in the
+// upgraded state. The prior schema must be kept as it was at the prior version; an attribute that was removed or
+// whose type has changed since must be migrated with NewStateUpgrader instead.
+func NewCarryOverStateUpgrader(priorSchema schema.Schema) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		PriorSchema: &priorSchema,
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			currentType, ok := resp.State.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			if !ok {
+				resp.Diagnostics.AddError("Cannot upgrade state", "The schema of the resource is not an object. This is a provider bug. Please report this")
+				return
+			}
+
+			var priorAttrs map[string]tftypes.Value
+			if err := req.State.Raw.As(&priorAttrs); err != nil {
+				resp.Diagnostics.AddError("Cannot upgrade state", fmt.Sprintf("The prior state cannot be read: %s", err.Error()))
+				return
+			}
+
+			attrs := map[string]tftypes.Value{}
+			for name, attrType := range currentType.AttributeTypes {
+				if v, exists := priorAttrs[name]; !exists {
+					attrs[name] = tftypes.NewValue(attrType, nil)
+				} else if !v.Type().Equal(attrType) {
+					resp.Diagnostics.AddError("Cannot upgrade state", fmt.Sprintf("The type of attribute %s has changed since the prior version. This is a provider bug. Please report this", name))
+					return
+				} else {
+					attrs[name] = v
+				}
+			}
+
+			for name := range priorAttrs {
+				if _, exists := currentType.AttributeTypes[name]; !exists {
+					resp.Diagnostics.AddError("Cannot upgrade state", fmt.Sprintf("The attribute %s was removed since the prior version. This is a provider bug. Please report this", name))
+					return
+				}
+			}
+
+			resp.State.Raw = tftypes.NewValue(currentType, attrs)
+		},
+	}
+}
+
+// WrappedConfidentialMaterialModelSchemaV0 the attributes of the confidential material as these were before the
+// resource schemas declared their version, i.e. before the timeouts were added. Only the types and the presence
+// of the attributes matter to reading the prior state; the descriptions and the validators are omitted.
+func WrappedConfidentialMaterialModelSchemaV0(moreAttrs map[string]schema.Attribute) map[string]schema.Attribute {
+	rv := map[string]schema.Attribute{
+		"id": schema.StringAttribute{Computed: true},
+		"wrapping_key": schema.SingleNestedAttribute{
+			Optional: true,
+			Attributes: map[string]schema.Attribute{
+				"vault_name": schema.StringAttribute{Optional: true},
+				"name":       schema.StringAttribute{Optional: true},
+				"version":    schema.StringAttribute{Optional: true},
+				"algorithm":  schema.StringAttribute{Optional: true, Computed: true},
+			},
+		},
+		"content": schema.StringAttribute{Required: true},
+	}
+
+	for k, v := range moreAttrs {
+		rv[k] = v
+	}
+	return rv
+}
+
+// WrappedAzKeyVaultObjectConfidentialMaterialModelSchemaV0 the attributes of the Key Vault object as these were
+// before the resource schemas declared their version, i.e. before the timeouts and the provenance tags were added.
+func WrappedAzKeyVaultObjectConfidentialMaterialModelSchemaV0(moreAttrs map[string]schema.Attribute) map[string]schema.Attribute {
+	rv := WrappedConfidentialMaterialModelSchemaV0(map[string]schema.Attribute{
+		"enabled":         schema.BoolAttribute{Optional: true, Computed: true},
+		"tags":            schema.MapAttribute{Optional: true, Computed: true, ElementType: types.StringType},
+		"not_before_date": schema.StringAttribute{Optional: true, Computed: true},
+		"not_after_date":  schema.StringAttribute{Optional: true, Computed: true},
+	})
+
+	for k, v := range moreAttrs {
+		rv[k] = v
+	}
+	return rv
+}
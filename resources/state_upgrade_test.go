@@ -0,0 +1,129 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+type priorUnitTestModel struct {
+	Id   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
+
+type currentUnitTestModel struct {
+	Id          types.String `tfsdk:"id"`
+	DisplayName types.String `tfsdk:"display_name"`
+}
+
+var priorUnitTestSchema = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"id":   schema.StringAttribute{Computed: true},
+		"name": schema.StringAttribute{Optional: true},
+	},
+}
+
+var currentUnitTestSchema = schema.Schema{
+	Version: 1,
+	Attributes: map[string]schema.Attribute{
+		"id":           schema.StringAttribute{Computed: true},
+		"display_name": schema.StringAttribute{Optional: true},
+	},
+}
+
+// upgradeStateFromJson upgrades the state as Terraform would have stored it with the prior schema of the upgrader.
+func upgradeStateFromJson(t *testing.T, upgrader resource.StateUpgrader, currentSchema schema.Schema, stateJson string) (tfsdk.State, diag.Diagnostics) {
+	ctx := context.Background()
+
+	priorValue, err := tftypes.ValueFromJSONWithOpts(
+		[]byte(stateJson),
+		upgrader.PriorSchema.Type().TerraformType(ctx),
+		tftypes.ValueFromJSONOpts{IgnoreUndefinedAttributes: true},
+	)
+	assert.Nil(t, err)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: priorValue},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: currentSchema, Raw: tftypes.NewValue(currentSchema.Type().TerraformType(ctx), nil)},
+	}
+
+	upgrader.StateUpgrader(ctx, req, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+func Test_StateUpgrader_MigratesPriorModel(t *testing.T) {
+	upgrader := NewStateUpgrader(priorUnitTestSchema, func(_ context.Context, prior priorUnitTestModel, mdl *currentUnitTestModel) diag.Diagnostics {
+		mdl.Id = prior.Id
+		mdl.DisplayName = prior.Name
+		return nil
+	})
+
+	state, dg := upgradeStateFromJson(t, upgrader, currentUnitTestSchema, `{"id":"unit-test-id","name":"unit-test-name"}`)
+	assert.False(t, dg.HasError())
+
+	mdl := currentUnitTestModel{}
+	assert.False(t, state.Get(context.Background(), &mdl).HasError())
+	assert.Equal(t, "unit-test-id", mdl.Id.ValueString())
+	assert.Equal(t, "unit-test-name", mdl.DisplayName.ValueString())
+}
+
+func Test_StateUpgrader_ReportsMigrationError(t *testing.T) {
+	upgrader := NewStateUpgrader(priorUnitTestSchema, func(_ context.Context, _ priorUnitTestModel, _ *currentUnitTestModel) diag.Diagnostics {
+		rv := diag.Diagnostics{}
+		rv.AddError("unit-test-migration-error", "unit-test")
+		return rv
+	})
+
+	state, dg := upgradeStateFromJson(t, upgrader, currentUnitTestSchema, `{"id":"unit-test-id"}`)
+	assert.True(t, dg.HasError())
+	assert.Equal(t, "unit-test-migration-error", dg[0].Summary())
+	assert.True(t, state.Raw.IsNull())
+}
+
+var carriedOverUnitTestSchema = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"id": schema.StringAttribute{Computed: true},
+	},
+}
+
+func Test_CarryOverStateUpgrader_AddsNullAttributes(t *testing.T) {
+	upgrader := NewCarryOverStateUpgrader(carriedOverUnitTestSchema)
+
+	state, dg := upgradeStateFromJson(t, upgrader, currentUnitTestSchema, `{"id":"unit-test-id"}`)
+	assert.False(t, dg.HasError())
+
+	mdl := currentUnitTestModel{}
+	assert.False(t, state.Get(context.Background(), &mdl).HasError())
+	assert.Equal(t, "unit-test-id", mdl.Id.ValueString())
+	assert.True(t, mdl.DisplayName.IsNull())
+}
+
+func Test_CarryOverStateUpgrader_RejectsRemovedAttribute(t *testing.T) {
+	upgrader := NewCarryOverStateUpgrader(priorUnitTestSchema)
+
+	_, dg := upgradeStateFromJson(t, upgrader, currentUnitTestSchema, `{"id":"unit-test-id","name":"unit-test-name"}`)
+	assert.True(t, dg.HasError())
+	assert.Contains(t, dg[0].Detail(), "attribute name was removed")
+}
+
+func Test_CarryOverStateUpgrader_RejectsChangedType(t *testing.T) {
+	upgrader := NewCarryOverStateUpgrader(schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":           schema.StringAttribute{Computed: true},
+			"display_name": schema.BoolAttribute{Optional: true},
+		},
+	})
+
+	_, dg := upgradeStateFromJson(t, upgrader, currentUnitTestSchema, `{"id":"unit-test-id","display_name":true}`)
+	assert.True(t, dg.HasError())
+	assert.Contains(t, dg[0].Detail(), "type of attribute display_name has changed")
+}
@@ -0,0 +1,332 @@
+package testsupport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+)
+
+// ApimResourceId the Azure resource id of the object of the API management service
+func ApimResourceId(subscriptionId, resourceGroup, serviceName, objectType, name string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ApiManagement/service/%s/%s/%s",
+		subscriptionId, resourceGroup, serviceName, objectType, name)
+}
+
+func apimUrl(resourceId string) string {
+	return "https://management.azure.com" + resourceId
+}
+
+// CompletedPoller the poller of the long-running operation that has already completed
+type CompletedPoller[T any] struct {
+	Result T
+}
+
+func (p *CompletedPoller[T]) PollUntilDone(_ context.Context, _ *runtime.PollUntilDoneOptions) (T, error) {
+	return p.Result, nil
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Named values
+
+// InMemoryNamedValueClient the named values of the API management services of a single Azure subscription
+// kept in memory
+type InMemoryNamedValueClient struct {
+	SubscriptionId string
+
+	mutex       sync.Mutex
+	namedValues map[string]*armapimanagement.NamedValueContractProperties
+}
+
+func NewInMemoryNamedValueClient(subscriptionId string) *InMemoryNamedValueClient {
+	return &InMemoryNamedValueClient{
+		SubscriptionId: subscriptionId,
+		namedValues:    map[string]*armapimanagement.NamedValueContractProperties{},
+	}
+}
+
+func (c *InMemoryNamedValueClient) resourceId(resourceGroupName, serviceName, namedValueID string) string {
+	return ApimResourceId(c.SubscriptionId, resourceGroupName, serviceName, "namedValues", namedValueID)
+}
+
+func (c *InMemoryNamedValueClient) notFound(method, resourceId, namedValueID string) error {
+	return notFoundError(method, apimUrl(resourceId), "ResourceNotFound", fmt.Sprintf("Named value %s not found", namedValueID))
+}
+
+// contractOf the named value as API management returns it: the value of a secret named value is returned only
+// by the ListValue operation.
+func contractOf(resourceId, namedValueID string, props *armapimanagement.NamedValueContractProperties) armapimanagement.NamedValueContract {
+	rv := *props
+	if rv.Secret != nil && *rv.Secret {
+		rv.Value = nil
+	}
+
+	return armapimanagement.NamedValueContract{
+		ID:         to.Ptr(resourceId),
+		Name:       to.Ptr(namedValueID),
+		Type:       to.Ptr("Microsoft.ApiManagement/service/namedValues"),
+		Properties: &rv,
+	}
+}
+
+func (c *InMemoryNamedValueClient) Get(_ context.Context, resourceGroupName string, serviceName string, namedValueID string, _ *armapimanagement.NamedValueClientGetOptions) (armapimanagement.NamedValueClientGetResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resourceId := c.resourceId(resourceGroupName, serviceName, namedValueID)
+	props := c.namedValues[resourceId]
+	if props == nil {
+		return armapimanagement.NamedValueClientGetResponse{}, c.notFound(http.MethodGet, resourceId, namedValueID)
+	}
+
+	return armapimanagement.NamedValueClientGetResponse{
+		NamedValueContract: contractOf(resourceId, namedValueID, props),
+		ETag:               to.Ptr(newVersion()),
+	}, nil
+}
+
+func (c *InMemoryNamedValueClient) ListValue(_ context.Context, resourceGroupName string, serviceName string, namedValueID string, _ *armapimanagement.NamedValueClientListValueOptions) (armapimanagement.NamedValueClientListValueResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resourceId := c.resourceId(resourceGroupName, serviceName, namedValueID)
+	props := c.namedValues[resourceId]
+	if props == nil {
+		return armapimanagement.NamedValueClientListValueResponse{}, c.notFound(http.MethodPost, resourceId, namedValueID)
+	}
+
+	return armapimanagement.NamedValueClientListValueResponse{
+		NamedValueSecretContract: armapimanagement.NamedValueSecretContract{Value: props.Value},
+	}, nil
+}
+
+func (c *InMemoryNamedValueClient) Delete(_ context.Context, resourceGroupName string, serviceName string, namedValueID string, _ string, _ *armapimanagement.NamedValueClientDeleteOptions) (armapimanagement.NamedValueClientDeleteResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.namedValues, c.resourceId(resourceGroupName, serviceName, namedValueID))
+	return armapimanagement.NamedValueClientDeleteResponse{}, nil
+}
+
+func (c *InMemoryNamedValueClient) BeginCreateOrUpdate(_ context.Context, resourceGroupName string, serviceName string, namedValueID string, parameters armapimanagement.NamedValueCreateContract, _ *armapimanagement.NamedValueClientBeginCreateOrUpdateOptions) (core.PollerAbstraction[armapimanagement.NamedValueClientCreateOrUpdateResponse], error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resourceId := c.resourceId(resourceGroupName, serviceName, namedValueID)
+	if parameters.Properties == nil {
+		return nil, badRequestError(http.MethodPut, apimUrl(resourceId), "properties are required")
+	}
+
+	props := &armapimanagement.NamedValueContractProperties{
+		DisplayName: parameters.Properties.DisplayName,
+		Secret:      parameters.Properties.Secret,
+		Tags:        parameters.Properties.Tags,
+		Value:       parameters.Properties.Value,
+	}
+	c.namedValues[resourceId] = props
+
+	return &CompletedPoller[armapimanagement.NamedValueClientCreateOrUpdateResponse]{
+		Result: armapimanagement.NamedValueClientCreateOrUpdateResponse{
+			NamedValueContract: contractOf(resourceId, namedValueID, props),
+		},
+	}, nil
+}
+
+func (c *InMemoryNamedValueClient) BeginUpdate(_ context.Context, resourceGroupName string, serviceName string, namedValueID string, _ string, parameters armapimanagement.NamedValueUpdateParameters, _ *armapimanagement.NamedValueClientBeginUpdateOptions) (core.PollerAbstraction[armapimanagement.NamedValueClientUpdateResponse], error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resourceId := c.resourceId(resourceGroupName, serviceName, namedValueID)
+	current := c.namedValues[resourceId]
+	if current == nil {
+		return nil, c.notFound(http.MethodPatch, resourceId, namedValueID)
+	}
+
+	props := *current
+	if p := parameters.Properties; p != nil {
+		props.DisplayName = valueOr(p.DisplayName, props.DisplayName)
+		props.Secret = valueOr(p.Secret, props.Secret)
+		props.Value = valueOr(p.Value, props.Value)
+		if p.Tags != nil {
+			props.Tags = p.Tags
+		}
+	}
+	c.namedValues[resourceId] = &props
+
+	return &CompletedPoller[armapimanagement.NamedValueClientUpdateResponse]{
+		Result: armapimanagement.NamedValueClientUpdateResponse{
+			NamedValueContract: contractOf(resourceId, namedValueID, &props),
+		},
+	}, nil
+}
+
+// NamedValue the value of the named value; false where the service doesn't have the named value.
+func (c *InMemoryNamedValueClient) NamedValue(resourceGroupName, serviceName, namedValueID string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if props := c.namedValues[c.resourceId(resourceGroupName, serviceName, namedValueID)]; props != nil && props.Value != nil {
+		return *props.Value, true
+	}
+	return "", false
+}
+
+var _ core.ApimNamedValueClientAbstraction = &InMemoryNamedValueClient{}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Subscriptions
+
+// InMemorySubscriptionClient the subscriptions of the API management services of a single Azure subscription
+// kept in memory
+type InMemorySubscriptionClient struct {
+	SubscriptionId string
+
+	mutex         sync.Mutex
+	subscriptions map[string]*armapimanagement.SubscriptionContractProperties
+}
+
+func NewInMemorySubscriptionClient(subscriptionId string) *InMemorySubscriptionClient {
+	return &InMemorySubscriptionClient{
+		SubscriptionId: subscriptionId,
+		subscriptions:  map[string]*armapimanagement.SubscriptionContractProperties{},
+	}
+}
+
+func (c *InMemorySubscriptionClient) resourceId(resourceGroupName, serviceName, sid string) string {
+	return ApimResourceId(c.SubscriptionId, resourceGroupName, serviceName, "subscriptions", sid)
+}
+
+func (c *InMemorySubscriptionClient) notFound(method, resourceId, sid string) error {
+	return notFoundError(method, apimUrl(resourceId), "ResourceNotFound", fmt.Sprintf("Subscription %s not found", sid))
+}
+
+// subscriptionContractOf the subscription as API management returns it: the keys are returned only by the
+// ListSecrets operation.
+func subscriptionContractOf(resourceId, sid string, props *armapimanagement.SubscriptionContractProperties) armapimanagement.SubscriptionContract {
+	rv := *props
+	rv.PrimaryKey = nil
+	rv.SecondaryKey = nil
+
+	return armapimanagement.SubscriptionContract{
+		ID:         to.Ptr(resourceId),
+		Name:       to.Ptr(sid),
+		Type:       to.Ptr("Microsoft.ApiManagement/service/subscriptions"),
+		Properties: &rv,
+	}
+}
+
+func (c *InMemorySubscriptionClient) Get(_ context.Context, resourceGroupName string, serviceName string, sid string, _ *armapimanagement.SubscriptionClientGetOptions) (armapimanagement.SubscriptionClientGetResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resourceId := c.resourceId(resourceGroupName, serviceName, sid)
+	props := c.subscriptions[resourceId]
+	if props == nil {
+		return armapimanagement.SubscriptionClientGetResponse{}, c.notFound(http.MethodGet, resourceId, sid)
+	}
+
+	return armapimanagement.SubscriptionClientGetResponse{
+		SubscriptionContract: subscriptionContractOf(resourceId, sid, props),
+		ETag:                 to.Ptr(newVersion()),
+	}, nil
+}
+
+func (c *InMemorySubscriptionClient) ListSecrets(_ context.Context, resourceGroupName string, serviceName string, sid string, _ *armapimanagement.SubscriptionClientListSecretsOptions) (armapimanagement.SubscriptionClientListSecretsResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resourceId := c.resourceId(resourceGroupName, serviceName, sid)
+	props := c.subscriptions[resourceId]
+	if props == nil {
+		return armapimanagement.SubscriptionClientListSecretsResponse{}, c.notFound(http.MethodPost, resourceId, sid)
+	}
+
+	return armapimanagement.SubscriptionClientListSecretsResponse{
+		SubscriptionKeysContract: armapimanagement.SubscriptionKeysContract{
+			PrimaryKey:   props.PrimaryKey,
+			SecondaryKey: props.SecondaryKey,
+		},
+	}, nil
+}
+
+func (c *InMemorySubscriptionClient) CreateOrUpdate(_ context.Context, resourceGroupName string, serviceName string, sid string, parameters armapimanagement.SubscriptionCreateParameters, _ *armapimanagement.SubscriptionClientCreateOrUpdateOptions) (armapimanagement.SubscriptionClientCreateOrUpdateResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resourceId := c.resourceId(resourceGroupName, serviceName, sid)
+	p := parameters.Properties
+	if p == nil {
+		return armapimanagement.SubscriptionClientCreateOrUpdateResponse{}, badRequestError(http.MethodPut, apimUrl(resourceId), "properties are required")
+	}
+
+	props := &armapimanagement.SubscriptionContractProperties{
+		DisplayName:  p.DisplayName,
+		Scope:        p.Scope,
+		AllowTracing: p.AllowTracing,
+		OwnerID:      p.OwnerID,
+		PrimaryKey:   valueOr(p.PrimaryKey, to.Ptr(newVersion())),
+		SecondaryKey: valueOr(p.SecondaryKey, to.Ptr(newVersion())),
+		State:        valueOr(p.State, to.Ptr(armapimanagement.SubscriptionStateActive)),
+		CreatedDate:  to.Ptr(time.Now().UTC().Truncate(time.Second)),
+	}
+	c.subscriptions[resourceId] = props
+
+	return armapimanagement.SubscriptionClientCreateOrUpdateResponse{
+		SubscriptionContract: subscriptionContractOf(resourceId, sid, props),
+	}, nil
+}
+
+func (c *InMemorySubscriptionClient) Update(_ context.Context, resourceGroupName string, serviceName string, sid string, _ string, parameters armapimanagement.SubscriptionUpdateParameters, _ *armapimanagement.SubscriptionClientUpdateOptions) (armapimanagement.SubscriptionClientUpdateResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resourceId := c.resourceId(resourceGroupName, serviceName, sid)
+	current := c.subscriptions[resourceId]
+	if current == nil {
+		return armapimanagement.SubscriptionClientUpdateResponse{}, c.notFound(http.MethodPatch, resourceId, sid)
+	}
+
+	props := *current
+	if p := parameters.Properties; p != nil {
+		props.DisplayName = valueOr(p.DisplayName, props.DisplayName)
+		props.Scope = valueOr(p.Scope, props.Scope)
+		props.AllowTracing = valueOr(p.AllowTracing, props.AllowTracing)
+		props.OwnerID = valueOr(p.OwnerID, props.OwnerID)
+		props.PrimaryKey = valueOr(p.PrimaryKey, props.PrimaryKey)
+		props.SecondaryKey = valueOr(p.SecondaryKey, props.SecondaryKey)
+		props.State = valueOr(p.State, props.State)
+	}
+	c.subscriptions[resourceId] = &props
+
+	return armapimanagement.SubscriptionClientUpdateResponse{
+		SubscriptionContract: subscriptionContractOf(resourceId, sid, &props),
+	}, nil
+}
+
+func (c *InMemorySubscriptionClient) Delete(_ context.Context, resourceGroupName string, serviceName string, sid string, _ string, _ *armapimanagement.SubscriptionClientDeleteOptions) (armapimanagement.SubscriptionClientDeleteResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.subscriptions, c.resourceId(resourceGroupName, serviceName, sid))
+	return armapimanagement.SubscriptionClientDeleteResponse{}, nil
+}
+
+// SubscriptionKeys the primary and the secondary key of the API management subscription; false where the
+// service doesn't have the subscription.
+func (c *InMemorySubscriptionClient) SubscriptionKeys(resourceGroupName, serviceName, sid string) (string, string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if props := c.subscriptions[c.resourceId(resourceGroupName, serviceName, sid)]; props != nil {
+		return *props.PrimaryKey, *props.SecondaryKey, true
+	}
+	return "", "", false
+}
+
+var _ core.ApimSubscriptionClientAbstraction = &InMemorySubscriptionClient{}
@@ -0,0 +1,71 @@
+package testsupport
+
+import (
+	"crypto/rsa"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/apim"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/general"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/keyvault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// NewLocalKeyDecrypter the decrypter unwrapping the ciphertexts with the RSA key held in the memory of the test,
+// as the Key Vault would do with the wrapping key using RSA-OAEP-256.
+func NewLocalKeyDecrypter(privateKey *rsa.PrivateKey) core.RSADecrypter {
+	return func(input []byte) ([]byte, error) {
+		return core.RsaDecryptBytes(privateKey, input, nil)
+	}
+}
+
+// Ciphertexts mints the ciphertexts of the confidential objects, as the encryption functions of the provider and
+// tfgen would, so that the tests need not embed pre-computed ciphertexts. The ciphertexts are returned in the form
+// the resources accept in the `encrypted_...` attributes.
+type Ciphertexts struct {
+	PublicKey *rsa.PublicKey
+}
+
+func NewCiphertexts(wrappingKey *rsa.PrivateKey) Ciphertexts {
+	return Ciphertexts{PublicKey: &wrappingKey.PublicKey}
+}
+
+// Secret the ciphertext of the Key Vault secret; the ciphertext is locked to the destination where it is given.
+func (c Ciphertexts) Secret(value string, dest *core.AzKeyVaultObjectCoordinate, md core.SecondaryProtectionParameters) (string, error) {
+	em, _, err := keyvault.CreateSecretEncryptedMessage(value, dest, md, c.PublicKey)
+	return em.ToBase64PEM(), err
+}
+
+// Key the ciphertext of the Key Vault key given as the JSON web key (e.g. as jwk.Import produces it)
+func (c Ciphertexts) Key(jwkKey interface{}, dest *core.AzKeyVaultObjectCoordinate, md core.SecondaryProtectionParameters) (string, error) {
+	em, _, err := keyvault.CreateKeyEncryptedMessage(jwkKey, dest, md, c.PublicKey)
+	return em.ToBase64PEM(), err
+}
+
+// Certificate the ciphertext of the Key Vault certificate
+func (c Ciphertexts) Certificate(certData core.ConfidentialCertificateData, dest *core.AzKeyVaultObjectCoordinate, md core.SecondaryProtectionParameters) (string, error) {
+	em, _, err := keyvault.CreateCertificateEncryptedMessage(certData, dest, md, c.PublicKey)
+	return em.ToBase64PEM(), err
+}
+
+// NamedValue the ciphertext of the API management named value
+func (c Ciphertexts) NamedValue(value string, dest *apim.DestinationNamedValueModel, md core.SecondaryProtectionParameters) (string, error) {
+	em, _, err := apim.CreateNamedValueEncryptedMessage(value, dest, md, c.PublicKey)
+	return em.ToBase64PEM(), err
+}
+
+// Subscription the ciphertext of the keys of the API management subscription
+func (c Ciphertexts) Subscription(primaryKey, secondaryKey string, dest *apim.DestinationSubscriptionCoordinateModel, md core.SecondaryProtectionParameters) (string, error) {
+	keys := apim.SubscriptionDataFunctionParameter{
+		PrimaryKey:   types.StringValue(primaryKey),
+		SecondaryKey: types.StringValue(secondaryKey),
+	}
+
+	em, _, err := apim.CreateSubscriptionEncryptedMessage(keys, dest, md, c.PublicKey)
+	return em.ToBase64PEM(), err
+}
+
+// Content the ciphertext of the content unwrapped by the general content data source
+func (c Ciphertexts) Content(value string, md core.SecondaryProtectionParameters) (string, error) {
+	em, err := general.CreateContentEncryptedMessage(value, md, c.PublicKey)
+	return em.ToBase64PEM(), err
+}
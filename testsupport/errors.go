@@ -0,0 +1,65 @@
+package testsupport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// newResponseError the error the Azure SDK clients return on the response with the status code. The error is a
+// *azcore.ResponseError, so that the resources (e.g. core.IsResourceNotFoundError) recognise it as they
+// recognise the errors of the Azure APIs.
+func newResponseError(method string, objectUrl string, status int, errorCode string, message string) error {
+	reqUrl, _ := url.Parse(objectUrl)
+
+	body, _ := json.Marshal(map[string]any{
+		"error": map[string]string{
+			"code":    errorCode,
+			"message": message,
+		},
+	})
+
+	resp := &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Header:     http.Header{"x-ms-error-code": []string{errorCode}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    &http.Request{Method: method, URL: reqUrl},
+	}
+
+	return runtime.NewResponseError(resp)
+}
+
+func notFoundError(method string, objectUrl string, errorCode string, message string) error {
+	return newResponseError(method, objectUrl, http.StatusNotFound, errorCode, message)
+}
+
+func badRequestError(method string, objectUrl string, message string) error {
+	return newResponseError(method, objectUrl, http.StatusBadRequest, "BadParameter", message)
+}
+
+// StatusCodeOf the HTTP status code of the error returned by the in-memory clients; http.StatusInternalServerError
+// where the error doesn't convey the status code.
+func StatusCodeOf(err error) int {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorCodeOf the Azure error code of the error returned by the in-memory clients
+func ErrorCodeOf(err error) string {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.ErrorCode
+	}
+	return "InternalError"
+}
@@ -0,0 +1,273 @@
+// Package testsupport the in-memory implementation of the Azure clients factory and of the Azure clients the
+// resources use. The package is intended for the unit tests of the resources built on
+// resources.ConfidentialGenericResource, including the resources of the modules wrapping this provider: the
+// factory keeps the objects the resources create in memory, decrypts the ciphertexts with a local RSA key,
+// and records the tracked ciphertexts and the audit events, so that the tests can assert on these.
+package testsupport
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/provider"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// DefaultTenantId the Azure tenant of the in-memory factory
+const DefaultTenantId = "00000000-0000-0000-0000-00000000fa4e"
+
+// LocalWrappingKeyLabel the wrapping key recorded in the audit events of the decryption with the local key
+const LocalWrappingKeyLabel = "local/wrapping-key (RSA-OAEP-256)"
+
+// InMemoryAZClientsFactory the stateful in-memory implementation of core.AZClientsFactory. The policy of the
+// provider (the defaults, the provider labels, the placement constraints, the provenance tags, and the fingerprints)
+// is that of the embedded provider factory and is configured with its fields; the Azure clients, the object
+// tracking, the revocation list, and the audit are kept in memory.
+type InMemoryAZClientsFactory struct {
+	provider.AZClientsFactoryImpl
+
+	// Decrypter unwraps the ciphertexts irrespective of the wrapping key coordinate
+	Decrypter core.RSADecrypter
+
+	// ObjectTracking whether the factory tracks the ciphertexts
+	ObjectTracking bool
+
+	// Revocations the revoked ciphertexts by uuid
+	Revocations map[string]core.CiphertextRevocation
+
+	mutex         sync.Mutex
+	secrets       map[string]*InMemorySecretsClient
+	keys          map[string]*InMemoryKeysClient
+	certificates  map[string]*InMemoryCertificatesClient
+	namedValues   map[string]*InMemoryNamedValueClient
+	subscriptions map[string]*InMemorySubscriptionClient
+	tracked       map[string]*core.TrackedObject
+	auditEvents   []core.AuditEvent
+}
+
+// NewInMemoryAZClientsFactory creates the factory decrypting the ciphertexts with the wrapping key. The
+// ciphertexts for the factory are produced with NewCiphertexts(wrappingKey).
+func NewInMemoryAZClientsFactory(wrappingKey *rsa.PrivateKey) *InMemoryAZClientsFactory {
+	rv := &InMemoryAZClientsFactory{
+		Decrypter:     NewLocalKeyDecrypter(wrappingKey),
+		Revocations:   map[string]core.CiphertextRevocation{},
+		secrets:       map[string]*InMemorySecretsClient{},
+		keys:          map[string]*InMemoryKeysClient{},
+		certificates:  map[string]*InMemoryCertificatesClient{},
+		namedValues:   map[string]*InMemoryNamedValueClient{},
+		subscriptions: map[string]*InMemorySubscriptionClient{},
+		tracked:       map[string]*core.TrackedObject{},
+	}
+	rv.AzTenantId = DefaultTenantId
+
+	return rv
+}
+
+func clientOf[T any](f *InMemoryAZClientsFactory, clients map[string]*T, key string, create func(string) *T) *T {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if rv, ok := clients[key]; ok {
+		return rv
+	}
+	rv := create(key)
+	clients[key] = rv
+	return rv
+}
+
+// Secrets the secrets of the vault
+func (f *InMemoryAZClientsFactory) Secrets(vaultName string) *InMemorySecretsClient {
+	return clientOf(f, f.secrets, vaultName, NewInMemorySecretsClient)
+}
+
+// Keys the keys of the vault
+func (f *InMemoryAZClientsFactory) Keys(vaultName string) *InMemoryKeysClient {
+	return clientOf(f, f.keys, vaultName, NewInMemoryKeysClient)
+}
+
+// Certificates the certificates of the vault
+func (f *InMemoryAZClientsFactory) Certificates(vaultName string) *InMemoryCertificatesClient {
+	return clientOf(f, f.certificates, vaultName, NewInMemoryCertificatesClient)
+}
+
+// NamedValues the API management named values in the Azure subscription
+func (f *InMemoryAZClientsFactory) NamedValues(subscriptionId string) *InMemoryNamedValueClient {
+	return clientOf(f, f.namedValues, subscriptionId, NewInMemoryNamedValueClient)
+}
+
+// Subscriptions the API management subscriptions in the Azure subscription
+func (f *InMemoryAZClientsFactory) Subscriptions(subscriptionId string) *InMemorySubscriptionClient {
+	return clientOf(f, f.subscriptions, subscriptionId, NewInMemorySubscriptionClient)
+}
+
+func (f *InMemoryAZClientsFactory) GetSecretsClient(vaultName string) (core.AzSecretsClientAbstraction, error) {
+	return f.Secrets(vaultName), nil
+}
+
+func (f *InMemoryAZClientsFactory) GetKeysClient(vaultName string) (core.AzKeyClientAbstraction, error) {
+	return f.Keys(vaultName), nil
+}
+
+func (f *InMemoryAZClientsFactory) GetCertificateClient(vaultName string) (core.AzCertificateClientAbstraction, error) {
+	return f.Certificates(vaultName), nil
+}
+
+func (f *InMemoryAZClientsFactory) GetApimNamedValueClient(subscriptionId string) (core.ApimNamedValueClientAbstraction, error) {
+	return f.NamedValues(subscriptionId), nil
+}
+
+func (f *InMemoryAZClientsFactory) GetApimSubscriptionClient(subscriptionId string) (core.ApimSubscriptionClientAbstraction, error) {
+	return f.Subscriptions(subscriptionId), nil
+}
+
+// GetMergedWrappingKeyCoordinate merges the wrapping key coordinate with the defaults of the provider; the version
+// of the key is established from the keys kept in memory.
+func (f *InMemoryAZClientsFactory) GetMergedWrappingKeyCoordinate(ctx context.Context, param *core.WrappingKeyCoordinateModel) (core.WrappingKeyCoordinate, error) {
+	return f.MergeWrappingKeyCoordinate(ctx, param, f.GetKeysClient)
+}
+
+func (f *InMemoryAZClientsFactory) GetDecrypterFor(ctx context.Context, _ *core.WrappingKeyCoordinateModel) core.RSADecrypter {
+	return func(input []byte) ([]byte, error) {
+		if f.Decrypter == nil {
+			return nil, errors.New("in-memory factory has no decrypter")
+		}
+
+		rv, err := f.Decrypter(input)
+
+		event := core.AuditEvent{
+			Operation:   core.AuditOperationDecrypt,
+			WrappingKey: LocalWrappingKeyLabel,
+			Outcome:     core.AuditOutcomeSuccess,
+		}
+		if err != nil {
+			event.Outcome = core.AuditOutcomeFailure
+			event.Message = err.Error()
+		}
+		f.RecordAuditEvent(ctx, event, nil, nil)
+
+		return rv, err
+	}
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Object tracking
+
+func (f *InMemoryAZClientsFactory) IsObjectTrackingEnabled() bool {
+	return f.ObjectTracking
+}
+
+func (f *InMemoryAZClientsFactory) IsObjectIdTracked(_ context.Context, id string) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	_, ok := f.tracked[id]
+	return f.ObjectTracking && ok, nil
+}
+
+func (f *InMemoryAZClientsFactory) GetTackedObjectUses(_ context.Context, id string) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if obj, ok := f.tracked[id]; ok && f.ObjectTracking {
+		return obj.NumUses, nil
+	}
+	return 0, nil
+}
+
+func (f *InMemoryAZClientsFactory) TrackObjectId(_ context.Context, id string, destination string) error {
+	if !f.ObjectTracking {
+		return nil
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	ts := time.Now().UTC().Format(time.RFC3339)
+
+	obj, ok := f.tracked[id]
+	if !ok {
+		obj = &core.TrackedObject{Uuid: id, FirstUsedAt: ts, Destination: destination}
+		f.tracked[id] = obj
+	}
+	obj.NumUses++
+	obj.LastUsedAt = ts
+	if len(destination) > 0 && !core.Contains(destination, obj.Destinations) {
+		obj.Destinations = append(obj.Destinations, destination)
+	}
+
+	return nil
+}
+
+func (f *InMemoryAZClientsFactory) ListTrackedObjects(_ context.Context, uuids []string) ([]core.TrackedObject, error) {
+	if !f.ObjectTracking {
+		return nil, errors.New("object tracking is not configured on the provider")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var rv []core.TrackedObject
+	for id, obj := range f.tracked {
+		if len(uuids) == 0 || core.Contains(id, uuids) {
+			rv = append(rv, *obj)
+		}
+	}
+
+	sort.Slice(rv, func(i, j int) bool { return rv[i].Uuid < rv[j].Uuid })
+	return rv, nil
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Revocation and audit
+
+func (f *InMemoryAZClientsFactory) GetCiphertextRevocation(_ context.Context, uuid string) (*core.CiphertextRevocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if rv, ok := f.Revocations[uuid]; ok {
+		return &rv, nil
+	}
+	return nil, nil
+}
+
+func (f *InMemoryAZClientsFactory) RevokeCiphertext(_ context.Context, uuid string, reason string) (*core.CiphertextRevocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	rv := core.CiphertextRevocation{
+		Uuid:      uuid,
+		Reason:    reason,
+		RevokedAt: time.Now().Format(time.RFC3339),
+	}
+	f.Revocations[uuid] = rv
+	return &rv, nil
+}
+
+func (f *InMemoryAZClientsFactory) RecordAuditEvent(_ context.Context, event core.AuditEvent, _ *core.WrappingKeyCoordinateModel, _ *diag.Diagnostics) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if len(event.WrappingKey) == 0 {
+		event.WrappingKey = LocalWrappingKeyLabel
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.auditEvents = append(f.auditEvents, event)
+}
+
+// AuditEvents the audit events recorded by the factory, in the order of recording
+func (f *InMemoryAZClientsFactory) AuditEvents() []core.AuditEvent {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return append([]core.AuditEvent{}, f.auditEvents...)
+}
+
+var _ core.AZClientsFactory = &InMemoryAZClientsFactory{}
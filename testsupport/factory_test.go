@@ -0,0 +1,240 @@
+package testsupport
+
+import (
+	"context"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/keyvault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func givenWrappingKey(t *testing.T) *rsa.PrivateKey {
+	key, err := core.PrivateKeyFromData(testkeymaterial.EphemeralRsaKeyText)
+	assert.Nil(t, err)
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	assert.True(t, ok)
+	return rsaKey
+}
+
+func Test_InMemoryAZClientsFactory_DecryptsCiphertexts(t *testing.T) {
+	wrappingKey := givenWrappingKey(t)
+	factory := NewInMemoryAZClientsFactory(wrappingKey)
+
+	dest := &core.AzKeyVaultObjectCoordinate{VaultName: "vault", Type: "secrets", Name: "secret"}
+	ciphertext, err := NewCiphertexts(wrappingKey).Secret("s3cr3t", dest, core.SecondaryProtectionParameters{})
+	assert.Nil(t, err)
+
+	em := core.EncryptedMessage{}
+	assert.Nil(t, em.FromBase64PEM(ciphertext))
+
+	_, data, err := keyvault.DecryptSecretMessage(em, factory.GetDecrypterFor(context.Background(), nil))
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", data.GetStingData())
+
+	events := factory.AuditEvents()
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, core.AuditOperationDecrypt, events[0].Operation)
+	assert.Equal(t, core.AuditOutcomeSuccess, events[0].Outcome)
+	assert.Equal(t, LocalWrappingKeyLabel, events[0].WrappingKey)
+}
+
+func Test_InMemoryAZClientsFactory_RecordsFailedDecryption(t *testing.T) {
+	factory := NewInMemoryAZClientsFactory(givenWrappingKey(t))
+
+	_, err := factory.GetDecrypterFor(context.Background(), nil)([]byte("not a ciphertext"))
+	assert.NotNil(t, err)
+
+	events := factory.AuditEvents()
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, core.AuditOutcomeFailure, events[0].Outcome)
+}
+
+func Test_InMemoryAZClientsFactory_CreatesSecretThroughSpecializer(t *testing.T) {
+	factory := NewInMemoryAZClientsFactory(givenWrappingKey(t))
+
+	spec := keyvault.AzKeyVaultSecretResourceSpecializer{}
+	spec.SetFactory(factory)
+
+	mdl := keyvault.SecretModel{
+		DestinationSecret: core.AzKeyVaultObjectCoordinateModel{
+			VaultName: types.StringValue("vault"),
+			Name:      types.StringValue("secret"),
+		},
+	}
+
+	helper := core.NewVersionedStringConfidentialDataHelper(keyvault.SecretObjectType)
+	data := helper.CreateConfidentialStringData("s3cr3t", core.SecondaryProtectionParameters{})
+
+	secret, dg := spec.DoCreate(context.Background(), &mdl, data.Data)
+	assert.False(t, dg.HasError())
+	assert.Equal(t, "secret", secret.ID.Name())
+
+	value, ok := factory.Secrets("vault").SecretValue("secret")
+	assert.True(t, ok)
+	assert.Equal(t, "s3cr3t", value)
+}
+
+func Test_InMemorySecretsClient_NotFound(t *testing.T) {
+	client := NewInMemorySecretsClient("vault")
+
+	_, err := client.GetSecret(context.Background(), "missing", "", nil)
+	assert.True(t, core.IsResourceNotFoundError(err))
+	assert.Equal(t, 404, StatusCodeOf(err))
+}
+
+func Test_InMemorySecretsClient_UpdateKeepsValue(t *testing.T) {
+	client := NewInMemorySecretsClient("vault")
+
+	setResp, err := client.SetSecret(context.Background(), "secret", azsecrets.SetSecretParameters{Value: to.Ptr("v")}, nil)
+	assert.Nil(t, err)
+
+	_, err = client.UpdateSecretProperties(context.Background(), "secret", setResp.ID.Version(), azsecrets.UpdateSecretPropertiesParameters{
+		SecretAttributes: &azsecrets.SecretAttributes{Enabled: to.Ptr(false)},
+	}, nil)
+	assert.Nil(t, err)
+
+	getResp, err := client.GetSecret(context.Background(), "secret", "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "v", *getResp.Value)
+	assert.False(t, *getResp.Attributes.Enabled)
+}
+
+func Test_InMemoryKeysClient_DecryptsWithAddedKey(t *testing.T) {
+	wrappingKey := givenWrappingKey(t)
+	client := NewInMemoryKeysClient("vault")
+	version := client.AddRSAKey("wrapping-key", wrappingKey)
+
+	factory := NewInMemoryAZClientsFactory(wrappingKey)
+	factory.Decrypter = func(input []byte) ([]byte, error) {
+		resp, err := client.Decrypt(context.Background(), "wrapping-key", version, azkeys.KeyOperationParameters{
+			Algorithm: to.Ptr(azkeys.EncryptionAlgorithmRSAOAEP256),
+			Value:     input,
+		}, nil)
+		return resp.Result, err
+	}
+
+	ciphertext, err := core.RsaEncryptBytes(&wrappingKey.PublicKey, []byte("plain text"), nil)
+	assert.Nil(t, err)
+
+	plaintext, err := factory.GetDecrypterFor(context.Background(), nil)(ciphertext)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain text", string(plaintext))
+
+	getResp, err := client.GetKey(context.Background(), "wrapping-key", "", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, getResp.Key.D)
+}
+
+func Test_InMemoryAZClientsFactory_TracksObjects(t *testing.T) {
+	factory := NewInMemoryAZClientsFactory(givenWrappingKey(t))
+	ctx := context.Background()
+
+	assert.Nil(t, factory.TrackObjectId(ctx, "uuid", "dest"))
+	tracked, _ := factory.IsObjectIdTracked(ctx, "uuid")
+	assert.False(t, tracked)
+
+	factory.ObjectTracking = true
+	assert.Nil(t, factory.TrackObjectId(ctx, "uuid", "dest-a"))
+	assert.Nil(t, factory.TrackObjectId(ctx, "uuid", "dest-b"))
+
+	tracked, _ = factory.IsObjectIdTracked(ctx, "uuid")
+	assert.True(t, tracked)
+
+	uses, _ := factory.GetTackedObjectUses(ctx, "uuid")
+	assert.Equal(t, 2, uses)
+
+	objs, err := factory.ListTrackedObjects(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(objs))
+	assert.Equal(t, []string{"dest-a", "dest-b"}, objs[0].Destinations)
+}
+
+func Test_InMemoryAZClientsFactory_ReturnsRevocation(t *testing.T) {
+	factory := NewInMemoryAZClientsFactory(givenWrappingKey(t))
+	factory.Revocations["uuid"] = core.CiphertextRevocation{Uuid: "uuid", Reason: "leaked"}
+
+	rev, err := factory.GetCiphertextRevocation(context.Background(), "uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, "leaked", rev.Reason)
+
+	rev, err = factory.GetCiphertextRevocation(context.Background(), "other")
+	assert.Nil(t, err)
+	assert.Nil(t, rev)
+}
+
+func Test_InMemoryNamedValueClient_HidesSecretValue(t *testing.T) {
+	factory := NewInMemoryAZClientsFactory(givenWrappingKey(t))
+	client, _ := factory.GetApimNamedValueClient("sub")
+	ctx := context.Background()
+
+	poller, err := client.BeginCreateOrUpdate(ctx, "rg", "svc", "nv", armapimanagement.NamedValueCreateContract{
+		Properties: &armapimanagement.NamedValueCreateContractProperties{
+			DisplayName: to.Ptr("nv"),
+			Secret:      to.Ptr(true),
+			Value:       to.Ptr("value"),
+		},
+	}, nil)
+	assert.Nil(t, err)
+	_, err = poller.PollUntilDone(ctx, nil)
+	assert.Nil(t, err)
+
+	getResp, err := client.Get(ctx, "rg", "svc", "nv", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, getResp.Properties.Value)
+
+	listResp, err := client.ListValue(ctx, "rg", "svc", "nv", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "value", *listResp.Value)
+
+	_, err = client.Delete(ctx, "rg", "svc", "nv", "*", nil)
+	assert.Nil(t, err)
+
+	_, err = client.Get(ctx, "rg", "svc", "nv", nil)
+	assert.True(t, core.IsResourceNotFoundError(err))
+}
+
+func Test_InMemorySubscriptionClient_KeepsKeys(t *testing.T) {
+	factory := NewInMemoryAZClientsFactory(givenWrappingKey(t))
+	client, _ := factory.GetApimSubscriptionClient("sub")
+	ctx := context.Background()
+
+	_, err := client.CreateOrUpdate(ctx, "rg", "svc", "sid", armapimanagement.SubscriptionCreateParameters{
+		Properties: &armapimanagement.SubscriptionCreateParameterProperties{
+			DisplayName:  to.Ptr("Subscription"),
+			Scope:        to.Ptr("/apis"),
+			PrimaryKey:   to.Ptr("primary"),
+			SecondaryKey: to.Ptr("secondary"),
+		},
+	}, nil)
+	assert.Nil(t, err)
+
+	primary, secondary, ok := factory.Subscriptions("sub").SubscriptionKeys("rg", "svc", "sid")
+	assert.True(t, ok)
+	assert.Equal(t, "primary", primary)
+	assert.Equal(t, "secondary", secondary)
+
+	getResp, err := client.Get(ctx, "rg", "svc", "sid", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, getResp.Properties.PrimaryKey)
+}
+
+func Test_InMemoryAZClientsFactory_RecordsRevocation(t *testing.T) {
+	factory := NewInMemoryAZClientsFactory(givenWrappingKey(t))
+
+	rev, err := factory.RevokeCiphertext(context.Background(), "uuid", "leaked")
+	assert.Nil(t, err)
+	assert.Equal(t, "leaked", rev.Reason)
+
+	rev, err = factory.GetCiphertextRevocation(context.Background(), "uuid")
+	assert.Nil(t, err)
+	assert.Equal(t, "leaked", rev.Reason)
+}
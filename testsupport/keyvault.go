@@ -0,0 +1,562 @@
+package testsupport
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// ObjectId the identifier of the Key Vault object, as the Key Vault in the Azure public cloud returns it
+func ObjectId(vaultName, objectType, name, version string) string {
+	return fmt.Sprintf("https://%s.vault.azure.net/%s/%s/%s", vaultName, objectType, name, version)
+}
+
+type versionedObject[T any] struct {
+	latest   string
+	versions map[string]*T
+}
+
+type versionedObjects[T any] map[string]*versionedObject[T]
+
+// get the specified version of the object; the latest version where the version is empty
+func (v versionedObjects[T]) get(name, version string) (*T, string) {
+	obj := v[name]
+	if obj == nil {
+		return nil, ""
+	}
+	if len(version) == 0 {
+		version = obj.latest
+	}
+	return obj.versions[version], version
+}
+
+func (v versionedObjects[T]) add(name string, value *T) string {
+	obj := v[name]
+	if obj == nil {
+		obj = &versionedObject[T]{versions: map[string]*T{}}
+		v[name] = obj
+	}
+
+	version := newVersion()
+	obj.latest = version
+	obj.versions[version] = value
+	return version
+}
+
+func newVersion() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func now() *time.Time {
+	return to.Ptr(time.Now().Truncate(time.Second))
+}
+
+func valueOr[T any](v *T, def *T) *T {
+	if v != nil {
+		return v
+	}
+	return def
+}
+
+func copyOf[T any](v T) *T {
+	return &v
+}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Secrets
+
+// InMemorySecretsClient the secrets of a single vault kept in memory
+type InMemorySecretsClient struct {
+	VaultName string
+
+	mutex   sync.Mutex
+	secrets versionedObjects[azsecrets.Secret]
+}
+
+func NewInMemorySecretsClient(vaultName string) *InMemorySecretsClient {
+	return &InMemorySecretsClient{
+		VaultName: vaultName,
+		secrets:   versionedObjects[azsecrets.Secret]{},
+	}
+}
+
+func (c *InMemorySecretsClient) GetSecret(_ context.Context, name string, version string, _ *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	secret, _ := c.secrets.get(name, version)
+	if secret == nil {
+		return azsecrets.GetSecretResponse{}, notFoundError(http.MethodGet, ObjectId(c.VaultName, "secrets", name, version), "SecretNotFound",
+			fmt.Sprintf("A secret with (name/id) %s was not found in this key vault", name))
+	}
+
+	return azsecrets.GetSecretResponse{Secret: *copyOf(*secret)}, nil
+}
+
+func (c *InMemorySecretsClient) SetSecret(_ context.Context, name string, parameters azsecrets.SetSecretParameters, _ *azsecrets.SetSecretOptions) (azsecrets.SetSecretResponse, error) {
+	if parameters.Value == nil {
+		return azsecrets.SetSecretResponse{}, badRequestError(http.MethodPut, ObjectId(c.VaultName, "secrets", name, ""), "secret value is required")
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	attr := azsecrets.SecretAttributes{}
+	if parameters.SecretAttributes != nil {
+		attr = *parameters.SecretAttributes
+	}
+	attr.Enabled = valueOr(attr.Enabled, to.Ptr(true))
+	attr.Created = now()
+	attr.Updated = attr.Created
+
+	secret := &azsecrets.Secret{
+		Value:       to.Ptr(*parameters.Value),
+		ContentType: parameters.ContentType,
+		Attributes:  &attr,
+		Tags:        parameters.Tags,
+	}
+	version := c.secrets.add(name, secret)
+	secret.ID = to.Ptr(azsecrets.ID(ObjectId(c.VaultName, "secrets", name, version)))
+
+	return azsecrets.SetSecretResponse{Secret: *copyOf(*secret)}, nil
+}
+
+func (c *InMemorySecretsClient) UpdateSecretProperties(_ context.Context, name string, version string, parameters azsecrets.UpdateSecretPropertiesParameters, _ *azsecrets.UpdateSecretPropertiesOptions) (azsecrets.UpdateSecretPropertiesResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	secret, _ := c.secrets.get(name, version)
+	if secret == nil {
+		return azsecrets.UpdateSecretPropertiesResponse{}, notFoundError(http.MethodPatch, ObjectId(c.VaultName, "secrets", name, version), "SecretNotFound",
+			fmt.Sprintf("A secret with (name/id) %s was not found in this key vault", name))
+	}
+
+	secret.ContentType = valueOr(parameters.ContentType, secret.ContentType)
+	if parameters.Tags != nil {
+		secret.Tags = parameters.Tags
+	}
+	attr := *secret.Attributes
+	if p := parameters.SecretAttributes; p != nil {
+		attr.Enabled = valueOr(p.Enabled, attr.Enabled)
+		attr.NotBefore = p.NotBefore
+		attr.Expires = p.Expires
+	}
+	attr.Updated = now()
+	secret.Attributes = &attr
+
+	// The value of the secret is not returned by the update
+	rv := *secret
+	rv.Value = nil
+	return azsecrets.UpdateSecretPropertiesResponse{Secret: rv}, nil
+}
+
+// SecretValue the value of the latest version of the secret; false where the vault doesn't have the secret.
+func (c *InMemorySecretsClient) SecretValue(name string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if secret, _ := c.secrets.get(name, ""); secret != nil {
+		return *secret.Value, true
+	}
+	return "", false
+}
+
+var _ core.AzSecretsClientAbstraction = &InMemorySecretsClient{}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Keys
+
+type inMemoryKey struct {
+	bundle     azkeys.KeyBundle
+	privateKey *rsa.PrivateKey
+}
+
+// InMemoryKeysClient the keys of a single vault kept in memory. The RSA keys which private components are
+// known (i.e. the keys imported or added with AddRSAKey) can decrypt.
+type InMemoryKeysClient struct {
+	VaultName string
+
+	mutex sync.Mutex
+	keys  versionedObjects[inMemoryKey]
+}
+
+func NewInMemoryKeysClient(vaultName string) *InMemoryKeysClient {
+	return &InMemoryKeysClient{
+		VaultName: vaultName,
+		keys:      versionedObjects[inMemoryKey]{},
+	}
+}
+
+func (c *InMemoryKeysClient) keyNotFound(method, name, version string) error {
+	return notFoundError(method, ObjectId(c.VaultName, "keys", name, version), "KeyNotFound",
+		fmt.Sprintf("A key with (name/id) %s was not found in this key vault", name))
+}
+
+func (c *InMemoryKeysClient) addKey(name string, key *inMemoryKey) azkeys.KeyBundle {
+	attr := azkeys.KeyAttributes{}
+	if key.bundle.Attributes != nil {
+		attr = *key.bundle.Attributes
+	}
+	attr.Enabled = valueOr(attr.Enabled, to.Ptr(true))
+	attr.Created = now()
+	attr.Updated = attr.Created
+	key.bundle.Attributes = &attr
+
+	version := c.keys.add(name, key)
+	key.bundle.Key.KID = to.Ptr(azkeys.ID(ObjectId(c.VaultName, "keys", name, version)))
+
+	return publicKeyBundleOf(key)
+}
+
+// publicKeyBundleOf the key bundle as Key Vault returns it: the private components of the key are never returned.
+func publicKeyBundleOf(key *inMemoryKey) azkeys.KeyBundle {
+	jwk := key.bundle.Key
+	return azkeys.KeyBundle{
+		Key: &azkeys.JSONWebKey{
+			KID:    jwk.KID,
+			Kty:    jwk.Kty,
+			KeyOps: jwk.KeyOps,
+			N:      jwk.N,
+			E:      jwk.E,
+			Crv:    jwk.Crv,
+			X:      jwk.X,
+			Y:      jwk.Y,
+		},
+		Attributes: copyOf(*key.bundle.Attributes),
+		Tags:       key.bundle.Tags,
+	}
+}
+
+func (c *InMemoryKeysClient) ImportKey(_ context.Context, name string, parameters azkeys.ImportKeyParameters, _ *azkeys.ImportKeyOptions) (azkeys.ImportKeyResponse, error) {
+	if parameters.Key == nil {
+		return azkeys.ImportKeyResponse{}, badRequestError(http.MethodPut, ObjectId(c.VaultName, "keys", name, ""), "key is required")
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	bundle := c.addKey(name, &inMemoryKey{
+		bundle: azkeys.KeyBundle{
+			Key:        copyOf(*parameters.Key),
+			Attributes: parameters.KeyAttributes,
+			Tags:       parameters.Tags,
+		},
+		privateKey: rsaPrivateKeyOf(parameters.Key),
+	})
+
+	return azkeys.ImportKeyResponse{KeyBundle: bundle}, nil
+}
+
+// CreateKey generates the RSA key of the requested size; other key types are not supported by the in-memory client.
+func (c *InMemoryKeysClient) CreateKey(_ context.Context, name string, parameters azkeys.CreateKeyParameters, _ *azkeys.CreateKeyOptions) (azkeys.CreateKeyResponse, error) {
+	opUrl := ObjectId(c.VaultName, "keys", name, "") + "/create"
+	if parameters.Kty == nil || (*parameters.Kty != azkeys.KeyTypeRSA && *parameters.Kty != azkeys.KeyTypeRSAHSM) {
+		return azkeys.CreateKeyResponse{}, badRequestError(http.MethodPost, opUrl, "only RSA keys can be created")
+	}
+
+	keySize := 2048
+	if parameters.KeySize != nil {
+		keySize = int(*parameters.KeySize)
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return azkeys.CreateKeyResponse{}, badRequestError(http.MethodPost, opUrl, err.Error())
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	bundle := c.addKey(name, &inMemoryKey{
+		bundle: azkeys.KeyBundle{
+			Key: &azkeys.JSONWebKey{
+				Kty:    parameters.Kty,
+				KeyOps: parameters.KeyOps,
+				N:      privateKey.N.Bytes(),
+				E:      big.NewInt(int64(privateKey.E)).Bytes(),
+			},
+			Attributes: parameters.KeyAttributes,
+			Tags:       parameters.Tags,
+		},
+		privateKey: privateKey,
+	})
+
+	return azkeys.CreateKeyResponse{KeyBundle: bundle}, nil
+}
+
+func (c *InMemoryKeysClient) GetKey(_ context.Context, name string, version string, _ *azkeys.GetKeyOptions) (azkeys.GetKeyResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key, _ := c.keys.get(name, version)
+	if key == nil {
+		return azkeys.GetKeyResponse{}, c.keyNotFound(http.MethodGet, name, version)
+	}
+	return azkeys.GetKeyResponse{KeyBundle: publicKeyBundleOf(key)}, nil
+}
+
+func (c *InMemoryKeysClient) UpdateKey(_ context.Context, name string, version string, parameters azkeys.UpdateKeyParameters, _ *azkeys.UpdateKeyOptions) (azkeys.UpdateKeyResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key, _ := c.keys.get(name, version)
+	if key == nil {
+		return azkeys.UpdateKeyResponse{}, c.keyNotFound(http.MethodPatch, name, version)
+	}
+
+	if parameters.Tags != nil {
+		key.bundle.Tags = parameters.Tags
+	}
+	if parameters.KeyOps != nil {
+		key.bundle.Key.KeyOps = parameters.KeyOps
+	}
+	attr := *key.bundle.Attributes
+	if p := parameters.KeyAttributes; p != nil {
+		attr.Enabled = valueOr(p.Enabled, attr.Enabled)
+		attr.NotBefore = p.NotBefore
+		attr.Expires = p.Expires
+	}
+	attr.Updated = now()
+	key.bundle.Attributes = &attr
+
+	return azkeys.UpdateKeyResponse{KeyBundle: publicKeyBundleOf(key)}, nil
+}
+
+func (c *InMemoryKeysClient) Decrypt(_ context.Context, name string, version string, parameters azkeys.KeyOperationParameters, _ *azkeys.DecryptOptions) (azkeys.DecryptResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	opUrl := ObjectId(c.VaultName, "keys", name, version) + "/decrypt"
+
+	key, _ := c.keys.get(name, version)
+	if key == nil {
+		return azkeys.DecryptResponse{}, c.keyNotFound(http.MethodPost, name, version)
+	} else if key.privateKey == nil {
+		return azkeys.DecryptResponse{}, badRequestError(http.MethodPost, opUrl, "the key cannot decrypt")
+	} else if enabled := key.bundle.Attributes.Enabled; enabled != nil && !*enabled {
+		return azkeys.DecryptResponse{}, newResponseError(http.MethodPost, opUrl, http.StatusForbidden, "Forbidden", "Operation decrypt is not allowed on a disabled key.")
+	} else if parameters.Algorithm == nil {
+		return azkeys.DecryptResponse{}, badRequestError(http.MethodPost, opUrl, "algorithm is required")
+	}
+
+	plaintext, err := RSADecrypt(key.privateKey, *parameters.Algorithm, parameters.Value)
+	if err != nil {
+		return azkeys.DecryptResponse{}, badRequestError(http.MethodPost, opUrl, err.Error())
+	}
+
+	return azkeys.DecryptResponse{
+		KeyOperationResult: azkeys.KeyOperationResult{
+			KID:    key.bundle.Key.KID,
+			Result: plaintext,
+		},
+	}, nil
+}
+
+// AddRSAKey places the RSA key into the vault (e.g. as the wrapping key of the ciphertexts). The returned value is
+// the version of the key.
+func (c *InMemoryKeysClient) AddRSAKey(name string, privateKey *rsa.PrivateKey) string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	bundle := c.addKey(name, &inMemoryKey{
+		bundle: azkeys.KeyBundle{
+			Key: &azkeys.JSONWebKey{
+				Kty: to.Ptr(azkeys.KeyTypeRSA),
+				KeyOps: []*azkeys.KeyOperation{
+					to.Ptr(azkeys.KeyOperationEncrypt),
+					to.Ptr(azkeys.KeyOperationDecrypt),
+					to.Ptr(azkeys.KeyOperationWrapKey),
+					to.Ptr(azkeys.KeyOperationUnwrapKey),
+				},
+				N: privateKey.N.Bytes(),
+				E: big.NewInt(int64(privateKey.E)).Bytes(),
+			},
+		},
+		privateKey: privateKey,
+	})
+
+	return bundle.Key.KID.Version()
+}
+
+// RSADecrypt decrypts the ciphertext with the RSA key using the Key Vault encryption algorithm
+func RSADecrypt(privateKey *rsa.PrivateKey, alg azkeys.EncryptionAlgorithm, ciphertext []byte) ([]byte, error) {
+	switch alg {
+	case azkeys.EncryptionAlgorithmRSAOAEP256:
+		return rsa.DecryptOAEP(sha256.New(), nil, privateKey, ciphertext, nil)
+	case azkeys.EncryptionAlgorithmRSAOAEP:
+		return rsa.DecryptOAEP(sha1.New(), nil, privateKey, ciphertext, nil)
+	case azkeys.EncryptionAlgorithmRSA15:
+		return rsa.DecryptPKCS1v15(nil, privateKey, ciphertext)
+	default:
+		return nil, fmt.Errorf("algorithm %s is not supported", alg)
+	}
+}
+
+func rsaPrivateKeyOf(key *azkeys.JSONWebKey) *rsa.PrivateKey {
+	if len(key.N) == 0 || len(key.D) == 0 || len(key.P) == 0 || len(key.Q) == 0 {
+		return nil
+	}
+
+	rv := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{
+			N: new(big.Int).SetBytes(key.N),
+			E: int(new(big.Int).SetBytes(key.E).Int64()),
+		},
+		D:      new(big.Int).SetBytes(key.D),
+		Primes: []*big.Int{new(big.Int).SetBytes(key.P), new(big.Int).SetBytes(key.Q)},
+	}
+	if rv.Validate() != nil {
+		return nil
+	}
+	rv.Precompute()
+	return rv
+}
+
+var _ core.AzKeyClientAbstraction = &InMemoryKeysClient{}
+
+// ----------------------------------------------------------------------------------------------------------------
+// Certificates
+
+// InMemoryCertificatesClient the certificates of a single vault kept in memory
+type InMemoryCertificatesClient struct {
+	VaultName string
+
+	mutex        sync.Mutex
+	certificates versionedObjects[azcertificates.Certificate]
+}
+
+func NewInMemoryCertificatesClient(vaultName string) *InMemoryCertificatesClient {
+	return &InMemoryCertificatesClient{
+		VaultName:    vaultName,
+		certificates: versionedObjects[azcertificates.Certificate]{},
+	}
+}
+
+func (c *InMemoryCertificatesClient) certificateNotFound(method, name, version string) error {
+	return notFoundError(method, ObjectId(c.VaultName, "certificates", name, version), "CertificateNotFound",
+		fmt.Sprintf("A certificate with (name/id) %s was not found in this key vault", name))
+}
+
+func (c *InMemoryCertificatesClient) ImportCertificate(_ context.Context, name string, parameters azcertificates.ImportCertificateParameters, _ *azcertificates.ImportCertificateOptions) (azcertificates.ImportCertificateResponse, error) {
+	opUrl := ObjectId(c.VaultName, "certificates", name, "import")
+	if parameters.Base64EncodedCertificate == nil {
+		return azcertificates.ImportCertificateResponse{}, badRequestError(http.MethodPost, opUrl, "certificate is required")
+	}
+
+	cer, cerErr := CertificateDEROf(*parameters.Base64EncodedCertificate, core.StringPtrValue(parameters.Password))
+	if cerErr != nil {
+		return azcertificates.ImportCertificateResponse{}, badRequestError(http.MethodPost, opUrl, cerErr.Error())
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	attr := azcertificates.CertificateAttributes{}
+	if parameters.CertificateAttributes != nil {
+		attr = *parameters.CertificateAttributes
+	}
+	attr.Enabled = valueOr(attr.Enabled, to.Ptr(true))
+	attr.Created = now()
+	attr.Updated = attr.Created
+	if cert, parseErr := x509.ParseCertificate(cer); parseErr == nil {
+		attr.NotBefore = to.Ptr(cert.NotBefore)
+		attr.Expires = to.Ptr(cert.NotAfter)
+	}
+
+	thumbprint := sha1.Sum(cer)
+	certificate := &azcertificates.Certificate{
+		CER:            cer,
+		X509Thumbprint: thumbprint[:],
+		Policy:         parameters.CertificatePolicy,
+		Attributes:     &attr,
+		Tags:           parameters.Tags,
+	}
+
+	version := c.certificates.add(name, certificate)
+	certificate.ID = to.Ptr(azcertificates.ID(ObjectId(c.VaultName, "certificates", name, version)))
+	certificate.KID = to.Ptr(azcertificates.ID(ObjectId(c.VaultName, "keys", name, version)))
+	certificate.SID = to.Ptr(azcertificates.ID(ObjectId(c.VaultName, "secrets", name, version)))
+
+	return azcertificates.ImportCertificateResponse{Certificate: *copyOf(*certificate)}, nil
+}
+
+func (c *InMemoryCertificatesClient) GetCertificate(_ context.Context, name string, version string, _ *azcertificates.GetCertificateOptions) (azcertificates.GetCertificateResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	certificate, _ := c.certificates.get(name, version)
+	if certificate == nil {
+		return azcertificates.GetCertificateResponse{}, c.certificateNotFound(http.MethodGet, name, version)
+	}
+	return azcertificates.GetCertificateResponse{Certificate: *copyOf(*certificate)}, nil
+}
+
+func (c *InMemoryCertificatesClient) UpdateCertificate(_ context.Context, name string, version string, parameters azcertificates.UpdateCertificateParameters, _ *azcertificates.UpdateCertificateOptions) (azcertificates.UpdateCertificateResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	certificate, _ := c.certificates.get(name, version)
+	if certificate == nil {
+		return azcertificates.UpdateCertificateResponse{}, c.certificateNotFound(http.MethodPatch, name, version)
+	}
+
+	if parameters.Tags != nil {
+		certificate.Tags = parameters.Tags
+	}
+	attr := *certificate.Attributes
+	if p := parameters.CertificateAttributes; p != nil {
+		attr.Enabled = valueOr(p.Enabled, attr.Enabled)
+	}
+	attr.Updated = now()
+	certificate.Attributes = &attr
+
+	return azcertificates.UpdateCertificateResponse{Certificate: *copyOf(*certificate)}, nil
+}
+
+// CertificateDEROf the DER-encoded leaf certificate of the base64-encoded PEM or PKCS12 data, as the certificate
+// import of the Key Vault accepts it.
+func CertificateDEROf(base64Data string, password string) ([]byte, error) {
+	data, decodeErr := base64.StdEncoding.DecodeString(base64Data)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("certificate data is not base64-encoded: %s", decodeErr.Error())
+	}
+
+	if block, rest := pem.Decode(data); block != nil {
+		for block != nil {
+			if block.Type == "CERTIFICATE" {
+				return block.Bytes, nil
+			}
+			block, rest = pem.Decode(rest)
+		}
+		return nil, errors.New("PEM data contains no certificate")
+	}
+
+	_, cert, _, p12Err := pkcs12.DecodeChain(data, password)
+	if p12Err != nil {
+		return nil, fmt.Errorf("cannot read PKCS12 data: %s", p12Err.Error())
+	}
+	return cert.Raw, nil
+}
+
+var _ core.AzCertificateClientAbstraction = &InMemoryCertificatesClient{}
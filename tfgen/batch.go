@@ -0,0 +1,437 @@
+package tfgen
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/cmdgroups/apim"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/cmdgroups/keyvault"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/model"
+	"gopkg.in/yaml.v3"
+)
+
+const BatchCommand = "batch"
+
+const (
+	ManifestCliOption   model.CLIOption = "manifest"
+	OutputFileCliOption model.CLIOption = "output-file"
+	OutputDirCliOption  model.CLIOption = "output-dir"
+)
+
+// BatchInputSource a source of the confidential input of a batch entry. Exactly one of file or
+// environment variable must be specified.
+type BatchInputSource struct {
+	File string `yaml:"file"`
+	Env  string `yaml:"env"`
+
+	data []byte
+}
+
+func (s *BatchInputSource) load() error {
+	if len(s.File) > 0 && len(s.Env) > 0 {
+		return errors.New("input source must specify either file or env, but not both")
+	}
+
+	if len(s.File) > 0 {
+		if data, err := os.ReadFile(s.File); err != nil {
+			return fmt.Errorf("cannot read input file %s: %s", s.File, err.Error())
+		} else {
+			s.data = data
+		}
+	} else if len(s.Env) > 0 {
+		if v, ok := os.LookupEnv(s.Env); !ok || len(v) == 0 {
+			return fmt.Errorf("environment variable %s is not set", s.Env)
+		} else {
+			s.data = []byte(v)
+		}
+	} else {
+		return errors.New("input source must specify either file or env")
+	}
+
+	if len(s.data) == 0 {
+		return errors.New("input source yields empty input")
+	}
+
+	return nil
+}
+
+// BatchProtectionParams protection parameters of a batch entry. Where specified, these override the
+// standard options given on the command line.
+type BatchProtectionParams struct {
+	ProviderConstraints []string   `yaml:"provider_constraints"`
+	ProviderAllOf       [][]string `yaml:"provider_all_of"`
+	ProviderAnyOf       [][]string `yaml:"provider_any_of"`
+	ProviderNoneOf      [][]string `yaml:"provider_none_of"`
+	AzureTenants        []string   `yaml:"azure_tenants"`
+	AzureSubscriptions  []string   `yaml:"azure_subscriptions"`
+	LockDestination     *bool      `yaml:"lock_destination"`
+	PlacementGlobs      []string   `yaml:"placement_globs"`
+	PlacementRegexes    []string   `yaml:"placement_regexes"`
+	TimeToCreate        string     `yaml:"time_to_create"`
+	NoCreateLimit       *bool      `yaml:"no_create_limit"`
+	NotBefore           string     `yaml:"not_before"`
+	DaysToExpire        *int       `yaml:"days_to_expire"`
+	NoExpiryLimit       *bool      `yaml:"no_expiry_limit"`
+	NumUses             *int       `yaml:"num_uses"`
+	CreateOnce          *bool      `yaml:"create_once"`
+	NoUsageLimit        *bool      `yaml:"no_usage_limit"`
+}
+
+func joinedGroups(groups [][]string) repeatedOption {
+	rv := make(repeatedOption, len(groups))
+	for i, g := range groups {
+		rv[i] = strings.Join(g, ",")
+	}
+	return rv
+}
+
+func (p *BatchProtectionParams) applyTo(args *EntryPointCLIArgs) error {
+	if p == nil {
+		return nil
+	}
+
+	if len(p.ProviderConstraints) > 0 {
+		args.ProviderConstraints = strings.Join(p.ProviderConstraints, ",")
+	}
+	if len(p.ProviderAllOf) > 0 {
+		args.ProviderAllOf = joinedGroups(p.ProviderAllOf)
+	}
+	if len(p.ProviderAnyOf) > 0 {
+		args.ProviderAnyOf = joinedGroups(p.ProviderAnyOf)
+	}
+	if len(p.ProviderNoneOf) > 0 {
+		args.ProviderNoneOf = joinedGroups(p.ProviderNoneOf)
+	}
+	if len(p.AzureTenants) > 0 {
+		args.AzureTenants = strings.Join(p.AzureTenants, ",")
+	}
+	if len(p.AzureSubscriptions) > 0 {
+		args.AzureSubscriptions = strings.Join(p.AzureSubscriptions, ",")
+	}
+	if p.LockDestination != nil {
+		args.ConstraintTarget = *p.LockDestination
+	}
+	if len(p.PlacementGlobs) > 0 {
+		args.PlacementGlobs = p.PlacementGlobs
+	}
+	if len(p.PlacementRegexes) > 0 {
+		args.PlacementRegexes = p.PlacementRegexes
+	}
+	if len(p.TimeToCreate) > 0 {
+		if d, err := time.ParseDuration(p.TimeToCreate); err != nil {
+			return fmt.Errorf("invalid time_to_create: %s", err.Error())
+		} else {
+			args.CreateLimit = d
+		}
+	}
+	if p.NoCreateLimit != nil {
+		args.NoCreateLimit = *p.NoCreateLimit
+	}
+	if len(p.NotBefore) > 0 {
+		args.NotBefore = p.NotBefore
+	}
+	if p.DaysToExpire != nil {
+		args.ExpiryDays = *p.DaysToExpire
+	}
+	if p.NoExpiryLimit != nil {
+		args.NoExpireLimit = *p.NoExpiryLimit
+	}
+	if p.NumUses != nil {
+		args.NumUses = *p.NumUses
+	}
+	if p.CreateOnce != nil {
+		args.CreateOnce = *p.CreateOnce
+	}
+	if p.NoUsageLimit != nil {
+		args.NoUsageLimit = *p.NoUsageLimit
+	}
+
+	return nil
+}
+
+// BatchEntry a single object to be encrypted in the batch.
+type BatchEntry struct {
+	// Name of the entry. It is used as the name of Terraform block and as the name of the file where
+	// a file per entry is produced.
+	Name    string `yaml:"name"`
+	Group   string `yaml:"group"`
+	Command string `yaml:"command"`
+
+	Input          *BatchInputSource `yaml:"input"`
+	SecondaryInput *BatchInputSource `yaml:"secondary_input"`
+	Password       *BatchInputSource `yaml:"password"`
+
+	// Destination options of the command, given without the leading dash, e.g. `destination-vault: my-vault`
+	Destination map[string]string      `yaml:"destination"`
+	Args        []string               `yaml:"args"`
+	Protection  *BatchProtectionParams `yaml:"protection"`
+}
+
+func (e *BatchEntry) commandArgs() []string {
+	keys := make([]string, 0, len(e.Destination))
+	for k := range e.Destination {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var rv []string
+	for _, k := range keys {
+		rv = append(rv, model.CLIOption(k).Opt(), e.Destination[k])
+	}
+
+	return append(rv, e.Args...)
+}
+
+// inputReader returns the reader supplying the entry's confidential inputs to the command. The public key
+// is read using the reader supplied to the batch.
+func (e *BatchEntry) inputReader(base model.InputReader) model.InputReader {
+	return func(prompt, fn string, base64Decode bool, multiline bool) ([]byte, error) {
+		var src *BatchInputSource
+
+		switch prompt {
+		case PublicKeyPrompt:
+			return base(prompt, fn, base64Decode, multiline)
+		case keyvault.PrivateKeyPasswordPrompt, keyvault.CertificatePasswordPrompt:
+			src = e.Password
+		case apim.SubscriptionSecondaryKeyPrompt:
+			src = e.SecondaryInput
+		default:
+			src = e.Input
+		}
+
+		if src == nil {
+			return nil, fmt.Errorf("entry %s does not specify input for prompt '%s'", e.Name, prompt)
+		}
+
+		if base64Decode {
+			return base64.StdEncoding.DecodeString(strings.TrimSpace(string(src.data)))
+		}
+		return src.data, nil
+	}
+}
+
+type BatchManifest struct {
+	Entries []BatchEntry `yaml:"entries"`
+}
+
+var batchEntryNameExpr = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_-]*$")
+
+type batchOutput struct {
+	name   string
+	tfCode model.TerraformCode
+}
+
+func CreateBatchArgParser() (*string, *string, *string, *flag.FlagSet) {
+	var manifestFile, outputFile, outputDir string
+
+	batchCmd := flag.NewFlagSet(BatchCommand, flag.ExitOnError)
+	batchCmd.StringVar(&manifestFile,
+		ManifestCliOption.String(),
+		"",
+		"YAML manifest listing objects to encrypt")
+
+	batchCmd.StringVar(&outputFile,
+		OutputFileCliOption.String(),
+		"",
+		"Write Terraform code for all entries into this file")
+
+	batchCmd.StringVar(&outputDir,
+		OutputDirCliOption.String(),
+		"",
+		"Write Terraform code for each entry into a separate file in this directory")
+
+	return &manifestFile, &outputFile, &outputDir, batchCmd
+}
+
+// RunBatch encrypts all entries listed in the manifest. All entries are validated and encrypted before
+// any output is written; a single invalid entry fails the entire batch. Where neither output file nor output
+// directory is given, the combined Terraform code is returned.
+func RunBatch(inputReader model.InputReader, cliArgs *EntryPointCLIArgs, args []string) (model.TerraformCode, error) {
+	manifestFile, outputFile, outputDir, batchCmd := CreateBatchArgParser()
+	if parseErr := batchCmd.Parse(args); parseErr != nil {
+		return "", parseErr
+	}
+
+	if len(*manifestFile) == 0 {
+		return "", fmt.Errorf("option %s is required", ManifestCliOption.Opt())
+	}
+	if len(*outputFile) > 0 && len(*outputDir) > 0 {
+		return "", fmt.Errorf("options %s and %s are mutually exclusive", OutputFileCliOption.Opt(), OutputDirCliOption.Opt())
+	}
+
+	manifestData, readErr := os.ReadFile(*manifestFile)
+	if readErr != nil {
+		return "", fmt.Errorf("cannot read manifest: %s", readErr.Error())
+	}
+
+	outputs, batchErr := ProcessBatchManifest(inputReader, cliArgs, manifestData)
+	if batchErr != nil {
+		return "", batchErr
+	}
+
+	return writeBatchOutput(outputs, *outputFile, *outputDir)
+}
+
+// writeBatchOutput writes the Terraform code into the output file or, a file per output, into the output
+// directory. Where neither is given, the combined Terraform code is returned. Existing files are never
+// overwritten; where any of the files cannot be written, the files already written are removed.
+func writeBatchOutput(outputs []batchOutput, outputFile, outputDir string) (model.TerraformCode, error) {
+	if len(outputDir) > 0 {
+		if mkdirErr := os.MkdirAll(outputDir, 0755); mkdirErr != nil {
+			return "", fmt.Errorf("cannot create output directory: %s", mkdirErr.Error())
+		}
+
+		files := make([]string, len(outputs))
+		var existing []string
+		for i, o := range outputs {
+			files[i] = filepath.Join(outputDir, fmt.Sprintf("%s.tf", o.name))
+			if _, statErr := os.Lstat(files[i]); statErr == nil {
+				existing = append(existing, files[i])
+			}
+		}
+		if len(existing) > 0 {
+			return "", fmt.Errorf("refusing to overwrite existing files: %s", strings.Join(existing, ", "))
+		}
+
+		for i, o := range outputs {
+			if writeErr := writeNewFile(files[i], []byte(o.tfCode), 0644); writeErr != nil {
+				for _, written := range files[:i] {
+					_ = os.Remove(written)
+				}
+				return "", writeErr
+			}
+		}
+
+		for _, fn := range files {
+			fmt.Printf("Wrote %s\n", fn)
+		}
+		return "", nil
+	}
+
+	combined := combineBatchOutput(outputs)
+	if len(outputFile) > 0 {
+		if writeErr := writeNewFile(outputFile, []byte(combined), 0644); writeErr != nil {
+			return "", writeErr
+		}
+		fmt.Printf("Wrote %s\n", outputFile)
+		return "", nil
+	}
+
+	return combined, nil
+}
+
+func combineBatchOutput(outputs []batchOutput) model.TerraformCode {
+	rv := make([]string, len(outputs))
+	for i, o := range outputs {
+		rv[i] = o.tfCode.String()
+	}
+
+	return model.TerraformCode(strings.Join(rv, "\n\n"))
+}
+
+// ProcessBatchManifest validates all manifest entries and, where all are valid, produces the Terraform
+// code for every entry.
+func ProcessBatchManifest(inputReader model.InputReader, cliArgs *EntryPointCLIArgs, manifestData []byte) ([]batchOutput, error) {
+	manifest := BatchManifest{}
+	if yamlErr := yaml.Unmarshal(manifestData, &manifest); yamlErr != nil {
+		return nil, fmt.Errorf("cannot parse manifest: %s", yamlErr.Error())
+	}
+
+	if len(manifest.Entries) == 0 {
+		return nil, errors.New("manifest does not list any entries")
+	}
+
+	commonKwp, commonKwpErr := buildContentWrappingParams(inputReader, cliArgs)
+	if commonKwpErr != nil {
+		return nil, commonKwpErr
+	}
+
+	// Validation pass: every entry must be complete before anything is encrypted
+	var validationErrs []error
+	generators := make([]model.SubCommandExecution, len(manifest.Entries))
+	seenNames := map[string]bool{}
+
+	for i := range manifest.Entries {
+		entry := &manifest.Entries[i]
+
+		generator, entryErr := prepareBatchEntry(inputReader, commonKwp, cliArgs, entry, seenNames)
+		if entryErr != nil {
+			validationErrs = append(validationErrs, fmt.Errorf("entry %d (%s): %s", i+1, entry.Name, entryErr.Error()))
+		} else {
+			generators[i] = generator
+		}
+	}
+
+	if len(validationErrs) > 0 {
+		return nil, errors.Join(validationErrs...)
+	}
+
+	// Encryption pass. Output is kept in memory until all entries are successfully encrypted.
+	rv := make([]batchOutput, len(manifest.Entries))
+	for i, generator := range generators {
+		entry := &manifest.Entries[i]
+
+		tfCode, _, genErr := generator(entry.inputReader(inputReader))
+		if genErr != nil {
+			return nil, fmt.Errorf("entry %d (%s): %s", i+1, entry.Name, genErr.Error())
+		}
+
+		rv[i] = batchOutput{
+			name:   entry.Name,
+			tfCode: tfCode,
+		}
+	}
+
+	return rv, nil
+}
+
+func prepareBatchEntry(inputReader model.InputReader, commonKwp *model.ContentWrappingParams, cliArgs *EntryPointCLIArgs, entry *BatchEntry, seenNames map[string]bool) (model.SubCommandExecution, error) {
+	if !batchEntryNameExpr.MatchString(entry.Name) {
+		return nil, errors.New("entry name must be a valid Terraform identifier")
+	}
+	if seenNames[entry.Name] {
+		return nil, errors.New("entry name is not unique")
+	}
+	seenNames[entry.Name] = true
+
+	if !isKnownCommandGroup(entry.Group) {
+		return nil, fmt.Errorf("unknown command group: %s", entry.Group)
+	}
+	if len(entry.Command) == 0 || entry.Command == "help" {
+		return nil, errors.New("entry must name a command")
+	}
+
+	if entry.Input == nil {
+		return nil, errors.New("entry must specify input")
+	}
+	for _, src := range []*BatchInputSource{entry.Input, entry.SecondaryInput, entry.Password} {
+		if src != nil {
+			if loadErr := src.load(); loadErr != nil {
+				return nil, loadErr
+			}
+		}
+	}
+
+	entryArgs := *cliArgs
+	if applyErr := entry.Protection.applyTo(&entryArgs); applyErr != nil {
+		return nil, applyErr
+	}
+
+	kwp, kwpErr := buildContentWrappingParams(inputReader, &entryArgs)
+	if kwpErr != nil {
+		return nil, kwpErr
+	}
+	// The public key is shared by all entries; it is read only once.
+	kwp.LoadRsaPublicKey = commonKwp.LoadRsaPublicKey
+	kwp.ResourceBlockName = entry.Name
+
+	return makeGenerator(kwp, entry.Group, entry.Command, entry.commandArgs())
+}
@@ -0,0 +1,131 @@
+package tfgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const unitTestBatchManifest = `
+entries:
+  - name: app_content
+    group: general
+    command: content
+    input:
+      env: UNIT_TEST_BATCH_CONTENT
+  - name: app_secret
+    group: kv
+    command: secret
+    input:
+      file: %SECRET_FILE%
+    destination:
+      destination-vault: unit-test-vault
+      destination-secret-name: unit-test-secret
+    protection:
+      num_uses: 3
+`
+
+func givenBatchManifest(t *testing.T, manifest string) string {
+	dir := t.TempDir()
+
+	secretFile := filepath.Join(dir, "secret.txt")
+	assert.NoError(t, os.WriteFile(secretFile, []byte("secret-from-file"), 0600))
+	t.Setenv("UNIT_TEST_BATCH_CONTENT", "content-from-env")
+
+	manifestFile := filepath.Join(dir, "manifest.yaml")
+	assert.NoError(t, os.WriteFile(manifestFile, []byte(strings.ReplaceAll(manifest, "%SECRET_FILE%", secretFile)), 0600))
+
+	return manifestFile
+}
+
+func Test_Batch_ProducesCombinedOutput(t *testing.T) {
+	_, mock := givenSetup(t)
+	manifestFile := givenBatchManifest(t, unitTestBatchManifest)
+
+	_, tfCode, _, err := MainEntryPointDispatch(mock.ReadInput, BatchCommand, ManifestCliOption.Opt(), manifestFile)
+	assert.NoError(t, err)
+
+	assert.Contains(t, tfCode.String(), `data "az-confidential_general_content" "app_content"`)
+	assert.Contains(t, tfCode.String(), `resource "az-confidential_keyvault_secret" "app_secret"`)
+	assert.Contains(t, tfCode.String(), "unit-test-vault")
+}
+
+func Test_Batch_WritesFilePerEntry(t *testing.T) {
+	_, mock := givenSetup(t)
+	manifestFile := givenBatchManifest(t, unitTestBatchManifest)
+	outDir := filepath.Join(t.TempDir(), "out")
+
+	_, _, _, err := MainEntryPointDispatch(mock.ReadInput, BatchCommand, ManifestCliOption.Opt(), manifestFile, OutputDirCliOption.Opt(), outDir)
+	assert.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(outDir, "app_content.tf"))
+	assert.FileExists(t, filepath.Join(outDir, "app_secret.tf"))
+}
+
+func Test_Batch_InvalidEntryFailsEntireBatch(t *testing.T) {
+	_, mock := givenSetup(t)
+	manifestFile := givenBatchManifest(t, unitTestBatchManifest+`
+  - name: app_missing
+    group: general
+    command: content
+    input:
+      env: UNIT_TEST_BATCH_UNDEFINED_VARIABLE
+`)
+	outDir := filepath.Join(t.TempDir(), "out")
+
+	_, _, _, err := MainEntryPointDispatch(mock.ReadInput, BatchCommand, ManifestCliOption.Opt(), manifestFile, OutputDirCliOption.Opt(), outDir)
+	assert.ErrorContains(t, err, "app_missing")
+	assert.NoDirExists(t, outDir)
+}
+
+func Test_Batch_RejectsDuplicateNames(t *testing.T) {
+	_, mock := givenSetup(t)
+	manifestFile := givenBatchManifest(t, unitTestBatchManifest+`
+  - name: app_content
+    group: general
+    command: content
+    input:
+      env: UNIT_TEST_BATCH_CONTENT
+`)
+
+	_, _, _, err := MainEntryPointDispatch(mock.ReadInput, BatchCommand, ManifestCliOption.Opt(), manifestFile)
+	assert.ErrorContains(t, err, "not unique")
+}
+
+func Test_Batch_RefusesToOverwriteExistingFiles(t *testing.T) {
+	_, mock := givenSetup(t)
+	manifestFile := givenBatchManifest(t, unitTestBatchManifest)
+	outDir := t.TempDir()
+
+	existingFile := filepath.Join(outDir, "app_secret.tf")
+	assert.NoError(t, os.WriteFile(existingFile, []byte("# existing"), 0644))
+
+	_, _, _, err := MainEntryPointDispatch(mock.ReadInput, BatchCommand, ManifestCliOption.Opt(), manifestFile, OutputDirCliOption.Opt(), outDir)
+	assert.ErrorContains(t, err, "refusing to overwrite")
+
+	// No file of the batch is written, and the existing file is kept as-is.
+	assert.NoFileExists(t, filepath.Join(outDir, "app_content.tf"))
+	data, readErr := os.ReadFile(existingFile)
+	assert.NoError(t, readErr)
+	assert.Equal(t, "# existing", string(data))
+}
+
+func Test_Batch_ReportsInvalidEntryArguments(t *testing.T) {
+	_, mock := givenSetup(t)
+	manifestFile := givenBatchManifest(t, unitTestBatchManifest+`
+  - name: app_bad_args
+    group: kv
+    command: secret
+    input:
+      env: UNIT_TEST_BATCH_CONTENT
+    args:
+      - -no-such-option
+`)
+
+	_, _, _, err := MainEntryPointDispatch(mock.ReadInput, BatchCommand, ManifestCliOption.Opt(), manifestFile)
+	assert.ErrorContains(t, err, "app_bad_args")
+	assert.ErrorContains(t, err, "no-such-option")
+}
@@ -23,6 +23,14 @@ func (ap *TargetCLIParams) SpecifiesTarget() bool {
 	return len(ap.AzSubscriptionId) > 0 && len(ap.ResourceGroupName) > 0 && len(ap.ServiceName) > 0
 }
 
+func (ap *TargetCLIParams) DestinationDescription() map[string]string {
+	return map[string]string{
+		"az_subscription_id":  ap.AzSubscriptionId,
+		"resource_group":      ap.ResourceGroupName,
+		"api_management_name": ap.ServiceName,
+	}
+}
+
 type NamedValueCLIParams struct {
 	TargetCLIParams
 	inputFile       string
@@ -35,11 +43,17 @@ func (ap *NamedValueCLIParams) SpecifiesTarget() bool {
 	return len(ap.namedValueName) > 0 && ap.TargetCLIParams.SpecifiesTarget()
 }
 
+func (ap *NamedValueCLIParams) DestinationDescription() map[string]string {
+	rv := ap.TargetCLIParams.DestinationDescription()
+	rv["name"] = ap.namedValueName
+	return rv
+}
+
 func CreateNamedValuedArgParser() (*NamedValueCLIParams, *flag.FlagSet) {
 	var nvParms NamedValueCLIParams
 
 	var nvCmd = flag.NewFlagSet("named_"+
-		"value", flag.ExitOnError)
+		"value", flag.ContinueOnError)
 
 	nvCmd.StringVar(&nvParms.inputFile,
 		"named-value-file",
@@ -163,6 +177,8 @@ func MakeNamedValueGenerator(kwp *model.ContentWrappingParams, args []string) (m
 		),
 	}
 
+	kwp.DescribeResource("az-confidential_apim_named_value."+mdl.TFBlockName, namedValueParams.DestinationDescription())
+
 	return func(inputReader model.InputReader) (model.TerraformCode, core.EncryptedMessage, error) {
 
 		namedValue, readErr := inputReader(NamedValueContentPrompt,
@@ -212,5 +228,6 @@ func makeNamedValueEncryptedMessage(mdl NamedValueTerraformCodeModel, kwp *model
 	}
 
 	em, md, emErr := res_apim.CreateNamedValueEncryptedMessage(namedValueDataAsStr, lockCoord, kwp.SecondaryProtectionParameters, publicKey)
+	kwp.DescribeCiphertext(res_apim.NamedValueObjectType, md)
 	return em, md, emErr
 }
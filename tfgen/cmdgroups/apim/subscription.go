@@ -29,6 +29,15 @@ func (ap *SubscriptionCLIParams) SpecifiesTarget() bool {
 	return ap.TargetCLIParams.SpecifiesTarget()
 }
 
+func (ap *SubscriptionCLIParams) DestinationDescription() map[string]string {
+	rv := ap.TargetCLIParams.DestinationDescription()
+	rv["subscription_id"] = ap.subscriptionId
+	rv["product_id"] = ap.productScope
+	rv["api_id"] = ap.apiScope
+	rv["user_id"] = ap.owner
+	return rv
+}
+
 type DestinationSubscriptionModel struct {
 	BaseCoordinateModel
 	SubscriptionId model.TerraformFieldExpression[string]
@@ -74,7 +83,7 @@ func NewSubscriptionCoordinateModel(azSubscriptionId, resourceGroupName, service
 func CreateSubscriptionArgParser() (*SubscriptionCLIParams, *flag.FlagSet) {
 	var nvParms SubscriptionCLIParams
 
-	var nvCmd = flag.NewFlagSet("subscription", flag.ExitOnError)
+	var nvCmd = flag.NewFlagSet("subscription", flag.ContinueOnError)
 
 	nvCmd.StringVar(&nvParms.primaryKeyFile,
 		"primary-key-file",
@@ -164,6 +173,8 @@ func MakeSubscriptionGenerator(kwp *model.ContentWrappingParams, args []string)
 		),
 	}
 
+	kwp.DescribeResource("az-confidential_apim_subscription."+mdl.TFBlockName, subscriptionParam.DestinationDescription())
+
 	return func(inputReader model.InputReader) (model.TerraformCode, core.EncryptedMessage, error) {
 
 		primaryKey, readErr := inputReader(SubscriptionPrimaryKeyPrompt,
@@ -236,5 +247,6 @@ func makeSubscriptionEncryptedMessage(mdl SubscriptionTerraformCodeModel, kwp *m
 	}
 
 	em, md, emErr := res_apim.CreateSubscriptionEncryptedMessage(fp, lockCoord, kwp.SecondaryProtectionParameters, publicKey)
+	kwp.DescribeCiphertext(res_apim.SubscriptionObjectType, md)
 	return em, md, emErr
 }
@@ -35,7 +35,7 @@ func CreatePasswordArgParser() (*ContentCLIParams, *flag.FlagSet) {
 }
 
 func MakeContentGenerator(kwp *model.ContentWrappingParams, args []string) (model.SubCommandExecution, error) {
-	if kwp.LockPlacement {
+	if kwp.LockPlacement || kwp.HasPlacementConstraints() {
 		return nil, errors.New("lock placement constraints are not possible for content; content is unpacked into Terraform state. Use provider limits instead")
 	}
 
@@ -46,10 +46,12 @@ func MakeContentGenerator(kwp *model.ContentWrappingParams, args []string) (mode
 	}
 
 	mdl := model.BaseTerraformCodeModel{
-		TFBlockName:           "content",
+		TFBlockName:           kwp.GetResourceBlockName("content"),
 		WrappingKeyCoordinate: kwp.WrappingKeyCoordinate,
 	}
 
+	kwp.DescribeResource("data.az-confidential_general_content."+mdl.TFBlockName, nil)
+
 	return func(inputReader model.InputReader) (model.TerraformCode, core.EncryptedMessage, error) {
 		contentBytes, readErr := inputReader(ContentPrompt,
 			contentParams.inputFile,
@@ -93,5 +95,6 @@ func makeContentEncryptedMessage(kwp *model.ContentWrappingParams, content strin
 	params.CreateLimit = 0
 	// Content does not have a limit to create.
 	em, emErr := general.CreateContentEncryptedMessage(content, params, rsaKey)
+	kwp.DescribeCiphertext(general.ContentObjectType, params)
 	return em, emErr
 }
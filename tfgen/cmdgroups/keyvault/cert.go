@@ -27,7 +27,7 @@ const (
 func CreateCertArgsParser() (*CertTFGenParams, *flag.FlagSet) {
 	var certParams = CertTFGenParams{}
 
-	var certCmd = flag.NewFlagSet("cert", flag.ExitOnError)
+	var certCmd = flag.NewFlagSet("cert", flag.ContinueOnError)
 
 	certCmd.StringVar(&certParams.inputFile,
 		"cert-file",
@@ -75,7 +75,7 @@ func MakeCertGenerator(kwp *model.ContentWrappingParams, args ...string) (model.
 
 	mdl := TerraformCodeModel{
 		BaseTerraformCodeModel: model.BaseTerraformCodeModel{
-			TFBlockName:           "cert",
+			TFBlockName:           kwp.GetResourceBlockName("cert"),
 			WrappingKeyCoordinate: kwp.WrappingKeyCoordinate,
 		},
 
@@ -89,6 +89,8 @@ func MakeCertGenerator(kwp *model.ContentWrappingParams, args ...string) (model.
 		NotAfterExample:  model.NotAfterExample(),
 	}
 
+	kwp.DescribeResource("az-confidential_keyvault_certificate."+mdl.TFBlockName, certParams.DestinationDescription())
+
 	return func(inputReader model.InputReader) (model.TerraformCode, core.EncryptedMessage, error) {
 		certData, certDataErr := AcquireCertificateData(certParams, inputReader)
 		if certDataErr != nil {
@@ -164,5 +166,6 @@ func makeCertificateEncryptedMessage(mdl TerraformCodeModel, kwp *model.ContentW
 	}
 
 	em, md, emErr := keyvault.CreateCertificateEncryptedMessage(data, lockCoord, kwp.SecondaryProtectionParameters, rsaKey)
+	kwp.DescribeCiphertext(keyvault.CertificateObjectType, md)
 	return em, md, emErr
 }
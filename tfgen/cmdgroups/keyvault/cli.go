@@ -10,3 +10,10 @@ type KeyVaultGroupCLIParams struct {
 func (mdl *KeyVaultGroupCLIParams) SpecifiesVault() bool {
 	return len(mdl.vaultName) > 0 && len(mdl.vaultObjectName) > 0
 }
+
+func (mdl *KeyVaultGroupCLIParams) DestinationDescription() map[string]string {
+	return map[string]string{
+		"vault_name": mdl.vaultName,
+		"name":       mdl.vaultObjectName,
+	}
+}
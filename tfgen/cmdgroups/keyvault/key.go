@@ -26,7 +26,7 @@ type KeyTFGenParams struct {
 func CreateKeyArgsParser() (*KeyTFGenParams, *flag.FlagSet) {
 	keyParams := &KeyTFGenParams{}
 
-	keyCmd := flag.NewFlagSet("key", flag.ExitOnError)
+	keyCmd := flag.NewFlagSet("key", flag.ContinueOnError)
 
 	keyCmd.StringVar(&keyParams.inputFile,
 		"key-file",
@@ -75,7 +75,7 @@ func MakeKeyGenerator(kwp *model.ContentWrappingParams, args ...string) (model.S
 	mdl := KeyResourceTerraformModel{
 		TerraformCodeModel: TerraformCodeModel{
 			BaseTerraformCodeModel: model.BaseTerraformCodeModel{
-				TFBlockName:           "key",
+				TFBlockName:           kwp.GetResourceBlockName("key"),
 				WrappingKeyCoordinate: kwp.WrappingKeyCoordinate,
 			},
 
@@ -91,6 +91,8 @@ func MakeKeyGenerator(kwp *model.ContentWrappingParams, args ...string) (model.S
 		KeyOperations: nil,
 	}
 
+	kwp.DescribeResource("az-confidential_keyvault_key."+mdl.TFBlockName, keyParams.DestinationDescription())
+
 	return func(inputReader model.InputReader) (model.TerraformCode, core.EncryptedMessage, error) {
 		jwkKey, acquireErr := AcquireKey(keyParams, inputReader)
 		if acquireErr != nil {
@@ -228,5 +230,6 @@ func makeKeyEncryptedMessage(mdl KeyResourceTerraformModel, kwp *model.ContentWr
 	}
 
 	em, md, emErr := keyvault.CreateKeyEncryptedMessage(jwkKey, lockCoord, kwp.SecondaryProtectionParameters, rsaKey)
+	kwp.DescribeCiphertext(keyvault.KeyObjectType, md)
 	return em, md, emErr
 }
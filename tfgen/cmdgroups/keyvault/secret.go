@@ -16,7 +16,7 @@ var secretTFTemplate string
 func CreateSecretArgParser() (*KeyVaultGroupCLIParams, *flag.FlagSet) {
 	var secretParams = KeyVaultGroupCLIParams{}
 
-	var secretCmd = flag.NewFlagSet("secret", flag.ExitOnError)
+	var secretCmd = flag.NewFlagSet("secret", flag.ContinueOnError)
 
 	secretCmd.StringVar(&secretParams.inputFile,
 		"secret-file",
@@ -57,7 +57,7 @@ func MakeSecretGenerator(kwp *model.ContentWrappingParams, args []string) (model
 
 	mdl := TerraformCodeModel{
 		BaseTerraformCodeModel: model.BaseTerraformCodeModel{
-			TFBlockName:           "secret",
+			TFBlockName:           kwp.GetResourceBlockName("secret"),
 			WrappingKeyCoordinate: kwp.WrappingKeyCoordinate,
 		},
 
@@ -71,6 +71,8 @@ func MakeSecretGenerator(kwp *model.ContentWrappingParams, args []string) (model
 		DestinationCoordinate: NewObjectCoordinateModel(secretParams.vaultName, secretParams.vaultObjectName),
 	}
 
+	kwp.DescribeResource("az-confidential_keyvault_secret."+mdl.TFBlockName, secretParams.DestinationDescription())
+
 	return func(inputReader model.InputReader) (model.TerraformCode, core.EncryptedMessage, error) {
 		secretData, readErr := inputReader(SecretContentPrompt,
 			secretParams.inputFile,
@@ -117,5 +119,6 @@ func makeSecretEncryptedMessage(mdl TerraformCodeModel, kwp *model.ContentWrappi
 	}
 
 	em, md, emErr := keyvault.CreateSecretEncryptedMessage(secretDataAsStr, lockCoord, kwp.SecondaryProtectionParameters, rsaKey)
+	kwp.DescribeCiphertext(keyvault.SecretObjectType, md)
 	return em, md, emErr
 }
@@ -0,0 +1,458 @@
+package tfgen
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/model"
+)
+
+const InspectCommand = "inspect"
+
+const (
+	CiphertextPrompt                = "Please provide ciphertext to inspect"
+	InspectPrivateKeyPrompt         = "Please provide private key of the key wrapping key"
+	InspectPrivateKeyPasswordPrompt = "Please provide password of the private key of the key wrapping key"
+)
+
+const (
+	CiphertextFileCliOption     model.CLIOption = "ciphertext"
+	PrivateKeyCliOption         model.CLIOption = "private-key"
+	PrivateKeyPasswordCliOption model.CLIOption = "private-key-password-file"
+	FormatCliOption             model.CLIOption = "format"
+	ShowPlaintextCliOption      model.CLIOption = "show-plaintext"
+	ExpiryWarningDaysCliOption  model.CLIOption = "expiry-warning-days"
+)
+
+const (
+	InspectFormatText = "text"
+	InspectFormatJson = "json"
+)
+
+type InspectCLIArgs struct {
+	CiphertextFile         string
+	PrivateKeyFile         string
+	PrivateKeyPasswordFile string
+	Format                 string
+	ShowPlaintext          bool
+	ExpiryWarningDays      int64
+
+	// Paths are the .tf files, or directories containing these, to be scanned for ciphertexts
+	Paths []string
+}
+
+func CreateInspectArgParser() (*InspectCLIArgs, *flag.FlagSet) {
+	rv := &InspectCLIArgs{}
+
+	inspectCmd := flag.NewFlagSet(InspectCommand, flag.ExitOnError)
+	inspectCmd.StringVar(&rv.CiphertextFile,
+		CiphertextFileCliOption.String(),
+		"",
+		"File containing Base64-encoded ciphertext. Ciphertext is read from the standard input where neither this option nor .tf files are given")
+
+	inspectCmd.StringVar(&rv.PrivateKeyFile,
+		PrivateKeyCliOption.String(),
+		"",
+		"Private key of the key wrapping key. Without the private key, only unverified ciphertext headers are printed")
+
+	inspectCmd.StringVar(&rv.PrivateKeyPasswordFile,
+		PrivateKeyPasswordCliOption.String(),
+		"",
+		"File containing password of the private key, if the private key is encrypted")
+
+	inspectCmd.StringVar(&rv.Format,
+		FormatCliOption.String(),
+		InspectFormatText,
+		"Output format: text or json")
+
+	inspectCmd.BoolVar(&rv.ShowPlaintext,
+		ShowPlaintextCliOption.String(),
+		false,
+		"Print the decrypted confidential data. Requires the private key")
+
+	inspectCmd.Int64Var(&rv.ExpiryWarningDays,
+		ExpiryWarningDaysCliOption.String(),
+		core.DefaultExpiryWarningDays,
+		"Warn where the ciphertext expires within this number of days; 0 disables the warning")
+
+	return rv, inspectCmd
+}
+
+// CiphertextInspection the description of the ciphertext produced by the inspect command.
+type CiphertextInspection struct {
+	Source  string `json:"source"`
+	Address string `json:"address,omitempty"`
+
+	// Verified is set where the header was obtained by decrypting the ciphertext. Otherwise,
+	// the header is read from the unauthenticated PEM headers.
+	Verified bool `json:"verified"`
+
+	Type                 string   `json:"type,omitempty"`
+	Uuid                 string   `json:"uuid,omitempty"`
+	ModelReference       string   `json:"model_reference,omitempty"`
+	ProviderConstraints  []string `json:"provider_constraints,omitempty"`
+	PlacementConstraints []string `json:"placement_constraints,omitempty"`
+	CreateLimit          string   `json:"create_limit,omitempty"`
+	NotBefore            string   `json:"not_before,omitempty"`
+	Expiry               string   `json:"expiry,omitempty"`
+	NumUses              int      `json:"num_uses"`
+
+	Warnings  []string        `json:"warnings,omitempty"`
+	Plaintext json.RawMessage `json:"plaintext,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+func (c *CiphertextInspection) setHeader(header core.ConfidentialDataJsonHeader, now time.Time, warningWindow time.Duration) {
+	c.Type = header.Type
+	c.Uuid = header.Uuid
+	c.ModelReference = header.ModelReference
+	c.NumUses = header.NumUses
+
+	c.ProviderConstraints = core.MapSlice(func(v core.ProviderConstraint) string { return string(v) }, header.ProviderConstraints)
+	c.PlacementConstraints = core.MapSlice(func(v core.PlacementConstraint) string { return string(v) }, header.PlacementConstraints)
+
+	if header.CreateLimit > 0 {
+		c.CreateLimit = time.Unix(header.CreateLimit, 0).UTC().Format(time.RFC3339)
+	}
+	if header.NotBefore > 0 {
+		c.NotBefore = time.Unix(header.NotBefore, 0).UTC().Format(time.RFC3339)
+	}
+	if header.Expiry > 0 {
+		c.Expiry = time.Unix(header.Expiry, 0).UTC().Format(time.RFC3339)
+	}
+
+	c.Warnings = InspectionWarningsOf(header, now, warningWindow)
+}
+
+// InspectionWarningsOf lists the reasons why the ciphertext cannot be used, is about to become unusable,
+// or is weakly protected. The warning window has the same meaning as the provider's expiry_warning_days.
+func InspectionWarningsOf(header core.ConfidentialDataJsonHeader, now time.Time, warningWindow time.Duration) []string {
+	var rv []string
+
+	params := core.SecondaryProtectionParameters{
+		ProviderConstraints:  header.ProviderConstraints,
+		PlacementConstraints: header.PlacementConstraints,
+		CreateLimit:          header.CreateLimit,
+		NotBefore:            header.NotBefore,
+		Expiry:               header.Expiry,
+		NumUses:              header.NumUses,
+	}
+
+	if params.LimitsExpiry() && now.Unix() > params.Expiry {
+		rv = append(rv, "ciphertext has expired")
+	}
+	if params.LimitsCreate() && now.Unix() > params.CreateLimit {
+		rv = append(rv, "ciphertext can no longer be used to create resources")
+	}
+	if params.LimitsActivation() && now.Unix() < params.NotBefore {
+		rv = append(rv, fmt.Sprintf("ciphertext is not yet active: it cannot be used before %s", time.Unix(params.NotBefore, 0).UTC().Format(time.RFC3339)))
+	}
+	rv = append(rv, core.ExpiryWarningsOf(header, now, warningWindow)...)
+
+	if params.IsWeaklyProtected() {
+		rv = append(rv, "ciphertext is weakly protected: it sets no constraints, expiry, or usage limits")
+	} else {
+		if !params.LimitsExpiry() {
+			rv = append(rv, "ciphertext never expires")
+		}
+		if !params.LimitsUsage() {
+			rv = append(rv, "ciphertext can be used unlimited number of times")
+		}
+		if !params.HasProviderConstraints() && !params.HasPlacementConstraints() {
+			rv = append(rv, "ciphertext does not constrain either provider or placement")
+		}
+	}
+
+	return rv
+}
+
+// ScannedCiphertext the ciphertext found in Terraform code.
+type ScannedCiphertext struct {
+	Source     string
+	Address    string
+	Ciphertext string
+}
+
+var tfBlockExpr = regexp.MustCompile(`^\s*(resource|data|output|locals|variable)\s*("([^"]+)")?\s*("([^"]+)")?`)
+var tfHeredocContentExpr = regexp.MustCompile(`^\s*content\s*=\s*<<-?\s*([A-Za-z_]+)\s*$`)
+var tfQuotedContentExpr = regexp.MustCompile(`^\s*content\s*=\s*"([^"]+)"`)
+
+// ScanTerraformCode finds the `content` attributes in the Terraform code, together with the address
+// of the block these appear in.
+func ScanTerraformCode(source string, code []byte) []ScannedCiphertext {
+	var rv []ScannedCiphertext
+
+	address := ""
+	heredocTerminator := ""
+	heredoc := strings.Builder{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(code))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if len(heredocTerminator) > 0 {
+			if strings.TrimSpace(line) == heredocTerminator {
+				rv = append(rv, ScannedCiphertext{Source: source, Address: address, Ciphertext: heredoc.String()})
+				heredocTerminator = ""
+				heredoc.Reset()
+			} else {
+				heredoc.WriteString(strings.TrimSpace(line))
+			}
+			continue
+		}
+
+		if m := tfBlockExpr.FindStringSubmatch(line); m != nil {
+			switch m[1] {
+			case "resource":
+				address = fmt.Sprintf("%s.%s", m[3], m[5])
+			case "data":
+				address = fmt.Sprintf("data.%s.%s", m[3], m[5])
+			default:
+				address = ""
+			}
+		} else if m = tfHeredocContentExpr.FindStringSubmatch(line); m != nil {
+			heredocTerminator = m[1]
+		} else if m = tfQuotedContentExpr.FindStringSubmatch(line); m != nil {
+			rv = append(rv, ScannedCiphertext{Source: source, Address: address, Ciphertext: m[1]})
+		}
+	}
+
+	return rv
+}
+
+func scanTerraformPaths(paths []string) ([]ScannedCiphertext, error) {
+	var rv []ScannedCiphertext
+
+	for _, p := range paths {
+		walkErr := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != p && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if path != p && filepath.Ext(path) != ".tf" {
+				return nil
+			}
+
+			code, readErr := os.ReadFile(path)
+			if readErr != nil {
+				return readErr
+			}
+			rv = append(rv, ScanTerraformCode(path, code)...)
+			return nil
+		})
+
+		if walkErr != nil {
+			return nil, fmt.Errorf("cannot scan %s: %s", p, walkErr.Error())
+		}
+	}
+
+	return rv, nil
+}
+
+func loadInspectDecrypter(inputReader model.InputReader, args *InspectCLIArgs) (core.RSADecrypter, error) {
+	keyData, readErr := inputReader(InspectPrivateKeyPrompt, args.PrivateKeyFile, false, true)
+	if readErr != nil {
+		return nil, fmt.Errorf("cannot read private key: %s", readErr.Error())
+	}
+
+	var key any
+	var keyErr error
+
+	block, blockErr := core.ParseSinglePEMBlock(keyData)
+	if blockErr != nil {
+		return nil, fmt.Errorf("private key must be PEM-encoded: %s", blockErr.Error())
+	}
+
+	if core.RequiresPassword(block) {
+		password, passwordErr := inputReader(InspectPrivateKeyPasswordPrompt, args.PrivateKeyPasswordFile, false, false)
+		if passwordErr != nil {
+			return nil, fmt.Errorf("cannot read private key password: %s", passwordErr.Error())
+		}
+		key, keyErr = core.PrivateKeyFromEncryptedBlock(block, strings.TrimSpace(string(password)))
+	} else {
+		key, keyErr = core.PrivateKeyFromBlock(block)
+	}
+
+	if keyErr != nil {
+		return nil, fmt.Errorf("cannot load private key: %s", keyErr.Error())
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+
+	return func(ciphertext []byte) ([]byte, error) {
+		return core.RsaDecryptBytes(rsaKey, ciphertext, nil)
+	}, nil
+}
+
+// InspectCiphertext describes the ciphertext. Where the decrypter is not available, the description is
+// based on the unverified headers. The plaintext is only included where explicitly requested.
+func InspectCiphertext(src ScannedCiphertext, decrypter core.RSADecrypter, showPlaintext bool, now time.Time, warningWindow time.Duration) CiphertextInspection {
+	rv := CiphertextInspection{
+		Source:  src.Source,
+		Address: src.Address,
+	}
+
+	em := core.EncryptedMessage{}
+	if err := em.FromBase64PEM(src.Ciphertext); err != nil {
+		rv.Error = err.Error()
+		return rv
+	}
+
+	if decrypter == nil {
+		rv.setHeader(core.UnverifiedHeaderOf(em), now, warningWindow)
+		return rv
+	}
+
+	msg, decryptErr := core.DecryptConfidentialDataMessage(em, decrypter)
+	if decryptErr != nil {
+		rv.Error = fmt.Sprintf("cannot decrypt ciphertext: %s", decryptErr.Error())
+		rv.setHeader(core.UnverifiedHeaderOf(em), now, warningWindow)
+		return rv
+	}
+
+	rv.Verified = true
+	rv.setHeader(msg.Header, now, warningWindow)
+	if showPlaintext {
+		rv.Plaintext = msg.ConfidentialData
+	}
+
+	return rv
+}
+
+func formatInspectionText(inspections []CiphertextInspection) string {
+	buf := strings.Builder{}
+
+	for i, c := range inspections {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		buf.WriteString(fmt.Sprintf("Source:                %s\n", c.Source))
+		if len(c.Address) > 0 {
+			buf.WriteString(fmt.Sprintf("Address:               %s\n", c.Address))
+		}
+		if len(c.Error) > 0 {
+			buf.WriteString(fmt.Sprintf("Error:                 %s\n", c.Error))
+		}
+		if c.Verified {
+			buf.WriteString("Header:                verified (decrypted)\n")
+		} else {
+			buf.WriteString("Header:                UNVERIFIED (read from unauthenticated PEM headers)\n")
+		}
+
+		buf.WriteString(fmt.Sprintf("Type:                  %s\n", c.Type))
+		buf.WriteString(fmt.Sprintf("Uuid:                  %s\n", c.Uuid))
+		buf.WriteString(fmt.Sprintf("Model reference:       %s\n", c.ModelReference))
+		buf.WriteString(fmt.Sprintf("Provider constraints:  %s\n", strings.Join(c.ProviderConstraints, ", ")))
+		buf.WriteString(fmt.Sprintf("Placement constraints: %s\n", strings.Join(c.PlacementConstraints, ", ")))
+		buf.WriteString(fmt.Sprintf("Create limit:          %s\n", valueOrNone(c.CreateLimit)))
+		buf.WriteString(fmt.Sprintf("Not before:            %s\n", valueOrNone(c.NotBefore)))
+		buf.WriteString(fmt.Sprintf("Expiry:                %s\n", valueOrNone(c.Expiry)))
+		buf.WriteString(fmt.Sprintf("Number of uses:        %d\n", c.NumUses))
+
+		for _, w := range c.Warnings {
+			buf.WriteString(fmt.Sprintf("WARNING: %s\n", w))
+		}
+
+		if len(c.Plaintext) > 0 {
+			buf.WriteString(fmt.Sprintf("Plaintext:             %s\n", string(c.Plaintext)))
+		}
+	}
+
+	return buf.String()
+}
+
+func valueOrNone(v string) string {
+	if len(v) == 0 {
+		return "none"
+	}
+	return v
+}
+
+// RunInspect describes the ciphertext given either in the file, in the standard input, or in the Terraform
+// code. The output is returned as a text or a JSON document.
+func RunInspect(inputReader model.InputReader, args []string) (string, error) {
+	inspectArgs, inspectCmd := CreateInspectArgParser()
+	if parseErr := inspectCmd.Parse(args); parseErr != nil {
+		return "", parseErr
+	}
+	inspectArgs.Paths = inspectCmd.Args()
+
+	if inspectArgs.Format != InspectFormatText && inspectArgs.Format != InspectFormatJson {
+		return "", fmt.Errorf("unsupported output format: %s", inspectArgs.Format)
+	}
+	if inspectArgs.ExpiryWarningDays < 0 {
+		return "", fmt.Errorf("option %s must not be negative", ExpiryWarningDaysCliOption.Opt())
+	}
+	if inspectArgs.ShowPlaintext && len(inspectArgs.PrivateKeyFile) == 0 {
+		return "", fmt.Errorf("option %s requires option %s", ShowPlaintextCliOption.Opt(), PrivateKeyCliOption.Opt())
+	}
+
+	var sources []ScannedCiphertext
+	if len(inspectArgs.Paths) > 0 {
+		scanned, scanErr := scanTerraformPaths(inspectArgs.Paths)
+		if scanErr != nil {
+			return "", scanErr
+		}
+		sources = append(sources, scanned...)
+	}
+
+	if len(inspectArgs.CiphertextFile) > 0 || len(inspectArgs.Paths) == 0 {
+		ciphertext, readErr := inputReader(CiphertextPrompt, inspectArgs.CiphertextFile, false, true)
+		if readErr != nil {
+			return "", fmt.Errorf("cannot read ciphertext: %s", readErr.Error())
+		}
+
+		src := inspectArgs.CiphertextFile
+		if len(src) == 0 {
+			src = "input"
+		}
+		sources = append(sources, ScannedCiphertext{Source: src, Ciphertext: string(ciphertext)})
+	}
+
+	var decrypter core.RSADecrypter
+	if len(inspectArgs.PrivateKeyFile) > 0 {
+		var decrypterErr error
+		if decrypter, decrypterErr = loadInspectDecrypter(inputReader, inspectArgs); decrypterErr != nil {
+			return "", decrypterErr
+		}
+	}
+
+	now := time.Now()
+	inspections := make([]CiphertextInspection, len(sources))
+	for i, src := range sources {
+		inspections[i] = InspectCiphertext(src, decrypter, inspectArgs.ShowPlaintext, now, core.ExpiryWarningWindowOf(inspectArgs.ExpiryWarningDays))
+	}
+
+	if inspectArgs.Format == InspectFormatJson {
+		jsonBytes, jsonErr := json.MarshalIndent(inspections, "", "  ")
+		return string(jsonBytes), jsonErr
+	}
+
+	if len(inspections) == 0 {
+		return "No ciphertext found", nil
+	}
+	return formatInspectionText(inspections), nil
+}
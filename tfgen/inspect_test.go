@@ -0,0 +1,105 @@
+package tfgen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
+	res_general "github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/general"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/cmdgroups/general"
+	"github.com/stretchr/testify/assert"
+)
+
+func givenGeneratedContentCode(t *testing.T, opts ...string) string {
+	_, mock := givenSetup(t)
+	mock.GivenReadRequestReturns(general.ContentPrompt, []byte("this is a secret content"))
+
+	args := append(opts, GeneralGroup, general.ContentCommand)
+	_, tfCode, _, err := MainEntryPointDispatch(mock.ReadInput, args...)
+	assert.NoError(t, err)
+
+	tfFile := filepath.Join(t.TempDir(), "main.tf")
+	assert.NoError(t, os.WriteFile(tfFile, []byte(tfCode), 0600))
+
+	return tfFile
+}
+
+func Test_Inspect_ScansTerraformCodeWithoutPrivateKey(t *testing.T) {
+	tfFile := givenGeneratedContentCode(t, NumberOfTimesUsesOption.Opt(), "3")
+	_, mock := givenSetup(t)
+
+	report, err := RunInspect(mock.ReadInput, []string{FormatCliOption.Opt(), InspectFormatJson, tfFile})
+	assert.NoError(t, err)
+
+	var inspections []CiphertextInspection
+	assert.NoError(t, json.Unmarshal([]byte(report), &inspections))
+	assert.Equal(t, 1, len(inspections))
+	assert.Equal(t, "data.az-confidential_general_content.content", inspections[0].Address)
+	assert.False(t, inspections[0].Verified)
+	assert.Equal(t, 3, inspections[0].NumUses)
+	assert.True(t, len(inspections[0].Uuid) > 5)
+	assert.Nil(t, inspections[0].Plaintext)
+}
+
+func Test_Inspect_DecryptsWithPrivateKey(t *testing.T) {
+	tfFile := givenGeneratedContentCode(t)
+	_, mock := givenSetup(t)
+	mock.GivenReadRequestReturns(InspectPrivateKeyPrompt, testkeymaterial.EphemeralRsaKeyText)
+
+	report, err := RunInspect(mock.ReadInput, []string{PrivateKeyCliOption.Opt(), "unit-test.pem", FormatCliOption.Opt(), InspectFormatJson, tfFile})
+	assert.NoError(t, err)
+
+	var inspections []CiphertextInspection
+	assert.NoError(t, json.Unmarshal([]byte(report), &inspections))
+	assert.True(t, inspections[0].Verified)
+	assert.Equal(t, res_general.ContentObjectType, inspections[0].Type)
+	assert.NotContains(t, report, "this is a secret content")
+}
+
+func Test_Inspect_ShowsPlaintextOnlyWhenAsked(t *testing.T) {
+	tfFile := givenGeneratedContentCode(t)
+	_, mock := givenSetup(t)
+	mock.GivenReadRequestReturns(InspectPrivateKeyPrompt, testkeymaterial.EphemeralRsaKeyText)
+
+	report, err := RunInspect(mock.ReadInput, []string{PrivateKeyCliOption.Opt(), "unit-test.pem", ShowPlaintextCliOption.Opt(), tfFile})
+	assert.NoError(t, err)
+	assert.Contains(t, report, "this is a secret content")
+}
+
+func Test_Inspect_ShowPlaintextRequiresPrivateKey(t *testing.T) {
+	_, mock := givenSetup(t)
+
+	_, err := RunInspect(mock.ReadInput, []string{ShowPlaintextCliOption.Opt(), "main.tf"})
+	assert.Error(t, err)
+}
+
+func Test_InspectionWarningsOf(t *testing.T) {
+	now := time.Now()
+
+	window := core.ExpiryWarningWindowOf(core.DefaultExpiryWarningDays)
+
+	warnings := InspectionWarningsOf(core.ConfidentialDataJsonHeader{}, now, window)
+	assert.Equal(t, 1, len(warnings))
+	assert.Contains(t, warnings[0], "weakly protected")
+
+	warnings = InspectionWarningsOf(core.ConfidentialDataJsonHeader{
+		Expiry:              now.Add(-time.Hour).Unix(),
+		CreateLimit:         now.Add(-2 * time.Hour).Unix(),
+		NumUses:             1,
+		ProviderConstraints: []core.ProviderConstraint{"acceptance"},
+	}, now, window)
+	assert.Equal(t, []string{"ciphertext has expired", "ciphertext can no longer be used to create resources"}, warnings)
+
+	warnings = InspectionWarningsOf(core.ConfidentialDataJsonHeader{
+		Uuid:                "unit-test-uuid",
+		Expiry:              now.Add(10 * 24 * time.Hour).Unix(),
+		NumUses:             1,
+		ProviderConstraints: []core.ProviderConstraint{"acceptance"},
+	}, now, window)
+	assert.Equal(t, 1, len(warnings))
+	assert.Contains(t, warnings[0], "ciphertext unit-test-uuid expires on")
+}
@@ -0,0 +1,66 @@
+package tfgen
+
+import (
+	"encoding/json"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/model"
+)
+
+const (
+	OutputFormatHcl  = "hcl"
+	OutputFormatJson = "json"
+)
+
+// JsonOutputHeader the header with which tfgen encrypted the ciphertext. Timestamps are given as Unix seconds;
+// zero denotes the absence of the limit.
+type JsonOutputHeader struct {
+	Type                 string   `json:"type"`
+	ProviderConstraints  []string `json:"provider_constraints"`
+	PlacementConstraints []string `json:"placement_constraints"`
+	CreateLimit          int64    `json:"create_limit"`
+	NotBefore            int64    `json:"not_before"`
+	Expiry               int64    `json:"expiry"`
+	NumUses              int      `json:"num_uses"`
+}
+
+// JsonOutput the machine-readable output of tfgen, produced with `-output json`. Tools embedding tfgen
+// rely on the names of these fields; these must remain stable.
+type JsonOutput struct {
+	Ciphertext  string            `json:"ciphertext"`
+	Header      JsonOutputHeader  `json:"header"`
+	Address     string            `json:"address"`
+	Destination map[string]string `json:"destination"`
+	HCL         string            `json:"hcl"`
+}
+
+func NewJsonOutput(kwp *model.ContentWrappingParams, tfCode model.TerraformCode, em core.EncryptedMessage) JsonOutput {
+	header := kwp.Description.Protection
+
+	dest := kwp.Description.Destination
+	if dest == nil {
+		dest = map[string]string{}
+	}
+
+	return JsonOutput{
+		Ciphertext: em.ToBase64PEM(),
+		Header: JsonOutputHeader{
+			Type:                 kwp.Description.ObjectType,
+			ProviderConstraints:  core.MapSlice(func(v core.ProviderConstraint) string { return string(v) }, header.ProviderConstraints),
+			PlacementConstraints: core.MapSlice(func(v core.PlacementConstraint) string { return string(v) }, header.PlacementConstraints),
+			CreateLimit:          header.CreateLimit,
+			NotBefore:            header.NotBefore,
+			Expiry:               header.Expiry,
+			NumUses:              header.NumUses,
+		},
+		Address:     kwp.Description.Address,
+		Destination: dest,
+		HCL:         tfCode.String(),
+	}
+}
+
+// RenderJsonOutput renders the JSON document describing the output of the command.
+func RenderJsonOutput(kwp *model.ContentWrappingParams, tfCode model.TerraformCode, em core.EncryptedMessage) (model.TerraformCode, error) {
+	jsonBytes, err := json.MarshalIndent(NewJsonOutput(kwp, tfCode, em), "", "  ")
+	return model.TerraformCode(jsonBytes), err
+}
@@ -0,0 +1,64 @@
+package tfgen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	res_kv "github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/keyvault"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/cmdgroups/keyvault"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_JsonOutput_KV_Secret(t *testing.T) {
+	decrypter, mock := givenSetup(t)
+	mock.GivenReadRequestReturns(keyvault.SecretContentPrompt, []byte("this is a secret content"))
+
+	_, output, em, err := MainEntryPointDispatch(mock.ReadInput,
+		OutputFormatOption.Opt(), OutputFormatJson,
+		NumberOfTimesUsesOption.Opt(), "2",
+		LockDestinationCliOption.Opt(),
+		KeyVaultGroup, keyvault.SecretCommand,
+		keyvault.DestinationVaultCliOption.Opt(), "vault-name",
+		keyvault.DestinationVaultSecretCliOption.Opt(), "secret-name",
+	)
+	assert.NoError(t, err)
+
+	doc := JsonOutput{}
+	assert.NoError(t, json.Unmarshal([]byte(output), &doc))
+
+	assert.Equal(t, "az-confidential_keyvault_secret.secret", doc.Address)
+	assert.Equal(t, map[string]string{"vault_name": "vault-name", "name": "secret-name"}, doc.Destination)
+	assert.Contains(t, doc.HCL, `resource "az-confidential_keyvault_secret" "secret"`)
+	assert.Equal(t, em.ToBase64PEM(), doc.Ciphertext)
+
+	header, _, err := res_kv.DecryptSecretMessage(em, decrypter)
+	assert.NoError(t, err)
+	assert.Equal(t, header.Type, doc.Header.Type)
+	assert.Equal(t, header.Expiry, doc.Header.Expiry)
+	assert.Equal(t, header.NumUses, doc.Header.NumUses)
+	assert.Equal(t, 2, doc.Header.NumUses)
+	assert.Equal(t, []string{string(header.PlacementConstraints[0])}, doc.Header.PlacementConstraints)
+	assert.Equal(t, []string{}, doc.Header.ProviderConstraints)
+}
+
+func Test_JsonOutput_RejectsCiphertextOnly(t *testing.T) {
+	_, mock := givenSetup(t)
+
+	_, _, _, err := MainEntryPointDispatch(mock.ReadInput,
+		OutputFormatOption.Opt(), OutputFormatJson,
+		CiphertextOnlyOption.Opt(),
+		KeyVaultGroup, keyvault.SecretCommand,
+	)
+	assert.Error(t, err)
+}
+
+func Test_JsonOutput_HeaderOfEmptyMessage(t *testing.T) {
+	_, mock := givenSetup(t)
+	kwp, err := buildContentWrappingParams(mock.ReadInput, &EntryPointCLIArgs{})
+	assert.NoError(t, err)
+
+	doc := NewJsonOutput(kwp, "", core.EncryptedMessage{})
+	assert.Equal(t, map[string]string{}, doc.Destination)
+	assert.Equal(t, int64(0), doc.Header.Expiry)
+}
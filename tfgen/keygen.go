@@ -0,0 +1,189 @@
+package tfgen
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/model"
+	"github.com/youmark/pkcs8"
+)
+
+const KeygenCommand = "keygen"
+
+const (
+	KeygenPasswordPrompt = "Please provide password to encrypt the private key"
+)
+
+const (
+	KeySizeCliOption         model.CLIOption = "bits"
+	PrivateKeyOutCliOption   model.CLIOption = "private-key-file"
+	PublicKeyOutCliOption    model.CLIOption = "public-key-file"
+	JwkOutCliOption          model.CLIOption = "jwk-file"
+	EncryptKeyCliOption      model.CLIOption = "encrypt"
+	KeyPasswordFileCliOption model.CLIOption = "password-file"
+)
+
+type KeygenCLIArgs struct {
+	Bits           int
+	PrivateKeyFile string
+	PublicKeyFile  string
+	JwkFile        string
+	Encrypt        bool
+	PasswordFile   string
+}
+
+func CreateKeygenArgParser() (*KeygenCLIArgs, *flag.FlagSet) {
+	rv := &KeygenCLIArgs{}
+
+	keygenCmd := flag.NewFlagSet(KeygenCommand, flag.ExitOnError)
+	keygenCmd.IntVar(&rv.Bits,
+		KeySizeCliOption.String(),
+		4096,
+		"RSA key size: 3072 or 4096 bits")
+
+	keygenCmd.StringVar(&rv.PrivateKeyFile,
+		PrivateKeyOutCliOption.String(),
+		"",
+		"File to write the PKCS#8 PEM-encoded private key to")
+
+	keygenCmd.StringVar(&rv.PublicKeyFile,
+		PublicKeyOutCliOption.String(),
+		"",
+		"File to write the PEM-encoded public key to. This file is given to the -pubkey option")
+
+	keygenCmd.StringVar(&rv.JwkFile,
+		JwkOutCliOption.String(),
+		"",
+		"Optional file to write the private key as JSON Web Key suitable for import into Azure Key Vault")
+
+	keygenCmd.BoolVar(&rv.Encrypt,
+		EncryptKeyCliOption.String(),
+		false,
+		"Encrypt the private key with a password")
+
+	keygenCmd.StringVar(&rv.PasswordFile,
+		KeyPasswordFileCliOption.String(),
+		"",
+		"File containing the password to encrypt the private key. The password is prompted for, if not specified")
+
+	return rv, keygenCmd
+}
+
+// writeNewFile writes the file, refusing to overwrite the existing one. The incomplete file is removed.
+func writeNewFile(fn string, data []byte, perm os.FileMode) error {
+	file, openErr := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if openErr != nil {
+		return fmt.Errorf("cannot create file %s: %s", fn, openErr.Error())
+	}
+
+	_, writeErr := file.Write(data)
+	closeErr := file.Close()
+
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		// The file is created by this call; the incomplete file is not left behind.
+		_ = os.Remove(fn)
+		return fmt.Errorf("cannot write file %s: %s", fn, err.Error())
+	}
+	return nil
+}
+
+// RunKeygen generates the RSA key pair to be used as the key wrapping key.
+func RunKeygen(inputReader model.InputReader, args []string) (string, error) {
+	keygenArgs, keygenCmd := CreateKeygenArgParser()
+	if parseErr := keygenCmd.Parse(args); parseErr != nil {
+		return "", parseErr
+	}
+
+	if keygenArgs.Bits != 3072 && keygenArgs.Bits != 4096 {
+		return "", fmt.Errorf("unsupported key size %d: only 3072 and 4096 bits are supported", keygenArgs.Bits)
+	}
+	if len(keygenArgs.PrivateKeyFile) == 0 || len(keygenArgs.PublicKeyFile) == 0 {
+		return "", fmt.Errorf("options %s and %s are required", PrivateKeyOutCliOption.Opt(), PublicKeyOutCliOption.Opt())
+	}
+
+	var password []byte
+	if keygenArgs.Encrypt {
+		passwordData, passwordErr := inputReader(KeygenPasswordPrompt, keygenArgs.PasswordFile, false, false)
+		if passwordErr != nil {
+			return "", fmt.Errorf("cannot read password: %s", passwordErr.Error())
+		}
+		password = []byte(strings.TrimSpace(string(passwordData)))
+		if len(password) == 0 {
+			return "", errors.New("password must not be empty")
+		}
+	}
+
+	privateKey, genErr := rsa.GenerateKey(rand.Reader, keygenArgs.Bits)
+	if genErr != nil {
+		return "", fmt.Errorf("cannot generate key: %s", genErr.Error())
+	}
+
+	der, marshalErr := pkcs8.MarshalPrivateKey(privateKey, password, nil)
+	if marshalErr != nil {
+		return "", fmt.Errorf("cannot encode private key: %s", marshalErr.Error())
+	}
+
+	blockType := "PRIVATE KEY"
+	if len(password) > 0 {
+		blockType = "ENCRYPTED PRIVATE KEY"
+	}
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+
+	publicPEM, pubErr := core.PublicKeyToPEM(&privateKey.PublicKey)
+	if pubErr != nil {
+		return "", fmt.Errorf("cannot encode public key: %s", pubErr.Error())
+	}
+
+	thumbprint, tpErr := core.PublicKeyThumbprint(&privateKey.PublicKey)
+	if tpErr != nil {
+		return "", fmt.Errorf("cannot compute key thumbprint: %s", tpErr.Error())
+	}
+
+	var jwkJson []byte
+	if len(keygenArgs.JwkFile) > 0 {
+		azJwk := azkeys.JSONWebKey{}
+		if jwkErr := core.PrivateKeyTOJSONWebKey(privatePEM, string(password), &azJwk); jwkErr != nil {
+			return "", fmt.Errorf("cannot convert private key to JSON Web Key: %s", jwkErr.Error())
+		}
+		azJwk.KeyOps = []*azkeys.KeyOperation{to.Ptr(azkeys.KeyOperationDecrypt), to.Ptr(azkeys.KeyOperationUnwrapKey)}
+
+		var jsonErr error
+		if jwkJson, jsonErr = json.MarshalIndent(&azJwk, "", "  "); jsonErr != nil {
+			return "", fmt.Errorf("cannot marshal JSON Web Key: %s", jsonErr.Error())
+		}
+	}
+
+	// All output is prepared before any file is written.
+	if writeErr := writeNewFile(keygenArgs.PrivateKeyFile, privatePEM, 0600); writeErr != nil {
+		return "", writeErr
+	}
+	if writeErr := writeNewFile(keygenArgs.PublicKeyFile, publicPEM, 0644); writeErr != nil {
+		return "", writeErr
+	}
+	if len(jwkJson) > 0 {
+		if writeErr := writeNewFile(keygenArgs.JwkFile, jwkJson, 0600); writeErr != nil {
+			return "", writeErr
+		}
+	}
+
+	rv := strings.Builder{}
+	rv.WriteString(fmt.Sprintf("Generated %d-bit RSA key pair\n", keygenArgs.Bits))
+	rv.WriteString(fmt.Sprintf("Private key: %s\n", keygenArgs.PrivateKeyFile))
+	rv.WriteString(fmt.Sprintf("Public key:  %s\n", keygenArgs.PublicKeyFile))
+	if len(jwkJson) > 0 {
+		rv.WriteString(fmt.Sprintf("JSON Web Key: %s\n", keygenArgs.JwkFile))
+	}
+	rv.WriteString(fmt.Sprintf("Thumbprint (SHA-256, RFC 7638): %s", thumbprint))
+
+	return rv.String(), nil
+}
@@ -0,0 +1,94 @@
+package tfgen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Keygen_ProducesUsableKeyPair(t *testing.T) {
+	_, mock := givenSetup(t)
+	dir := t.TempDir()
+
+	privFile := filepath.Join(dir, "kek.pem")
+	pubFile := filepath.Join(dir, "kek.pub.pem")
+	jwkFile := filepath.Join(dir, "kek.jwk.json")
+
+	report, err := RunKeygen(mock.ReadInput, []string{
+		KeySizeCliOption.Opt(), "3072",
+		PrivateKeyOutCliOption.Opt(), privFile,
+		PublicKeyOutCliOption.Opt(), pubFile,
+		JwkOutCliOption.Opt(), jwkFile,
+	})
+	assert.NoError(t, err)
+
+	pubKey, pubErr := core.LoadPublicKey(pubFile)
+	assert.NoError(t, pubErr)
+	assert.Equal(t, 3072, pubKey.N.BitLen())
+
+	thumbprint, _ := core.PublicKeyThumbprint(pubKey)
+	assert.Contains(t, report, thumbprint)
+
+	privData, _ := os.ReadFile(privFile)
+	_, privErr := core.PrivateKeyFromData(privData)
+	assert.NoError(t, privErr)
+
+	jwkData, _ := os.ReadFile(jwkFile)
+	azJwk := azkeys.JSONWebKey{}
+	assert.NoError(t, json.Unmarshal(jwkData, &azJwk))
+	assert.Equal(t, azkeys.KeyTypeRSA, *azJwk.Kty)
+	assert.Equal(t, pubKey.N.Bytes(), azJwk.N)
+}
+
+func Test_Keygen_EncryptsPrivateKey(t *testing.T) {
+	_, mock := givenSetup(t)
+	mock.GivenReadRequestReturnsString(KeygenPasswordPrompt, "s3cr3t")
+	dir := t.TempDir()
+
+	privFile := filepath.Join(dir, "kek.pem")
+	_, err := RunKeygen(mock.ReadInput, []string{
+		KeySizeCliOption.Opt(), "3072",
+		PrivateKeyOutCliOption.Opt(), privFile,
+		PublicKeyOutCliOption.Opt(), filepath.Join(dir, "kek.pub.pem"),
+		EncryptKeyCliOption.Opt(),
+	})
+	assert.NoError(t, err)
+
+	privData, _ := os.ReadFile(privFile)
+	block, blockErr := core.ParseSinglePEMBlock(privData)
+	assert.NoError(t, blockErr)
+	assert.True(t, core.RequiresPassword(block))
+
+	_, keyErr := core.PrivateKeyFromEncryptedBlock(block, "s3cr3t")
+	assert.NoError(t, keyErr)
+}
+
+func Test_Keygen_RefusesToOverwrite(t *testing.T) {
+	_, mock := givenSetup(t)
+	dir := t.TempDir()
+
+	privFile := filepath.Join(dir, "kek.pem")
+	assert.NoError(t, os.WriteFile(privFile, []byte("existing"), 0600))
+
+	_, err := RunKeygen(mock.ReadInput, []string{
+		KeySizeCliOption.Opt(), "3072",
+		PrivateKeyOutCliOption.Opt(), privFile,
+		PublicKeyOutCliOption.Opt(), filepath.Join(dir, "kek.pub.pem"),
+	})
+	assert.Error(t, err)
+
+	data, _ := os.ReadFile(privFile)
+	assert.Equal(t, "existing", string(data))
+}
+
+func Test_Keygen_RejectsWeakKeySize(t *testing.T) {
+	_, mock := givenSetup(t)
+
+	_, err := RunKeygen(mock.ReadInput, []string{KeySizeCliOption.Opt(), "2048"})
+	assert.ErrorContains(t, err, "unsupported key size")
+}
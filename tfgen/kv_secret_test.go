@@ -63,6 +63,73 @@ func Test_KV_Secret_CustomTimingLimits(t *testing.T) {
 	)
 }
 
+func Test_KV_Secret_ProviderConstraintGroups(t *testing.T) {
+	executeKvSecretEncryptionCycle(t,
+		[]string{
+			ProviderConstraintsCliOption.Opt(), "legacy",
+			ProviderAllOfCliOption.Opt(), "prod,weu",
+			ProviderAnyOfCliOption.Opt(), "a,b",
+			ProviderNoneOfCliOption.Opt(), "sandbox",
+		},
+		[]string{}, // op command options,
+
+		func(t *testing.T, header core.ConfidentialDataJsonHeader) {
+			assert.Equal(t, []core.ProviderConstraint{
+				"legacy",
+				"all-of:prod|weu",
+				"any-of:a|b",
+				"none-of:sandbox",
+			}, header.ProviderConstraints)
+		},
+	)
+}
+
+func Test_KV_Secret_PlacementPatterns(t *testing.T) {
+	executeKvSecretEncryptionCycle(t,
+		[]string{
+			PlacementGlobCliOption.Opt(), "az-c-keyvault://kv-prod-*@secrets=app-*",
+			PlacementRegexCliOption.Opt(), "az-c-keyvault://kv-dr-[0-9]+@secrets=app-.*",
+		},
+		[]string{}, // op command options,
+
+		func(t *testing.T, header core.ConfidentialDataJsonHeader) {
+			assert.Equal(t, []core.PlacementConstraint{
+				"glob:az-c-keyvault://kv-prod-*@secrets=app-*",
+				"regex:az-c-keyvault://kv-dr-[0-9]+@secrets=app-.*",
+			}, header.PlacementConstraints)
+		},
+	)
+}
+
+func Test_KV_Secret_AzureBindings(t *testing.T) {
+	executeKvSecretEncryptionCycle(t,
+		[]string{
+			AzureTenantsCliOption.Opt(), "11111111-1111-1111-1111-111111111111",
+			AzureSubscriptionsCliOption.Opt(), "33333333-3333-3333-3333-333333333333,44444444-4444-4444-4444-444444444444",
+		},
+		[]string{}, // op command options,
+
+		func(t *testing.T, header core.ConfidentialDataJsonHeader) {
+			assert.Equal(t, []core.ProviderConstraint{
+				"azure-tenant:11111111-1111-1111-1111-111111111111",
+				"azure-subscription:33333333-3333-3333-3333-333333333333",
+				"azure-subscription:44444444-4444-4444-4444-444444444444",
+			}, header.ProviderConstraints)
+		},
+	)
+}
+
+func Test_KV_Secret_NotBefore(t *testing.T) {
+	executeKvSecretEncryptionCycle(t,
+		[]string{NotBeforeCliOption.Opt(), "2030-01-31T10:00:00Z"},
+		[]string{}, // op command options,
+
+		func(t *testing.T, header core.ConfidentialDataJsonHeader) {
+			assert.Equal(t, time.Date(2030, 1, 31, 10, 0, 0, 0, time.UTC).Unix(), header.NotBefore)
+		},
+	)
+}
+
 func Test_KV_Secret_CreateOnce(t *testing.T) {
 	now := time.Now().Unix()
 
@@ -0,0 +1,410 @@
+package tfgen
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core"
+	res_apim "github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/apim"
+	res_keyvault "github.com/aliakseiyanchuk/terraform-provider-az-confidential/resources/keyvault"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/cmdgroups/apim"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/cmdgroups/keyvault"
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/model"
+)
+
+const MigrateCommand = "migrate"
+
+const (
+	StateCliOption model.CLIOption = "state"
+)
+
+const AzureRMKeyVaultCertificateTypeName = "azurerm_key_vault_certificate"
+
+// terraformShowModule a module of the `terraform show -json` output
+type terraformShowModule struct {
+	Resources []struct {
+		Address string                 `json:"address"`
+		Mode    string                 `json:"mode"`
+		Type    string                 `json:"type"`
+		Values  map[string]interface{} `json:"values"`
+	} `json:"resources"`
+	ChildModules []terraformShowModule `json:"child_modules"`
+}
+
+// terraformStateDocument either the `terraform show -json` output, or the state itself (as given by
+// `terraform state pull`)
+type terraformStateDocument struct {
+	Values *struct {
+		RootModule terraformShowModule `json:"root_module"`
+	} `json:"values"`
+
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   interface{}            `json:"index_key"`
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// MigratedResource an azurerm resource instance found in the state
+type MigratedResource struct {
+	Address string
+	Type    string
+	Values  map[string]interface{}
+}
+
+func (r MigratedResource) stringValue(attr string) string {
+	if v, ok := r.Values[attr].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// firstBlock returns the first element of the nested block, which the state keeps as a list
+func (r MigratedResource) firstBlock(attr string) map[string]interface{} {
+	if l, ok := r.Values[attr].([]interface{}); ok && len(l) > 0 {
+		if m, ok := l[0].(map[string]interface{}); ok {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s terraformShowModule) collect(rv []MigratedResource) []MigratedResource {
+	for _, r := range s.Resources {
+		if r.Mode == "managed" {
+			rv = append(rv, MigratedResource{Address: r.Address, Type: r.Type, Values: r.Values})
+		}
+	}
+	for _, child := range s.ChildModules {
+		rv = child.collect(rv)
+	}
+	return rv
+}
+
+// ParseMigratedResources reads the managed resources from the output of `terraform show -json` or from the
+// state JSON.
+func ParseMigratedResources(data []byte) ([]MigratedResource, error) {
+	doc := terraformStateDocument{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("cannot parse state: %s", err.Error())
+	}
+
+	if doc.Values != nil {
+		return doc.Values.RootModule.collect(nil), nil
+	}
+
+	var rv []MigratedResource
+	for _, r := range doc.Resources {
+		if r.Mode != "managed" {
+			continue
+		}
+
+		prefix := r.Type + "." + r.Name
+		if len(r.Module) > 0 {
+			prefix = r.Module + "." + prefix
+		}
+
+		for _, inst := range r.Instances {
+			rv = append(rv, MigratedResource{
+				Address: prefix + formatIndexKey(inst.IndexKey),
+				Type:    r.Type,
+				Values:  inst.Attributes,
+			})
+		}
+	}
+
+	return rv, nil
+}
+
+func formatIndexKey(key interface{}) string {
+	switch v := key.(type) {
+	case float64:
+		return fmt.Sprintf("[%d]", int64(v))
+	case string:
+		return fmt.Sprintf("[%q]", v)
+	default:
+		return ""
+	}
+}
+
+// migrationRule converts the azurerm resource into the arguments and the inputs of the tfgen command producing
+// the equivalent confidential resource
+type migrationRule struct {
+	group      string
+	command    string
+	targetType string
+	// movable whether the provider moves the state of the azurerm resource into the confidential resource.
+	// The resources that cannot be moved are removed from the state without being destroyed.
+	movable bool
+
+	convert func(r MigratedResource) (args []string, input, password []byte, err error)
+}
+
+var migrationRules = map[string]migrationRule{
+	res_keyvault.AzureRMKeyVaultSecretTypeName: {
+		group:      KeyVaultGroup,
+		command:    keyvault.SecretCommand,
+		targetType: "az-confidential_keyvault_secret",
+		movable:    true,
+		convert:    convertAzureRMKeyVaultSecret,
+	},
+	AzureRMKeyVaultCertificateTypeName: {
+		group:      KeyVaultGroup,
+		command:    keyvault.CertificateCommand,
+		targetType: "az-confidential_keyvault_certificate",
+		movable:    false,
+		convert:    convertAzureRMKeyVaultCertificate,
+	},
+	res_apim.AzureRMNamedValueTypeName: {
+		group:      ApimGroup,
+		command:    apim.NamedValueCommand,
+		targetType: "az-confidential_apim_named_value",
+		movable:    true,
+		convert:    convertAzureRMNamedValue,
+	},
+}
+
+func keyVaultObjectArgs(r MigratedResource, nameOption model.CLIOption) ([]string, error) {
+	coord := core.AzKeyVaultObjectVersionedCoordinate{}
+	if err := coord.FromId(r.stringValue("id")); err != nil {
+		return nil, fmt.Errorf("unexpected object id: %s", err.Error())
+	}
+
+	return []string{
+		keyvault.DestinationVaultCliOption.Opt(), coord.VaultName,
+		nameOption.Opt(), coord.Name,
+	}, nil
+}
+
+func convertAzureRMKeyVaultSecret(r MigratedResource) ([]string, []byte, []byte, error) {
+	value := r.stringValue("value")
+	if len(value) == 0 {
+		return nil, nil, nil, errors.New("the state does not contain the value of the secret")
+	}
+
+	args, err := keyVaultObjectArgs(r, keyvault.DestinationVaultSecretCliOption)
+	return args, []byte(value), nil, err
+}
+
+func convertAzureRMKeyVaultCertificate(r MigratedResource) ([]string, []byte, []byte, error) {
+	cert := r.firstBlock("certificate")
+	if cert == nil {
+		return nil, nil, nil, errors.New("the certificate was not imported from its contents; Key Vault generates it")
+	}
+
+	contents, _ := cert["contents"].(string)
+	password, _ := cert["password"].(string)
+
+	certData, decodeErr := base64.StdEncoding.DecodeString(strings.TrimSpace(contents))
+	if decodeErr != nil || len(certData) == 0 {
+		return nil, nil, nil, errors.New("the state does not contain the base64-encoded contents of the certificate")
+	}
+
+	args, err := keyVaultObjectArgs(r, keyvault.DestinationVaultCertificateCliOption)
+	return args, certData, []byte(password), err
+}
+
+func convertAzureRMNamedValue(r MigratedResource) ([]string, []byte, []byte, error) {
+	if r.firstBlock("value_from_key_vault") != nil {
+		return nil, nil, nil, errors.New("the named value reads its value from the Key Vault")
+	}
+
+	value := r.stringValue("value")
+	if len(value) == 0 {
+		return nil, nil, nil, errors.New("the state does not contain the value of the named value")
+	}
+
+	identity, err := res_apim.NamedValueIdentityOf(r.stringValue("id"), "")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unexpected named value id: %s", err.Error())
+	}
+
+	return []string{
+		apim.AzSubscriptionIdOptionCliOption.Opt(), identity.AzSubscriptionId,
+		apim.ResourceGroupNameCliOption.Opt(), identity.ResourceGroup,
+		apim.ServiceNameCliOption.Opt(), identity.ServiceName,
+		apim.NamedValueCliOption.Opt(), identity.Name,
+	}, []byte(value), nil, nil
+}
+
+var migratedNameExpr = regexp.MustCompile("[^a-zA-Z0-9_]+")
+
+// migratedResourceName derives the name of the confidential resource from the address of the azurerm
+// resource, e.g. module.app.azurerm_key_vault_secret.db["primary"] becomes app_db_primary.
+func migratedResourceName(r MigratedResource) string {
+	name := strings.Replace(r.Address, r.Type+".", "", 1)
+	name = strings.ReplaceAll(name, "module.", "")
+
+	return strings.Trim(migratedNameExpr.ReplaceAllString(name, "_"), "_")
+}
+
+// migrationInputReader supplies the value read from the state to the command. The public key is read using
+// the reader supplied to the migration.
+func migrationInputReader(base model.InputReader, input, password []byte) model.InputReader {
+	return func(prompt, fn string, base64Decode bool, multiline bool) ([]byte, error) {
+		switch prompt {
+		case PublicKeyPrompt:
+			return base(prompt, fn, base64Decode, multiline)
+		case keyvault.CertificatePasswordPrompt:
+			// PKCS12 bundles may be protected with an empty password
+			return password, nil
+		default:
+			return input, nil
+		}
+	}
+}
+
+func migrationBlock(rule migrationRule, r MigratedResource, name string) string {
+	if rule.movable {
+		return fmt.Sprintf(`# Remove %s from the configuration; the provider moves its state into the confidential resource
+moved {
+  from = %s
+  to   = %s.%s
+}`, r.Address, r.Address, rule.targetType, name)
+	}
+
+	return fmt.Sprintf(`# %s cannot be moved: it is removed from the state without being destroyed, and
+# the confidential resource imports the certificate as its new version. Remove it from the configuration.
+removed {
+  from = %s
+
+  lifecycle {
+    destroy = false
+  }
+}`, r.Address, r.Address)
+}
+
+func CreateMigrateArgParser() (*string, *string, *string, *flag.FlagSet) {
+	var stateFile, outputFile, outputDir string
+
+	migrateCmd := flag.NewFlagSet(MigrateCommand, flag.ExitOnError)
+	migrateCmd.StringVar(&stateFile,
+		StateCliOption.String(),
+		"",
+		"Output of `terraform show -json`, or the state JSON, listing the azurerm resources to migrate")
+
+	migrateCmd.StringVar(&outputFile,
+		OutputFileCliOption.String(),
+		"",
+		"Write Terraform code for all migrated resources into this file")
+
+	migrateCmd.StringVar(&outputDir,
+		OutputDirCliOption.String(),
+		"",
+		"Write Terraform code for each migrated resource into a separate file in this directory")
+
+	return &stateFile, &outputFile, &outputDir, migrateCmd
+}
+
+// RunMigrate converts the azurerm secrets, certificates and named values found in the state into the
+// confidential resources, encrypting their current values. The resources that cannot be migrated are reported
+// and left as they are.
+func RunMigrate(inputReader model.InputReader, cliArgs *EntryPointCLIArgs, args []string) (model.TerraformCode, error) {
+	stateFile, outputFile, outputDir, migrateCmd := CreateMigrateArgParser()
+	if parseErr := migrateCmd.Parse(args); parseErr != nil {
+		return "", parseErr
+	}
+
+	if len(*stateFile) == 0 {
+		return "", fmt.Errorf("option %s is required", StateCliOption.Opt())
+	}
+	if len(*outputFile) > 0 && len(*outputDir) > 0 {
+		return "", fmt.Errorf("options %s and %s are mutually exclusive", OutputFileCliOption.Opt(), OutputDirCliOption.Opt())
+	}
+
+	stateData, readErr := os.ReadFile(*stateFile)
+	if readErr != nil {
+		return "", fmt.Errorf("cannot read state: %s", readErr.Error())
+	}
+
+	outputs, skipped, migrateErr := ProcessMigration(inputReader, cliArgs, stateData)
+	if migrateErr != nil {
+		return "", migrateErr
+	}
+
+	// The skipped resources are reported to stderr, as the stdout may carry the generated Terraform code
+	for _, s := range skipped {
+		_, _ = fmt.Fprintf(os.Stderr, "Skipped %s\n", s)
+	}
+
+	return writeBatchOutput(outputs, *outputFile, *outputDir)
+}
+
+// ProcessMigration produces the Terraform code of the confidential resource, together with the moved or removed
+// block, for every azurerm resource in the state that can be migrated. The resources that cannot be migrated
+// are returned with the reason.
+func ProcessMigration(inputReader model.InputReader, cliArgs *EntryPointCLIArgs, stateData []byte) ([]batchOutput, []string, error) {
+	resources, parseErr := ParseMigratedResources(stateData)
+	if parseErr != nil {
+		return nil, nil, parseErr
+	}
+
+	commonKwp, commonKwpErr := buildContentWrappingParams(inputReader, cliArgs)
+	if commonKwpErr != nil {
+		return nil, nil, commonKwpErr
+	}
+
+	var rv []batchOutput
+	var skipped []string
+	seenNames := map[string]bool{}
+
+	for _, r := range resources {
+		rule, ok := migrationRules[r.Type]
+		if !ok {
+			continue
+		}
+
+		args, input, password, convertErr := rule.convert(r)
+		if convertErr != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %s", r.Address, convertErr.Error()))
+			continue
+		}
+
+		// Names are unique across resource types, as these also name the files where a file per resource is produced.
+		name := migratedResourceName(r)
+		for i := 2; seenNames[name]; i++ {
+			name = fmt.Sprintf("%s_%d", migratedResourceName(r), i)
+		}
+		seenNames[name] = true
+
+		kwp, kwpErr := buildContentWrappingParams(inputReader, cliArgs)
+		if kwpErr != nil {
+			return nil, nil, kwpErr
+		}
+		// The public key is shared by all resources; it is read only once.
+		kwp.LoadRsaPublicKey = commonKwp.LoadRsaPublicKey
+		kwp.ResourceBlockName = name
+
+		generator, genInitErr := makeGenerator(kwp, rule.group, rule.command, args)
+		if genInitErr != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Address, genInitErr.Error())
+		}
+
+		tfCode, _, genErr := generator(migrationInputReader(inputReader, input, password))
+		if genErr != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Address, genErr.Error())
+		}
+
+		rv = append(rv, batchOutput{
+			name:   name,
+			tfCode: model.TerraformCode(tfCode.String() + "\n\n" + migrationBlock(rule, r, name)),
+		})
+	}
+
+	if len(rv) == 0 {
+		return nil, skipped, errors.New("state does not contain azurerm resources that can be migrated")
+	}
+
+	return rv, skipped, nil
+}
@@ -0,0 +1,202 @@
+package tfgen
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/core/testkeymaterial"
+	"github.com/stretchr/testify/assert"
+)
+
+const unitTestShowOutput = `{
+  "format_version": "1.0",
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "azurerm_key_vault_secret.db",
+          "mode": "managed",
+          "type": "azurerm_key_vault_secret",
+          "name": "db",
+          "values": {
+            "id": "https://unit-test-vault.vault.azure.net/secrets/db-password/0123456789",
+            "name": "db-password",
+            "value": "plain-text-secret"
+          }
+        },
+        {
+          "address": "data.azurerm_key_vault_secret.lookup",
+          "mode": "data",
+          "type": "azurerm_key_vault_secret",
+          "name": "lookup",
+          "values": {
+            "id": "https://unit-test-vault.vault.azure.net/secrets/lookup/0123456789",
+            "value": "plain-text-lookup"
+          }
+        },
+        {
+          "address": "azurerm_key_vault_certificate.tls",
+          "mode": "managed",
+          "type": "azurerm_key_vault_certificate",
+          "name": "tls",
+          "values": {
+            "id": "https://unit-test-vault.vault.azure.net/certificates/tls/0123456789",
+            "certificate": [{"contents": "%CERT%", "password": ""}]
+          }
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.apim",
+          "resources": [
+            {
+              "address": "module.apim.azurerm_api_management_named_value.nv[0]",
+              "mode": "managed",
+              "type": "azurerm_api_management_named_value",
+              "name": "nv",
+              "index": 0,
+              "values": {
+                "id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/namedValues/nv",
+                "value": "plain-text-named-value",
+                "value_from_key_vault": []
+              }
+            },
+            {
+              "address": "module.apim.azurerm_api_management_named_value.kv",
+              "mode": "managed",
+              "type": "azurerm_api_management_named_value",
+              "name": "kv",
+              "values": {
+                "id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ApiManagement/service/svc/namedValues/kv",
+                "value_from_key_vault": [{"secret_id": "https://unit-test-vault.vault.azure.net/secrets/kv"}]
+              }
+            }
+          ]
+        }
+      ]
+    }
+  }
+}`
+
+const unitTestStateJson = `{
+  "version": 4,
+  "resources": [
+    {
+      "module": "module.app",
+      "mode": "managed",
+      "type": "azurerm_key_vault_secret",
+      "name": "db",
+      "instances": [
+        {
+          "index_key": "primary",
+          "attributes": {
+            "id": "https://unit-test-vault.vault.azure.net/secrets/db-primary/0123456789",
+            "value": "plain-text-primary"
+          }
+        }
+      ]
+    }
+  ]
+}`
+
+func givenStateFileContent(content string) string {
+	return strings.ReplaceAll(content, "%CERT%", base64.StdEncoding.EncodeToString(testkeymaterial.EphemeralCertificatePEM))
+}
+
+func givenStateFile(t *testing.T, content string) string {
+	fn := filepath.Join(t.TempDir(), "state.json")
+	assert.NoError(t, os.WriteFile(fn, []byte(givenStateFileContent(content)), 0600))
+
+	return fn
+}
+
+func Test_Migrate_ConvertsShowOutput(t *testing.T) {
+	_, mock := givenSetup(t)
+	stateFile := givenStateFile(t, unitTestShowOutput)
+
+	_, tfCode, _, err := MainEntryPointDispatch(mock.ReadInput, MigrateCommand, StateCliOption.Opt(), stateFile)
+	assert.NoError(t, err)
+
+	code := tfCode.String()
+	assert.Contains(t, code, `resource "az-confidential_keyvault_secret" "db"`)
+	assert.Contains(t, code, "from = azurerm_key_vault_secret.db\n  to   = az-confidential_keyvault_secret.db")
+	assert.Contains(t, code, `resource "az-confidential_apim_named_value" "apim_nv_0"`)
+	assert.Contains(t, code, "from = module.apim.azurerm_api_management_named_value.nv[0]\n  to   = az-confidential_apim_named_value.apim_nv_0")
+	assert.Contains(t, code, `resource "az-confidential_keyvault_certificate" "tls"`)
+	assert.Contains(t, code, "removed {\n  from = azurerm_key_vault_certificate.tls")
+	assert.Contains(t, code, "unit-test-vault")
+
+	assert.NotContains(t, code, "lookup")
+	assert.NotContains(t, code, "module.apim.azurerm_api_management_named_value.kv")
+	assert.NotContains(t, code, "plain-text")
+}
+
+func Test_Migrate_ReportsResourcesThatCannotBeMigrated(t *testing.T) {
+	_, mock := givenSetup(t)
+	cliArgs, _ := CreateCommonCLIArgs()
+
+	outputs, skipped, err := ProcessMigration(mock.ReadInput, cliArgs, []byte(givenStateFileContent(unitTestShowOutput)))
+	assert.NoError(t, err)
+	assert.Len(t, outputs, 3)
+	assert.Equal(t, []string{"module.apim.azurerm_api_management_named_value.kv: the named value reads its value from the Key Vault"}, skipped)
+}
+
+func givenCapturedOutput(t *testing.T, f **os.File) func() string {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	prev := *f
+	*f = w
+
+	return func() string {
+		_ = w.Close()
+		*f = prev
+
+		data, readErr := io.ReadAll(r)
+		assert.NoError(t, readErr)
+		return string(data)
+	}
+}
+
+func Test_Migrate_ReportsSkippedResourcesToStderr(t *testing.T) {
+	_, mock := givenSetup(t)
+	stateFile := givenStateFile(t, unitTestShowOutput)
+
+	stdout := givenCapturedOutput(t, &os.Stdout)
+	stderr := givenCapturedOutput(t, &os.Stderr)
+
+	_, _, _, err := MainEntryPointDispatch(mock.ReadInput, MigrateCommand, StateCliOption.Opt(), stateFile)
+
+	errText := stderr()
+	outText := stdout()
+
+	assert.NoError(t, err)
+	assert.Contains(t, errText, "Skipped module.apim.azurerm_api_management_named_value.kv")
+	assert.NotContains(t, outText, "Skipped")
+}
+
+func Test_Migrate_ConvertsStateJson(t *testing.T) {
+	_, mock := givenSetup(t)
+	stateFile := givenStateFile(t, unitTestStateJson)
+	outDir := filepath.Join(t.TempDir(), "out")
+
+	_, _, _, err := MainEntryPointDispatch(mock.ReadInput, MigrateCommand, StateCliOption.Opt(), stateFile, OutputDirCliOption.Opt(), outDir)
+	assert.NoError(t, err)
+
+	data, readErr := os.ReadFile(filepath.Join(outDir, "app_db_primary.tf"))
+	assert.NoError(t, readErr)
+	assert.Contains(t, string(data), `from = module.app.azurerm_key_vault_secret.db["primary"]`)
+	assert.Contains(t, string(data), "db-primary")
+}
+
+func Test_Migrate_FailsWhereNothingCanBeMigrated(t *testing.T) {
+	_, mock := givenSetup(t)
+	stateFile := givenStateFile(t, `{"version": 4, "resources": []}`)
+
+	_, _, _, err := MainEntryPointDispatch(mock.ReadInput, MigrateCommand, StateCliOption.Opt(), stateFile)
+	assert.ErrorContains(t, err, "can be migrated")
+}
@@ -12,6 +12,53 @@ type ContentWrappingParams struct {
 
 	WrappingKeyCoordinate WrappingKey
 	LockPlacement         bool
+
+	// ResourceBlockName overrides the name of the Terraform block that a command would output by default.
+	ResourceBlockName string
+
+	// Description of the Terraform block which the command outputs. Commands set it when parsing their arguments.
+	Description ResourceDescription
+}
+
+// ResourceDescription describes the Terraform block produced by a command for the consumption by other tools.
+type ResourceDescription struct {
+	Address     string
+	Destination map[string]string
+
+	// ObjectType and Protection are the header with which the command encrypted the ciphertext.
+	ObjectType string
+	Protection core.SecondaryProtectionParameters
+}
+
+// DescribeResource records the address of the Terraform block and the destination coordinates which were
+// supplied on the command line.
+func (kwp *ContentWrappingParams) DescribeResource(address string, destination map[string]string) {
+	dest := make(map[string]string, len(destination))
+	for k, v := range destination {
+		if len(v) > 0 {
+			dest[k] = v
+		}
+	}
+
+	kwp.Description = ResourceDescription{
+		Address:     address,
+		Destination: dest,
+	}
+}
+
+// DescribeCiphertext records the object type and the protection parameters, including the placement lock,
+// with which the ciphertext was encrypted.
+func (kwp *ContentWrappingParams) DescribeCiphertext(objectType string, params core.SecondaryProtectionParameters) {
+	kwp.Description.ObjectType = objectType
+	kwp.Description.Protection = params
+}
+
+// GetResourceBlockName returns the name of the Terraform block to output, unless the default is overridden.
+func (kwp *ContentWrappingParams) GetResourceBlockName(defaultName string) string {
+	if len(kwp.ResourceBlockName) > 0 {
+		return kwp.ResourceBlockName
+	}
+	return defaultName
 }
 
 func (kwp *ContentWrappingParams) GetMetadataForTerraform(objName, destExp string) VersionedConfidentialMetadataTFCode {
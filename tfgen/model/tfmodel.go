@@ -74,7 +74,7 @@ type BaseTerraformCodeModel struct {
 
 func NewBaseTerraformCodeModel(kwp *ContentWrappingParams, blockName, objectName, destArg string) BaseTerraformCodeModel {
 	return BaseTerraformCodeModel{
-		TFBlockName: blockName,
+		TFBlockName: kwp.GetResourceBlockName(blockName),
 		// The Terraform resources should be provided in the Heredoc
 		// style for added readability.
 		EncryptedContent:         NewStringTerraformFieldHeredocExpression(),
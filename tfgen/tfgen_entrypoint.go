@@ -16,6 +16,7 @@ import (
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/cmdgroups/keyvault"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/io"
 	"github.com/aliakseiyanchuk/terraform-provider-az-confidential/tfgen/model"
+	"github.com/google/uuid"
 )
 
 const (
@@ -34,15 +35,24 @@ const (
 	WrappingKeyVersion           model.CLIOption = "wrapping-key-version"
 	PublicKeyCliOption           model.CLIOption = "pubkey"
 	ProviderConstraintsCliOption model.CLIOption = "provider-constraints"
+	ProviderAllOfCliOption       model.CLIOption = "provider-all-of"
+	ProviderAnyOfCliOption       model.CLIOption = "provider-any-of"
+	ProviderNoneOfCliOption      model.CLIOption = "provider-none-of"
+	AzureTenantsCliOption        model.CLIOption = "azure-tenants"
+	AzureSubscriptionsCliOption  model.CLIOption = "azure-subscriptions"
 	LockDestinationCliOption     model.CLIOption = "lock-destination"
+	PlacementGlobCliOption       model.CLIOption = "placement-glob"
+	PlacementRegexCliOption      model.CLIOption = "placement-regex"
 	TimeToCreateCliOption        model.CLIOption = "time-to-create"
 	NoCreateLimitCliOption       model.CLIOption = "no-create-limit"
+	NotBeforeCliOption           model.CLIOption = "not-before"
 	DaysToExpireCliOption        model.CLIOption = "days-to-expire"
 	NoExpiryLimitCliOption       model.CLIOption = "no-expiry-limit"
 	NumberOfTimesUsesOption      model.CLIOption = "num-uses"
 	CreateOnceOption             model.CLIOption = "create-once"
 	NoUsageLimitOption           model.CLIOption = "no-usage-limit"
 	CiphertextOnlyOption         model.CLIOption = "ciphertext-only"
+	OutputFormatOption           model.CLIOption = "output"
 )
 
 var CommandGroups []string
@@ -52,11 +62,20 @@ type EntryPointCLIArgs struct {
 	RSAPublicKeyFile      string
 
 	ProviderConstraints string
+	ProviderAllOf       repeatedOption
+	ProviderAnyOf       repeatedOption
+	ProviderNoneOf      repeatedOption
+	AzureTenants        string
+	AzureSubscriptions  string
 	ConstraintTarget    bool
+	PlacementGlobs      repeatedOption
+	PlacementRegexes    repeatedOption
 
 	PrintCiphertextOnly bool
+	OutputFormat        string
 
 	CreateLimit time.Duration
+	NotBefore   string
 	ExpiryDays  int
 	NumUses     int
 	CreateOnce  bool
@@ -66,6 +85,18 @@ type EntryPointCLIArgs struct {
 	NoUsageLimit  bool
 }
 
+// repeatedOption the option that can be given several times on the command line.
+type repeatedOption []string
+
+func (r *repeatedOption) String() string {
+	return strings.Join(*r, ", ")
+}
+
+func (r *repeatedOption) Set(v string) error {
+	*r = append(*r, v)
+	return nil
+}
+
 func CreateCommonCLIArgs() (*EntryPointCLIArgs, *flag.FlagSet) {
 	rv := &EntryPointCLIArgs{}
 
@@ -99,6 +130,38 @@ func CreateCommonCLIArgs() (*EntryPointCLIArgs, *flag.FlagSet) {
 		"Require the provider deploying a resource from the ciphertext to be configured with the specified provider label. Use comma to separate individual labels",
 	)
 
+	baseFlags.Var(&rv.ProviderAllOf,
+		ProviderAllOfCliOption.String(),
+		"Require the provider to be configured with all of the specified provider labels. Use comma to separate individual labels. "+
+			"The option can be given several times.",
+	)
+
+	baseFlags.Var(&rv.ProviderAnyOf,
+		ProviderAnyOfCliOption.String(),
+		"Require the provider to be configured with at least one of the specified provider labels. Use comma to separate individual labels. "+
+			"The option can be given several times; each occurrence must be satisfied.",
+	)
+
+	baseFlags.Var(&rv.ProviderNoneOf,
+		ProviderNoneOfCliOption.String(),
+		"Require the provider to be configured with none of the specified provider labels. Use comma to separate individual labels. "+
+			"The option can be given several times.",
+	)
+
+	baseFlags.StringVar(&rv.AzureTenants,
+		AzureTenantsCliOption.String(),
+		"",
+		"Allow the ciphertext to be used only by the provider whose credential belongs to one of these Azure tenants. "+
+			"Use comma to separate individual tenant ids",
+	)
+
+	baseFlags.StringVar(&rv.AzureSubscriptions,
+		AzureSubscriptionsCliOption.String(),
+		"",
+		"Allow the ciphertext to be used only by the provider configured with one of these Azure subscriptions. "+
+			"Use comma to separate individual subscription ids",
+	)
+
 	baseFlags.BoolVar(&rv.ConstraintTarget,
 		LockDestinationCliOption.String(),
 		false,
@@ -107,6 +170,18 @@ func CreateCommonCLIArgs() (*EntryPointCLIArgs, *flag.FlagSet) {
 			"vault name and key name. Exact option depend on the resource type.",
 	)
 
+	baseFlags.Var(&rv.PlacementGlobs,
+		PlacementGlobCliOption.String(),
+		"Allow the ciphertext to create Azure resources only at the destinations matching this glob pattern, e.g. "+
+			"az-c-keyvault://kv-prod-*@secrets=app-*. The option can be given several times.",
+	)
+
+	baseFlags.Var(&rv.PlacementRegexes,
+		PlacementRegexCliOption.String(),
+		"Allow the ciphertext to create Azure resources only at the destinations matching this regular expression, e.g. "+
+			"az-c-keyvault://kv-prod-[0-9]+@secrets=app-.*. The option can be given several times.",
+	)
+
 	baseFlags.DurationVar(&rv.CreateLimit,
 		TimeToCreateCliOption.String(),
 		time.Hour*24*3,
@@ -119,6 +194,12 @@ func CreateCommonCLIArgs() (*EntryPointCLIArgs, *flag.FlagSet) {
 		"Removes the timing limit on create from the ciphertext",
 	)
 
+	baseFlags.StringVar(&rv.NotBefore,
+		NotBeforeCliOption.String(),
+		"",
+		"Date (RFC3339, e.g. 2026-01-31T00:00:00Z) before which the ciphertext cannot be used to create or update Azure resources",
+	)
+
 	baseFlags.IntVar(&rv.ExpiryDays,
 		DaysToExpireCliOption.String(),
 		365,
@@ -155,6 +236,13 @@ func CreateCommonCLIArgs() (*EntryPointCLIArgs, *flag.FlagSet) {
 		"Output only ciphertext (i.e. do not output associated Terraform code template)",
 	)
 
+	baseFlags.StringVar(&rv.OutputFormat,
+		OutputFormatOption.String(),
+		OutputFormatHcl,
+		"Output format: hcl for Terraform code, or json for a JSON document containing ciphertext, its header, "+
+			"suggested resource address, destination coordinates, and Terraform code",
+	)
+
 	return rv, baseFlags
 }
 
@@ -167,11 +255,73 @@ func buildContentWrappingParams(ioReader model.InputReader, cliArgs *EntryPointC
 		}
 	}
 
+	for _, g := range []struct {
+		opt    model.CLIOption
+		groups repeatedOption
+		newFn  func(labels ...string) core.ProviderConstraint
+	}{
+		{ProviderAllOfCliOption, cliArgs.ProviderAllOf, core.AllOfProviderConstraint},
+		{ProviderAnyOfCliOption, cliArgs.ProviderAnyOf, core.AnyOfProviderConstraint},
+		{ProviderNoneOfCliOption, cliArgs.ProviderNoneOf, core.NoneOfProviderConstraint},
+	} {
+		for _, group := range g.groups {
+			labels := strings.Split(group, ",")
+			if core.Contains("", labels) || strings.Contains(group, "|") {
+				return nil, fmt.Errorf("option %s requires comma-separated non-empty labels without '|' character: %s", g.opt.Opt(), group)
+			}
+			providerConstraints = append(providerConstraints, g.newFn(labels...))
+		}
+	}
+
+	for _, b := range []struct {
+		opt   model.CLIOption
+		ids   string
+		newFn func(id string) core.ProviderConstraint
+	}{
+		{AzureTenantsCliOption, cliArgs.AzureTenants, core.AzureTenantConstraint},
+		{AzureSubscriptionsCliOption, cliArgs.AzureSubscriptions, core.AzureSubscriptionConstraint},
+	} {
+		if len(b.ids) == 0 {
+			continue
+		}
+		for _, id := range strings.Split(b.ids, ",") {
+			if _, uuidErr := uuid.Parse(id); uuidErr != nil {
+				return nil, fmt.Errorf("option %s requires comma-separated Azure ids: %s is not a valid id", b.opt.Opt(), id)
+			}
+			providerConstraints = append(providerConstraints, b.newFn(id))
+		}
+	}
+
+	var placementConstraints []core.PlacementConstraint
+	for _, g := range cliArgs.PlacementGlobs {
+		placementConstraints = append(placementConstraints, core.PlacementConstraint(core.PlacementGlobPrefix+g))
+	}
+	for _, r := range cliArgs.PlacementRegexes {
+		placementConstraints = append(placementConstraints, core.PlacementConstraint(core.PlacementRegexPrefix+r))
+	}
+	if len(placementConstraints) > 0 {
+		if cliArgs.ConstraintTarget {
+			return nil, fmt.Errorf("option %s cannot be used together with placement patterns", LockDestinationCliOption.Opt())
+		}
+		if _, parseErr := core.ParsePlacementConstraints(placementConstraints); parseErr != nil {
+			return nil, fmt.Errorf("invalid placement pattern: %s", parseErr.Error())
+		}
+	}
+
 	createLimit := int64(0)
 	if !cliArgs.NoCreateLimit {
 		createLimit = time.Now().Add(cliArgs.CreateLimit).Unix()
 	}
 
+	notBefore := int64(0)
+	if len(cliArgs.NotBefore) > 0 {
+		if t, parseErr := time.Parse(time.RFC3339, cliArgs.NotBefore); parseErr != nil {
+			return nil, fmt.Errorf("option %s requires RFC3339 date: %s", NotBeforeCliOption.Opt(), parseErr.Error())
+		} else {
+			notBefore = t.Unix()
+		}
+	}
+
 	expiryLimit := int64(0)
 	if !cliArgs.NoExpireLimit {
 		expiryLimit = time.Now().Add(time.Hour * time.Duration(24*cliArgs.ExpiryDays)).Unix()
@@ -187,8 +337,9 @@ func buildContentWrappingParams(ioReader model.InputReader, cliArgs *EntryPointC
 	rv := &model.ContentWrappingParams{
 		SecondaryProtectionParameters: core.SecondaryProtectionParameters{
 			ProviderConstraints:  providerConstraints,
-			PlacementConstraints: nil,
+			PlacementConstraints: placementConstraints,
 			CreateLimit:          createLimit,
+			NotBefore:            notBefore,
 			Expiry:               expiryLimit,
 			NumUses:              numUses,
 		},
@@ -234,6 +385,65 @@ func MainEntryPointDispatch(inputReader model.InputReader, allCmdArgs ...string)
 		return nil, "", core.EncryptedMessage{}, parseErr
 	}
 
+	if cliArgs.OutputFormat != OutputFormatHcl && cliArgs.OutputFormat != OutputFormatJson {
+		fmt.Printf("Unsupported output format: %s\n", cliArgs.OutputFormat)
+		return nil, "", core.EncryptedMessage{}, errors.New("unsupported output format")
+	}
+	if cliArgs.OutputFormat == OutputFormatJson && cliArgs.PrintCiphertextOnly {
+		fmt.Printf("Options %s and %s cannot be used together\n", CiphertextOnlyOption.Opt(), OutputFormatOption.Opt())
+		return nil, "", core.EncryptedMessage{}, errors.New("ciphertext-only output cannot be formatted as JSON")
+	}
+
+	if baseFlags.Arg(0) == InspectCommand {
+		report, err := RunInspect(inputReader, baseFlags.Args()[1:])
+		if err != nil {
+			fmt.Println("Cannot inspect the ciphertext:")
+			fmt.Println(err.Error())
+		} else {
+			fmt.Println(report)
+		}
+		return cliArgs, "", core.EncryptedMessage{}, err
+	}
+
+	if baseFlags.Arg(0) == KeygenCommand {
+		report, err := RunKeygen(inputReader, baseFlags.Args()[1:])
+		if err != nil {
+			fmt.Println("Cannot generate the key pair:")
+			fmt.Println(err.Error())
+		} else {
+			fmt.Println(report)
+		}
+		return cliArgs, "", core.EncryptedMessage{}, err
+	}
+
+	if baseFlags.Arg(0) == BatchCommand {
+		if cliArgs.PrintCiphertextOnly || cliArgs.OutputFormat == OutputFormatJson {
+			fmt.Printf("Options %s and %s cannot be used with the batch command\n", CiphertextOnlyOption.Opt(), OutputFormatOption.Opt())
+			return nil, "", core.EncryptedMessage{}, errors.New("ciphertext-only or JSON output is not supported in batch mode")
+		}
+
+		tfCode, err := RunBatch(inputReader, cliArgs, baseFlags.Args()[1:])
+		if err != nil {
+			fmt.Println("Cannot process the batch manifest:")
+			fmt.Println(err.Error())
+		}
+		return cliArgs, tfCode, core.EncryptedMessage{}, err
+	}
+
+	if baseFlags.Arg(0) == MigrateCommand {
+		if cliArgs.PrintCiphertextOnly || cliArgs.OutputFormat == OutputFormatJson {
+			fmt.Printf("Options %s and %s cannot be used with the migrate command\n", CiphertextOnlyOption.Opt(), OutputFormatOption.Opt())
+			return nil, "", core.EncryptedMessage{}, errors.New("ciphertext-only or JSON output is not supported in migrate mode")
+		}
+
+		tfCode, err := RunMigrate(inputReader, cliArgs, baseFlags.Args()[1:])
+		if err != nil {
+			fmt.Println("Cannot migrate the state:")
+			fmt.Println(err.Error())
+		}
+		return cliArgs, tfCode, core.EncryptedMessage{}, err
+	}
+
 	if len(baseFlags.Args()) < 2 {
 		fmt.Println("Missing command group and command")
 		printSubcommandSelectionHelp(baseFlags)
@@ -251,22 +461,14 @@ func MainEntryPointDispatch(inputReader model.InputReader, allCmdArgs ...string)
 	cmd := baseFlags.Args()[1]
 	cmdArgs := baseFlags.Args()[2:]
 
-	var generator model.SubCommandExecution
-	var generatorInitErr error
-
-	switch cmdGroup {
-	case GeneralGroup:
-		generator, generatorInitErr = general.EntryPoint(kwp, cmd, cmdArgs)
-	case KeyVaultGroup:
-		generator, generatorInitErr = keyvault.EntryPoint(kwp, cmd, cmdArgs)
-	case ApimGroup:
-		generator, generatorInitErr = apim.EntryPoint(kwp, cmd, cmdArgs)
-	default:
+	if !isKnownCommandGroup(cmdGroup) {
 		_, _ = fmt.Printf("Unknown subcommand: %s", cmdGroup)
 		printSubcommandSelectionHelp(baseFlags)
 		os.Exit(1)
 	}
 
+	generator, generatorInitErr := makeGenerator(kwp, cmdGroup, cmd, cmdArgs)
+
 	if generatorInitErr != nil {
 		// Error message must be printed by the sub-command
 		fmt.Println("Cannot produce template:")
@@ -279,13 +481,37 @@ func MainEntryPointDispatch(inputReader model.InputReader, allCmdArgs ...string)
 		// Error message must be printed by the sub-command
 		fmt.Println("Cannot produce template:")
 		fmt.Println(err.Error())
+	} else if cliArgs.OutputFormat == OutputFormatJson {
+		// The JSON document is returned in place of the Terraform code; the code is embedded in it.
+		tfCode, err = RenderJsonOutput(kwp, tfCode, en)
 	}
 
 	return cliArgs, tfCode, en, err
 }
 
+func isKnownCommandGroup(cmdGroup string) bool {
+	return cmdGroup == GeneralGroup || cmdGroup == KeyVaultGroup || cmdGroup == ApimGroup
+}
+
+func makeGenerator(kwp *model.ContentWrappingParams, cmdGroup, cmd string, cmdArgs []string) (model.SubCommandExecution, error) {
+	switch cmdGroup {
+	case GeneralGroup:
+		return general.EntryPoint(kwp, cmd, cmdArgs)
+	case KeyVaultGroup:
+		return keyvault.EntryPoint(kwp, cmd, cmdArgs)
+	case ApimGroup:
+		return apim.EntryPoint(kwp, cmd, cmdArgs)
+	default:
+		return nil, fmt.Errorf("unknown command group: %s", cmdGroup)
+	}
+}
+
 func printSubcommandSelectionHelp(f *flag.FlagSet) {
-	fmt.Println("Usage: tfgen [<standard options>] <group> <subcommand> [<args>]")
+	fmt.Println("Usage: tfgen [<standard options>] [-output hcl|json] <group> <subcommand> [<args>]")
+	fmt.Println("       tfgen inspect [-private-key <file>] [-format text|json] [-ciphertext <file> | <.tf files or directories>]")
+	fmt.Println("       tfgen keygen -private-key-file <file> -public-key-file <file> [-bits 3072|4096] [-encrypt] [-jwk-file <file>]")
+	fmt.Println("       tfgen [<standard options>] batch -manifest <file.yaml> [-output-file <file.tf> | -output-dir <dir>]")
+	fmt.Println("       tfgen [<standard options>] migrate -state <terraform show -json output> [-output-file <file.tf> | -output-dir <dir>]")
 	fmt.Println("Possible command groups are:")
 	for _, cmd := range CommandGroups {
 		fmt.Printf("- %s", cmd)
@@ -315,10 +541,10 @@ func MainEntryPoint() {
 
 	if cliArgs.PrintCiphertextOnly {
 		fld := model.FoldString(en.ToBase64PEM(), 80)
-		for _, v := range strings.Join(fld, "\n") {
+		for _, v := range fld {
 			fmt.Println(v)
 		}
-	} else {
+	} else if len(tfCode) > 0 {
 		fmt.Println(tfCode)
 	}
 